@@ -147,6 +147,24 @@ if !errorlevel! neq 0 (
 )
 call :print_success "Template folder successfully copied"
 
+:: Copy layout profiles, custom profiles already in the build are kept
+call :print_step "Copying layouts folder"
+xcopy /q /E /I /y config\layouts build\config\layouts > nul
+if !errorlevel! neq 0 (
+    call :print_error "Failed to copy layouts folder"
+    exit /b 1
+)
+call :print_success "Layouts folder successfully copied"
+
+:: Copy config profiles, profiles already in the build are kept
+call :print_step "Copying profiles folder"
+xcopy /q /E /I /y config\profiles build\config\profiles > nul
+if !errorlevel! neq 0 (
+    call :print_error "Failed to copy profiles folder"
+    exit /b 1
+)
+call :print_success "Profiles folder successfully copied"
+
 :: Copy README
 call :print_step "Copying README.md"
 copy README.md build > nul
@@ -13,6 +13,8 @@ mkdir build\config > NUL || goto :error
 copy config\koolo.yaml.dist build\config\koolo.yaml  > NUL || goto :error
 copy config\Settings.json build\config\Settings.json  > NUL || goto :error
 xcopy /q /E /I /y config\template build\config\template  > NUL || goto :error
+xcopy /q /E /I /y config\layouts build\config\layouts  > NUL || goto :error
+xcopy /q /E /I /y config\profiles build\config\profiles  > NUL || goto :error
 xcopy /q /E /I /y tools build\tools > NUL || goto :error
 xcopy /q /y README.md build > NUL || goto :error
 
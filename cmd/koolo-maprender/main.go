@@ -0,0 +1,186 @@
+// Command koolo-maprender renders stored map data to PNG, so pathing issues can be checked without launching the game.
+//
+// Map data is read from the map cache written by koolo, or from JSON fixtures:
+//
+//	koolo-maprender -cache cache/maps -seed 1234 -difficulty hell -area 101 -from 17562,8069 -to 17566,8370
+//	koolo-maprender -fixture maps/1234_hell.json -seed 1234 -difficulty hell -area 100 -merge 101
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/game/map_client"
+	"github.com/hectorgimenez/koolo/internal/pather"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+func main() {
+	cacheDir := flag.String("cache", "", "map cache directory written by koolo")
+	fixture := flag.String("fixture", "", "map data JSON fixture, file or directory")
+	seed := flag.String("seed", "", "map seed")
+	diff := flag.String("difficulty", difficulty.Normal, "normal, nightmare or hell")
+	areaFlag := flag.String("area", "", "area ID or name to render")
+	mergeFlag := flag.String("merge", "", "adjacent area ID or name rendered together with the area")
+	fromFlag := flag.String("from", "", "path start in world coordinates, x,y")
+	toFlag := flag.String("to", "", "path goal in world coordinates, x,y")
+	out := flag.String("out", "", "output PNG file, defaults to <seed>_<difficulty>_<area>.png")
+	list := flag.Bool("list", false, "list the areas available in the map data")
+	jps := flag.Bool("jps", false, "find the path with Jump Point Search instead of A*")
+	flag.Parse()
+
+	provider, err := newProvider(*cacheDir, *fixture)
+	if err != nil {
+		log.Fatalf("Error: %s", err.Error())
+	}
+
+	mapData, err := provider.GetMapData(*seed, difficulty.Difficulty(*diff))
+	if err != nil {
+		log.Fatalf("Error loading map data: %s", err.Error())
+	}
+	areas := game.AreasFromMapData(mapData)
+
+	if *list {
+		ids := make([]area.ID, 0, len(areas))
+		for id := range areas {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		for _, id := range ids {
+			fmt.Printf("%d\t%s\n", id, areas[id].Name)
+		}
+		return
+	}
+
+	a, err := findArea(areas, *areaFlag)
+	if err != nil {
+		log.Fatalf("Error: %s", err.Error())
+	}
+
+	grid := a.Grid
+	render := pather.MapRender{
+		Rooms:   a.Rooms,
+		Exits:   a.AdjacentLevels,
+		NPCs:    a.NPCs,
+		Objects: a.Objects,
+	}
+	if *mergeFlag != "" {
+		merged, err := findArea(areas, *mergeFlag)
+		if err != nil {
+			log.Fatalf("Error: %s", err.Error())
+		}
+		grid = pather.MergeAreaGrids(a, merged)
+		render.Rooms = append(slices.Clone(render.Rooms), merged.Rooms...)
+		render.Exits = append(slices.Clone(render.Exits), merged.AdjacentLevels...)
+		render.NPCs = append(slices.Clone(render.NPCs), merged.NPCs...)
+		render.Objects = append(slices.Clone(render.Objects), merged.Objects...)
+	}
+
+	if *fromFlag != "" || *toFlag != "" {
+		from, err := parsePosition(*fromFlag)
+		if err != nil {
+			log.Fatalf("Error parsing -from: %s", err.Error())
+		}
+		to, err := parsePosition(*toFlag)
+		if err != nil {
+			log.Fatalf("Error parsing -to: %s", err.Error())
+		}
+
+		render.Route = true
+		render.From, render.To = grid.RelativePosition(from), grid.RelativePosition(to)
+		alg := astar.AlgorithmAStar
+		if *jps {
+			alg = astar.AlgorithmJPS
+		}
+		path, distance, found := astar.Calculate(alg, grid, render.From, render.To)
+		if found {
+			fmt.Printf("Path found, distance: %d\n", distance)
+		} else {
+			fmt.Println("Path not found")
+		}
+		render.Path = path
+	}
+
+	if *out == "" {
+		*out = fmt.Sprintf("%s_%s_%d.png", *seed, *diff, a.Area)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("Error creating output file: %s", err.Error())
+	}
+	defer f.Close()
+
+	if err = png.Encode(f, pather.RenderMap(grid, render)); err != nil {
+		log.Fatalf("Error writing PNG: %s", err.Error())
+	}
+	fmt.Printf("Map rendered to %s\n", *out)
+}
+
+// cacheOnlyProvider is the generator behind the cache, map data is never generated here
+type cacheOnlyProvider struct{}
+
+func (cacheOnlyProvider) GetMapData(string, difficulty.Difficulty) (map_client.MapData, error) {
+	return nil, errors.New("map data not found in cache")
+}
+
+func newProvider(cacheDir, fixture string) (map_client.MapDataProvider, error) {
+	switch {
+	case cacheDir != "" && fixture != "":
+		return nil, errors.New("-cache and -fixture can not be used together")
+	case cacheDir != "":
+		return map_client.NewCachedProvider(cacheOnlyProvider{}, cacheDir, 0), nil
+	case fixture != "":
+		return map_client.NewFixtureProvider(fixture), nil
+	}
+
+	return nil, errors.New("-cache or -fixture is required")
+}
+
+func findArea(areas map[area.ID]game.AreaData, s string) (game.AreaData, error) {
+	if s == "" {
+		return game.AreaData{}, errors.New("-area is required, use -list to see the available ones")
+	}
+
+	if id, err := strconv.Atoi(s); err == nil {
+		if a, found := areas[area.ID(id)]; found {
+			return a, nil
+		}
+	}
+
+	for _, a := range areas {
+		if strings.EqualFold(a.Name, s) {
+			return a, nil
+		}
+	}
+
+	return game.AreaData{}, fmt.Errorf("area %s not found in map data", s)
+}
+
+func parsePosition(s string) (data.Position, error) {
+	x, y, found := strings.Cut(s, ",")
+	if !found {
+		return data.Position{}, fmt.Errorf("expected x,y, got %q", s)
+	}
+
+	px, err := strconv.Atoi(strings.TrimSpace(x))
+	if err != nil {
+		return data.Position{}, err
+	}
+	py, err := strconv.Atoi(strings.TrimSpace(y))
+	if err != nil {
+		return data.Position{}, err
+	}
+
+	return data.Position{X: px, Y: py}, nil
+}
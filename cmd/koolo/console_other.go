@@ -0,0 +1,6 @@
+//go:build !windows
+
+package main
+
+// attachConsole does nothing, output already goes to the terminal
+func attachConsole() {}
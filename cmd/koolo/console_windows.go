@@ -0,0 +1,22 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+
+	"github.com/hectorgimenez/koolo/internal/utils/winproc"
+)
+
+// attachConsole sends the output to the console koolo was started from, the binary is built as a GUI app and has no
+// console of its own
+func attachConsole() {
+	if r, _, _ := winproc.AttachConsole.Call(winproc.ATTACH_PARENT_PROCESS); r == 0 {
+		return
+	}
+
+	if out, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0); err == nil {
+		os.Stdout = out
+		os.Stderr = out
+	}
+}
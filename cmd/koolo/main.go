@@ -6,6 +6,7 @@ import (
 	"log"
 	"log/slog"
 	_ "net/http/pprof"
+	"os"
 	"runtime/debug"
 
 	sloggger "github.com/hectorgimenez/koolo/cmd/koolo/log"
@@ -16,12 +17,14 @@ import (
 	"github.com/hectorgimenez/koolo/internal/remote/telegram"
 	"github.com/hectorgimenez/koolo/internal/server"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/hectorgimenez/koolo/internal/utils/winproc"
-	"github.com/inkeliz/gowebview"
 	"golang.org/x/sync/errgroup"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:]))
+	}
+
 	err := config.Load()
 	if err != nil {
 		utils.ShowDialog("Error loading configuration", err.Error())
@@ -35,6 +38,8 @@ func main() {
 	}
 	defer sloggger.FlushLog()
 
+	unlockSecrets(logger)
+
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("fatal error detected, Koolo will close with the following error: %v\n Stacktrace: %s", r, debug.Stack())
@@ -49,12 +54,19 @@ func main() {
 
 	g, ctx := errgroup.WithContext(ctx)
 
-	winproc.SetProcessDpiAware.Call() // Set DPI awareness to be able to read the correct scale and show the window correctly
+	setProcessDpiAware()
 
 	eventListener := event.NewListener(logger)
 	manager := bot.NewSupervisorManager(logger, eventListener)
 	scheduler := bot.NewScheduler(manager, logger)
 	go scheduler.Start()
+
+	// Config and pickit changes on disk are applied to the running supervisors between games
+	configWatcher := bot.NewConfigWatcher(manager, logger)
+	g.Go(func() error {
+		return configWatcher.Start(ctx)
+	})
+
 	srv, err := server.New(logger, manager)
 	if err != nil {
 		log.Fatalf("Error starting local server: %s", err.Error())
@@ -62,55 +74,20 @@ func main() {
 
 	g.Go(func() error {
 		defer cancel()
-		displayScale := config.GetCurrentDisplayScale()
-		w, err := gowebview.New(&gowebview.Config{URL: "http://localhost:8087", WindowConfig: &gowebview.WindowConfig{
-			Title: "Koolo",
-			Size: &gowebview.Point{
-				X: int64(1280 * displayScale),
-				Y: int64(720 * displayScale),
-			},
-		}})
-		if err != nil {
-			w.Destroy()
-			return fmt.Errorf("error creating webview: %w", err)
-		}
-
-		w.SetSize(&gowebview.Point{
-			X: int64(1280 * displayScale),
-			Y: int64(720 * displayScale),
-		}, gowebview.HintFixed)
-
-		defer w.Destroy()
-		w.Run()
-
-		return nil
+		return runWebView(ctx)
 	})
 
 	// Discord Bot initialization
 	if config.Koolo.Discord.Enabled {
-		discordBot, err := discord.NewBot(config.Koolo.Discord.Token, config.Koolo.Discord.ChannelID, manager)
-		if err != nil {
-			logger.Error("Discord could not been initialized", slog.Any("error", err))
-			return
-		}
-
-		eventListener.Register(discordBot.Handle)
-		g.Go(func() error {
-			return discordBot.Start(ctx)
+		startRemoteBot(ctx, g, logger, eventListener, "Discord", config.Koolo.Discord.Token, func(token string) (remoteBot, error) {
+			return discord.NewBot(token, config.Koolo.Discord.ChannelID, manager)
 		})
 	}
 
 	// Telegram Bot initialization
 	if config.Koolo.Telegram.Enabled {
-		telegramBot, err := telegram.NewBot(config.Koolo.Telegram.Token, config.Koolo.Telegram.ChatID, logger)
-		if err != nil {
-			logger.Error("Telegram could not been initialized", slog.Any("error", err))
-			return
-		}
-
-		eventListener.Register(telegramBot.Handle)
-		g.Go(func() error {
-			return telegramBot.Start(ctx)
+		startRemoteBot(ctx, g, logger, eventListener, "Telegram", config.Koolo.Telegram.Token, func(token string) (remoteBot, error) {
+			return telegram.NewBot(token, config.Koolo.Telegram.ChatID, logger)
 		})
 	}
 
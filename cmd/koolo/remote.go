@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/hectorgimenez/koolo/internal/event"
+	"github.com/hectorgimenez/koolo/internal/secrets"
+	"golang.org/x/sync/errgroup"
+)
+
+type remoteBot interface {
+	Handle(ctx context.Context, e event.Event) error
+	Start(ctx context.Context) error
+}
+
+// startRemoteBot starts a Discord or Telegram bot. A secret:// token can't be read while the secrets store is locked,
+// the bot waits until the store is unlocked from the web UI instead of stopping Koolo.
+func startRemoteBot(ctx context.Context, g *errgroup.Group, logger *slog.Logger, listener *event.Listener, name, token string, newBot func(token string) (remoteBot, error)) {
+	// Handlers can't be registered once the listener is running, events are dropped until the bot is started
+	var started atomic.Pointer[remoteBot]
+	listener.Register(func(ctx context.Context, e event.Event) error {
+		if b := started.Load(); b != nil {
+			return (*b).Handle(ctx, e)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		resolved, err := secrets.Resolve(token)
+		if errors.Is(err, secrets.ErrLocked) {
+			logger.Warn(name+" will start once the secrets store is unlocked from the web UI", slog.Any("error", err))
+			select {
+			case <-secrets.Unlocked():
+			case <-ctx.Done():
+				return nil
+			}
+			resolved, err = secrets.Resolve(token)
+		}
+		if err != nil {
+			logger.Error(name+" could not been initialized", slog.Any("error", err))
+			return nil
+		}
+
+		b, err := newBot(resolved)
+		if err != nil {
+			logger.Error(name+" could not been initialized", slog.Any("error", err))
+			return nil
+		}
+		started.Store(&b)
+
+		return b.Start(ctx)
+	})
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/secrets"
+)
+
+// unlockSecrets opens the secrets store with the configured key file or the passphrase from the environment, without
+// any of them the store stays locked until it's unlocked from the web UI
+func unlockSecrets(logger *slog.Logger) {
+	path := config.Koolo.SecretsFile()
+
+	var err error
+	switch {
+	case config.Koolo.Secrets.KeyFile != "":
+		err = secrets.UnlockWithKeyFile(path, config.Koolo.Secrets.KeyFile)
+	case os.Getenv(secrets.PassphraseEnv) != "":
+		err = secrets.Unlock(path, []byte(os.Getenv(secrets.PassphraseEnv)))
+	default:
+		if _, statErr := os.Stat(path); statErr == nil {
+			logger.Info("Secrets store is locked, unlock it from the web UI to use secret:// values", slog.String("file", path))
+		}
+		return
+	}
+
+	if err != nil {
+		logger.Error("Secrets store could not be unlocked", slog.String("file", path), slog.Any("error", err))
+		return
+	}
+	logger.Info("Secrets store unlocked", slog.String("file", path))
+}
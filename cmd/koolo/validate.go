@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+// runValidate checks every character config and pickit directory, used by "koolo validate [config dir]". It returns
+// the process exit code: 0 when there are no errors, 1 when any character has errors and 2 when the check can't run.
+func runValidate(args []string) int {
+	attachConsole()
+
+	configDir := "config"
+	if len(args) > 0 {
+		configDir = args[0]
+	}
+
+	reports, err := config.ValidateFiles(configDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	exitCode := 0
+	for _, r := range reports {
+		if len(r.Issues) == 0 {
+			fmt.Printf("%s: ok\n", r.Name)
+			continue
+		}
+
+		fmt.Printf("%s:\n", r.Name)
+		for _, issue := range r.Issues {
+			fmt.Printf("  %s\n", issue)
+		}
+		if r.Issues.HasErrors() {
+			exitCode = 1
+		}
+	}
+
+	return exitCode
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"log/slog"
+)
+
+func setProcessDpiAware() {}
+
+// runWebView has no window to show outside Windows, the UI is still served by the local server until shutdown
+func runWebView(ctx context.Context) error {
+	slog.Info("Koolo UI available at http://localhost:8087")
+	<-ctx.Done()
+
+	return nil
+}
@@ -0,0 +1,43 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/utils/winproc"
+	"github.com/inkeliz/gowebview"
+)
+
+// setProcessDpiAware sets DPI awareness to be able to read the correct scale and show the window correctly
+func setProcessDpiAware() {
+	winproc.SetProcessDpiAware.Call()
+}
+
+// runWebView shows the local server in a window, it returns when the window is closed
+func runWebView(_ context.Context) error {
+	displayScale := config.GetCurrentDisplayScale()
+	w, err := gowebview.New(&gowebview.Config{URL: "http://localhost:8087", WindowConfig: &gowebview.WindowConfig{
+		Title: "Koolo",
+		Size: &gowebview.Point{
+			X: int64(1280 * displayScale),
+			Y: int64(720 * displayScale),
+		},
+	}})
+	if err != nil {
+		w.Destroy()
+		return fmt.Errorf("error creating webview: %w", err)
+	}
+
+	w.SetSize(&gowebview.Point{
+		X: int64(1280 * displayScale),
+		Y: int64(720 * displayScale),
+	}, gowebview.HintFixed)
+
+	defer w.Destroy()
+	w.Run()
+
+	return nil
+}
@@ -21,15 +21,23 @@ func ClearAreaAroundPosition(pos data.Position, radius int, filter data.MonsterF
 	for {
 		ctx.PauseIfNotPriority()
 
-		// Check for closest monster within radius - monsters are already sorted by distance
+		// Check for closest monster within radius - monsters are already sorted by distance, the ones we can shoot
+		// from the current position go first
 		err := ctx.Char.KillMonsterSequence(func(d game.Data) (data.UnitID, bool) {
+			var target data.UnitID
 			for _, m := range d.Monsters.Enemies(filter) {
 				dist := pather.DistanceFromPoint(pos, m.Position)
-				if ctx.Data.AreaData.IsWalkable(m.Position) && dist <= radius {
+				if !ctx.Data.AreaData.IsWalkable(m.Position) || dist > radius {
+					continue
+				}
+				if ctx.PathFinder.LineOfSight(d.PlayerUnit.Position, m.Position) {
 					return m.UnitID, true
 				}
+				if target == 0 {
+					target = m.UnitID
+				}
 			}
-			return 0, false
+			return target, target != 0
 		}, nil)
 
 		if err != nil {
@@ -62,7 +70,7 @@ func ClearThroughPath(pos data.Position, radius int, filter data.MonsterFilter)
 			return nil
 		}
 
-		path, _, found := ctx.PathFinder.GetPath(pos)
+		path, _, found := ctx.PathFinder.GetPath(pos, pather.WithSmoothing())
 		if !found {
 			return fmt.Errorf("path could not be calculated")
 		}
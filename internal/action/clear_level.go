@@ -3,6 +3,7 @@ package action
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
 	"github.com/hectorgimenez/d2go/pkg/data/stat"
@@ -11,15 +12,41 @@ import (
 	"github.com/hectorgimenez/koolo/internal/utils"
 )
 
+// Rooms closer than this to the path followed by the character are considered already cleared, monsters around are
+// attacked while moving
+const clearLevelVisibilityRadius = 20
+
 func ClearCurrentLevel(openChests bool, filter data.MonsterFilter) error {
 	ctx := context.Get()
 	ctx.SetLastAction("ClearCurrentLevel")
 
-	rooms := ctx.PathFinder.OptimizeRoomsTraverseOrder()
-	for _, r := range rooms {
+	// Chests are only opened in the visited rooms, so none of them can be skipped
+	visibilityRadius := clearLevelVisibilityRadius
+	if openChests {
+		visibilityRadius = 0
+	}
+
+	plan := ctx.PathFinder.PlanExploration(visibilityRadius)
+	for _, step := range plan {
+		// Skipped rooms are checked from the tile they were seen from, the game only reports monsters close to the
+		// player
+		for _, seen := range step.Seen {
+			if err := MoveToCoords(seen.From); err != nil {
+				ctx.Logger.Warn("Failed moving to check a skipped room", slog.Any("error", err))
+				continue
+			}
+			if !hasMonstersInside(seen.Room, filter) {
+				continue
+			}
+			if err := clearRoom(seen.Room, filter); err != nil {
+				ctx.Logger.Warn("Failed to clear skipped room", slog.Any("error", err))
+			}
+		}
+
+		r := step.Room
 		err := clearRoom(r, filter)
 		if err != nil {
-			ctx.Logger.Warn("Failed to clear room: %v", err)
+			ctx.Logger.Warn("Failed to clear room", slog.Any("error", err))
 		}
 
 		if !openChests {
@@ -30,7 +57,7 @@ func ClearCurrentLevel(openChests bool, filter data.MonsterFilter) error {
 			if o.IsChest() && o.Selectable && r.IsInside(o.Position) {
 				err = MoveToCoords(o.Position)
 				if err != nil {
-					ctx.Logger.Warn("Failed moving to chest: %v", err)
+					ctx.Logger.Warn("Failed moving to chest", slog.Any("error", err))
 					continue
 				}
 				err = InteractObject(o, func() bool {
@@ -38,7 +65,7 @@ func ClearCurrentLevel(openChests bool, filter data.MonsterFilter) error {
 					return !chest.Selectable
 				})
 				if err != nil {
-					ctx.Logger.Warn("Failed interacting with chest: %v", err)
+					ctx.Logger.Warn("Failed interacting with chest", slog.Any("error", err))
 				}
 				utils.Sleep(500) // Add small delay to allow the game to open the chest and drop the content
 			}
@@ -48,6 +75,18 @@ func ClearCurrentLevel(openChests bool, filter data.MonsterFilter) error {
 	return nil
 }
 
+func hasMonstersInside(room data.Room, filter data.MonsterFilter) bool {
+	ctx := context.Get()
+
+	for _, m := range ctx.Data.Monsters.Enemies(filter) {
+		if m.Stats[stat.Life] > 0 && room.IsInside(m.Position) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func clearRoom(room data.Room, filter data.MonsterFilter) error {
 	ctx := context.Get()
 	ctx.SetLastAction("clearRoom")
@@ -15,7 +15,6 @@ import (
 	"github.com/hectorgimenez/koolo/internal/town"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/lxn/win"
 )
 
 func Gamble() error {
@@ -39,9 +38,9 @@ func Gamble() error {
 		InteractNPC(vendorNPC)
 		// Jamella gamble button is the second one
 		if vendorNPC == npc.Jamella {
-			ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_RETURN)
+			ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyEnter)
 		} else {
-			ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_DOWN, win.VK_RETURN)
+			ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyDown, game.KeyEnter)
 		}
 
 		if !ctx.Data.OpenMenus.NPCShop {
@@ -78,9 +77,9 @@ func GambleSingleItem(items []string, desiredQuality item.Quality) error {
 		InteractNPC(vendorNPC)
 		// Jamella gamble button is the second one
 		if vendorNPC == npc.Jamella {
-			ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_RETURN)
+			ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyEnter)
 		} else {
-			ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_DOWN, win.VK_RETURN)
+			ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyDown, game.KeyEnter)
 		}
 
 		if !ctx.Data.OpenMenus.NPCShop {
@@ -134,11 +133,7 @@ func GambleSingleItem(items []string, desiredQuality item.Quality) error {
 		if itemBought.Name == "" {
 			ctx.Logger.Debug("Desired items not found in gambling window, refreshing...", slog.Any("items", items))
 
-			if ctx.Data.LegacyGraphics {
-				ctx.HID.Click(game.LeftButton, ui.GambleRefreshButtonXClassic, ui.GambleRefreshButtonYClassic)
-			} else {
-				ctx.HID.Click(game.LeftButton, ui.GambleRefreshButtonX, ui.GambleRefreshButtonY)
-			}
+			RefreshGamblingWindow(ctx)
 
 			utils.Sleep(500)
 		}
@@ -224,9 +219,9 @@ func gambleItems() error {
 
 				// Select gamble option
 				if vendorNPC == npc.Jamella {
-					ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_RETURN)
+					ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyEnter)
 				} else {
-					ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_DOWN, win.VK_RETURN)
+					ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyDown, game.KeyEnter)
 				}
 
 				refreshAttempts = 0
@@ -242,9 +237,6 @@ func gambleItems() error {
 	}
 }
 func RefreshGamblingWindow(ctx *context.Status) {
-	if ctx.Data.LegacyGraphics {
-		ctx.HID.Click(game.LeftButton, ui.GambleRefreshButtonXClassic, ui.GambleRefreshButtonYClassic)
-	} else {
-		ctx.HID.Click(game.LeftButton, ui.GambleRefreshButtonX, ui.GambleRefreshButtonY)
-	}
+	refreshBtn := ui.CurrentLayout().GambleRefreshButton
+	ctx.HID.Click(game.LeftButton, refreshBtn.X, refreshBtn.Y)
 }
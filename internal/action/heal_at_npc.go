@@ -2,6 +2,7 @@ package action
 
 import (
 	"fmt"
+	"log/slog"
 
 	"github.com/hectorgimenez/koolo/internal/action/step"
 	"github.com/hectorgimenez/koolo/internal/context"
@@ -26,7 +27,7 @@ func HealAtNPC() error {
 	if shouldHeal {
 		err := InteractNPC(town.GetTownByArea(ctx.Data.PlayerUnit.Area).HealNPC())
 		if err != nil {
-			ctx.Logger.Warn("Failed to heal on NPC: %v", err)
+			ctx.Logger.Warn("Failed to heal on NPC", slog.Any("error", err))
 		}
 	}
 
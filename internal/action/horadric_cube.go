@@ -13,7 +13,6 @@ import (
 	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/lxn/win"
 )
 
 func CubeAddItems(items ...data.Item) error {
@@ -93,11 +92,8 @@ func CubeTransmute() error {
 	ctx.Logger.Debug("Transmuting items in the Horadric Cube")
 	utils.Sleep(150)
 
-	if ctx.Data.LegacyGraphics {
-		ctx.HID.Click(game.LeftButton, ui.CubeTransmuteBtnXClassic, ui.CubeTransmuteBtnYClassic)
-	} else {
-		ctx.HID.Click(game.LeftButton, ui.CubeTransmuteBtnX, ui.CubeTransmuteBtnY)
-	}
+	transmuteBtn := ui.CurrentLayout().CubeTransmuteButton
+	ctx.HID.Click(game.LeftButton, transmuteBtn.X, transmuteBtn.Y)
 
 	utils.Sleep(2000)
 
@@ -140,7 +136,7 @@ func ensureCubeIsEmpty() error {
 		}
 	}
 
-	ctx.HID.PressKey(win.VK_ESCAPE)
+	ctx.HID.PressKey(game.KeyEscape)
 	utils.Sleep(300)
 
 	stashInventory(true)
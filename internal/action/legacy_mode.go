@@ -19,7 +19,8 @@ func SwitchToLegacyMode() {
 		// Close the mini panel if option is enabled
 		if ctx.CharacterCfg.CloseMiniPanel {
 			utils.Sleep(100)
-			ctx.HID.Click(game.LeftButton, ui.CloseMiniPanelClassicX, ui.CloseMiniPanelClassicY)
+			closeBtn := ui.LayoutFor(ctx.Context, true).CloseMiniPanel
+			ctx.HID.Click(game.LeftButton, closeBtn.X, closeBtn.Y)
 			utils.Sleep(100)
 		}
 	}
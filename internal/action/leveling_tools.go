@@ -14,7 +14,6 @@ import (
 	"github.com/hectorgimenez/koolo/internal/town"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/lxn/win"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
 	"github.com/hectorgimenez/d2go/pkg/data/area"
@@ -24,38 +23,6 @@ import (
 	"github.com/hectorgimenez/d2go/pkg/data/stat"
 )
 
-var uiStatButtonPosition = map[stat.ID]data.Position{
-	stat.Strength:  {X: 240, Y: 210},
-	stat.Dexterity: {X: 240, Y: 290},
-	stat.Vitality:  {X: 240, Y: 380},
-	stat.Energy:    {X: 240, Y: 430},
-}
-
-var uiSkillPagePosition = [3]data.Position{
-	{X: 1100, Y: 140},
-	{X: 1010, Y: 140},
-	{X: 910, Y: 140},
-}
-
-var uiSkillRowPosition = [6]int{190, 250, 310, 365, 430, 490}
-var uiSkillColumnPosition = [3]int{920, 1010, 1095}
-
-var uiStatButtonPositionLegacy = map[stat.ID]data.Position{
-	stat.Strength:  {X: 430, Y: 180},
-	stat.Dexterity: {X: 430, Y: 250},
-	stat.Vitality:  {X: 430, Y: 360},
-	stat.Energy:    {X: 430, Y: 435},
-}
-
-var uiSkillPagePositionLegacy = [3]data.Position{
-	{X: 970, Y: 510},
-	{X: 970, Y: 390},
-	{X: 970, Y: 260},
-}
-
-var uiSkillRowPositionLegacy = [6]int{110, 195, 275, 355, 440, 520}
-var uiSkillColumnPositionLegacy = [3]int{690, 770, 855}
-
 func EnsureStatPoints() error {
 	// This function will allocate stat points to the character based on the settings in the character configuration file.
 
@@ -102,13 +69,8 @@ func EnsureStatPoints() error {
 					ctx.HID.PressKeyBinding(ctx.Data.KeyBindings.CharacterScreen)
 				}
 
-				// check if legacy or standard button co-ordinates should be used
-				var statBtnPosition data.Position
-				if ctx.Data.LegacyGraphics {
-					statBtnPosition = uiStatButtonPositionLegacy[currentPoints.ID]
-				} else {
-					statBtnPosition = uiStatButtonPosition[currentPoints.ID]
-				}
+				// the layout picks the legacy or standard button co-ordinates
+				statBtnPosition, _ := ui.CurrentLayout().StatButtons.Find(currentPoints.ID)
 
 				utils.Sleep(100)
 				ctx.HID.Click(game.LeftButton, statBtnPosition.X, statBtnPosition.Y)
@@ -192,17 +154,10 @@ func EnsureSkillPoints() error {
 				i := 1
 				for i <= min { // levels up the skill until unused skillpoints are spent or the target skill level is reached
 					utils.Sleep(100)
-					if ctx.Data.LegacyGraphics {
-						ctx.HID.Click(game.LeftButton, uiSkillPagePositionLegacy[skillDesc.Page-1].X, uiSkillPagePositionLegacy[skillDesc.Page-1].Y)
-					} else {
-						ctx.HID.Click(game.LeftButton, uiSkillPagePosition[skillDesc.Page-1].X, uiSkillPagePosition[skillDesc.Page-1].Y)
-					}
+					layout := ui.CurrentLayout()
+					ctx.HID.Click(game.LeftButton, layout.SkillTreeTabs[skillDesc.Page-1].X, layout.SkillTreeTabs[skillDesc.Page-1].Y)
 					utils.Sleep(200)
-					if ctx.Data.LegacyGraphics {
-						ctx.HID.Click(game.LeftButton, uiSkillColumnPositionLegacy[skillDesc.Column-1], uiSkillRowPositionLegacy[skillDesc.Row-1])
-					} else {
-						ctx.HID.Click(game.LeftButton, uiSkillColumnPosition[skillDesc.Column-1], uiSkillRowPosition[skillDesc.Row-1])
-					}
+					ctx.HID.Click(game.LeftButton, layout.SkillTreeColumns[skillDesc.Column-1], layout.SkillTreeRows[skillDesc.Row-1])
 					utils.Sleep(500)
 					i = i + 1
 				}
@@ -260,7 +215,8 @@ func EnsureSkillBindings() error {
 	}
 
 	if len(notBoundSkills) > 0 {
-		ctx.HID.Click(game.LeftButton, ui.SecondarySkillButtonX, ui.SecondarySkillButtonY)
+		secondarySkillBtn := ui.CurrentLayout().SecondarySkillButton
+		ctx.HID.Click(game.LeftButton, secondarySkillBtn.X, secondarySkillBtn.Y)
 		utils.Sleep(300)
 		ctx.HID.MovePointer(10, 10)
 		utils.Sleep(300)
@@ -282,7 +238,8 @@ func EnsureSkillBindings() error {
 	}
 
 	if ctx.Data.PlayerUnit.LeftSkill != mainSkill {
-		ctx.HID.Click(game.LeftButton, ui.MainSkillButtonX, ui.MainSkillButtonY)
+		mainSkillBtn := ui.CurrentLayout().MainSkillButton
+		ctx.HID.Click(game.LeftButton, mainSkillBtn.X, mainSkillBtn.Y)
 		utils.Sleep(300)
 		ctx.HID.MovePointer(10, 10)
 		utils.Sleep(300)
@@ -379,14 +336,17 @@ func calculateSkillPositionInUI(mainSkill bool, skillID skill.ID) (data.Position
 		}
 	}
 
-	skillOffsetX := ui.MainSkillListFirstSkillX - (ui.SkillListSkillOffset * column)
+	layout := ui.CurrentLayout()
+	skillOffsetX := layout.MainSkillListFirstSkill.X - (layout.SkillListSkillOffset * column)
+	firstSkillY := layout.MainSkillListFirstSkill.Y
 	if !mainSkill {
-		skillOffsetX = ui.SecondarySkillListFirstSkillX + (ui.SkillListSkillOffset * column)
+		skillOffsetX = layout.SecondarySkillListFirstSkill.X + (layout.SkillListSkillOffset * column)
+		firstSkillY = layout.SecondarySkillListFirstSkill.Y
 	}
 
 	return data.Position{
 		X: skillOffsetX,
-		Y: ui.SkillListFirstSkillY - ui.SkillListSkillOffset*row,
+		Y: firstSkillY - layout.SkillListSkillOffset*row,
 	}, true
 }
 
@@ -404,11 +364,12 @@ func HireMerc() error {
 			if err != nil {
 				return err
 			}
-			ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_RETURN)
+			ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyEnter)
 			utils.Sleep(2000)
-			ctx.HID.Click(game.LeftButton, ui.FirstMercFromContractorListX, ui.FirstMercFromContractorListY)
+			firstMerc := ui.CurrentLayout().FirstMercFromContractorList
+			ctx.HID.Click(game.LeftButton, firstMerc.X, firstMerc.Y)
 			utils.Sleep(500)
-			ctx.HID.Click(game.LeftButton, ui.FirstMercFromContractorListX, ui.FirstMercFromContractorListY)
+			ctx.HID.Click(game.LeftButton, firstMerc.X, firstMerc.Y)
 		}
 	}
 
@@ -429,9 +390,9 @@ func ResetStats() error {
 			}
 		}
 		InteractNPC(npc.Akara)
-		ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_DOWN, win.VK_RETURN)
+		ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyDown, game.KeyEnter)
 		utils.Sleep(1000)
-		ctx.HID.KeySequence(win.VK_HOME, win.VK_RETURN)
+		ctx.HID.KeySequence(game.KeyHome, game.KeyEnter)
 
 		if currentArea != area.RogueEncampment {
 			return WayPoint(currentArea)
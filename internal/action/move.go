@@ -3,6 +3,7 @@ package action
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
 	"time"
 
@@ -183,6 +184,47 @@ func MoveToArea(dst area.ID) error {
 	return nil
 }
 
+// MoveToAreaRoute reaches any area following the cheapest route, taking a waypoint first if it's worth it
+func MoveToAreaRoute(dst area.ID) error {
+	ctx := context.Get()
+	ctx.SetLastAction("MoveToAreaRoute")
+
+	// The waypoint menu only lists the waypoints of the selected act, until the act of the destination has been read
+	// its waypoint is taken directly, the same way runs did before planning routes
+	ctx.RecordWaypoints(ctx.Data.PlayerUnit.AvailableWaypoints)
+	_, hasWaypoint := area.WPAddresses[dst]
+	actKnown := slices.ContainsFunc(ctx.DiscoveredWaypoints, func(wp area.ID) bool { return wp.Act() == dst.Act() })
+	if hasWaypoint && !actKnown {
+		return WayPoint(dst)
+	}
+
+	route, err := ctx.PathFinder.PlanAreaRoute(dst, ctx.DiscoveredWaypoints)
+	if err != nil {
+		if !hasWaypoint {
+			return err
+		}
+
+		ctx.Logger.Warn("Failed to plan an area route, using the waypoint", slog.String("destination", dst.Area().Name), slog.Any("error", err))
+		return WayPoint(dst)
+	}
+
+	ctx.Logger.Debug("Area route calculated", slog.String("destination", dst.Area().Name), slog.Bool("useWaypoint", route.UseWayPoint), slog.Any("areas", route.Areas))
+
+	if route.UseWayPoint {
+		if err = WayPoint(route.WayPoint); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range route.Areas {
+		if err = MoveToArea(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func MoveToCoords(to data.Position) error {
 	ctx := context.Get()
 
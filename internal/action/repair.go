@@ -13,7 +13,6 @@ import (
 	"github.com/hectorgimenez/koolo/internal/town"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/lxn/win"
 )
 
 func Repair() error {
@@ -61,17 +60,14 @@ func Repair() error {
 			}
 
 			if repairNPC != npc.Halbu {
-				ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_RETURN)
+				ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyEnter)
 			} else {
-				ctx.HID.KeySequence(win.VK_HOME, win.VK_RETURN)
+				ctx.HID.KeySequence(game.KeyHome, game.KeyEnter)
 			}
 
 			utils.Sleep(100)
-			if ctx.Data.LegacyGraphics {
-				ctx.HID.Click(game.LeftButton, ui.RepairButtonXClassic, ui.RepairButtonYClassic)
-			} else {
-				ctx.HID.Click(game.LeftButton, ui.RepairButtonX, ui.RepairButtonY)
-			}
+			repairBtn := ui.CurrentLayout().RepairButton
+			ctx.HID.Click(game.LeftButton, repairBtn.X, repairBtn.Y)
 			utils.Sleep(500)
 
 			return step.CloseAllMenus()
@@ -5,8 +5,8 @@ import (
 	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
 	"github.com/hectorgimenez/d2go/pkg/data/npc"
 	"github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/town"
-	"github.com/lxn/win"
 )
 
 func ReviveMerc() {
@@ -26,9 +26,9 @@ func ReviveMerc() {
 		InteractNPC(mercNPC)
 
 		if mercNPC == npc.Tyrael2 {
-			ctx.HID.KeySequence(win.VK_END, win.VK_UP, win.VK_RETURN, win.VK_ESCAPE)
+			ctx.HID.KeySequence(game.KeyEnd, game.KeyUp, game.KeyEnter, game.KeyEscape)
 		} else {
-			ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_RETURN, win.VK_ESCAPE)
+			ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyEnter, game.KeyEscape)
 		}
 	}
 }
@@ -19,7 +19,6 @@ import (
 	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/lxn/win"
 )
 
 const (
@@ -340,38 +339,21 @@ func clickStashGoldBtn() {
 	ctx.SetLastStep("clickStashGoldBtn")
 
 	utils.Sleep(170)
-	if ctx.GameReader.LegacyGraphics() {
-		ctx.HID.Click(game.LeftButton, ui.StashGoldBtnXClassic, ui.StashGoldBtnYClassic)
-		utils.Sleep(1000)
-		ctx.HID.Click(game.LeftButton, ui.StashGoldBtnConfirmXClassic, ui.StashGoldBtnConfirmYClassic)
-	} else {
-		ctx.HID.Click(game.LeftButton, ui.StashGoldBtnX, ui.StashGoldBtnY)
-		utils.Sleep(1000)
-		ctx.HID.Click(game.LeftButton, ui.StashGoldBtnConfirmX, ui.StashGoldBtnConfirmY)
-	}
+	layout := ui.CurrentLayout()
+	ctx.HID.Click(game.LeftButton, layout.StashGoldButton.X, layout.StashGoldButton.Y)
+	utils.Sleep(1000)
+	ctx.HID.Click(game.LeftButton, layout.StashGoldConfirmButton.X, layout.StashGoldConfirmButton.Y)
 }
 
 func SwitchStashTab(tab int) {
 	ctx := context.Get()
 	ctx.SetLastStep("switchTab")
 
-	if ctx.GameReader.LegacyGraphics() {
-		x := ui.SwitchStashTabBtnXClassic
-		y := ui.SwitchStashTabBtnYClassic
-
-		tabSize := ui.SwitchStashTabBtnTabSizeClassic
-		x = x + tabSize*tab - tabSize/2
-		ctx.HID.Click(game.LeftButton, x, y)
-		utils.Sleep(500)
-	} else {
-		x := ui.SwitchStashTabBtnX
-		y := ui.SwitchStashTabBtnY
-
-		tabSize := ui.SwitchStashTabBtnTabSize
-		x = x + tabSize*tab - tabSize/2
-		ctx.HID.Click(game.LeftButton, x, y)
-		utils.Sleep(500)
-	}
+	layout := ui.CurrentLayout()
+	tabSize := layout.SwitchStashTabWidth
+	x := layout.SwitchStashTab.X + tabSize*tab - tabSize/2
+	ctx.HID.Click(game.LeftButton, x, layout.SwitchStashTab.Y)
+	utils.Sleep(500)
 }
 
 func OpenStash() error {
@@ -396,7 +378,7 @@ func CloseStash() error {
 	ctx.SetLastAction("CloseStash")
 
 	if ctx.Data.OpenMenus.Stash {
-		ctx.HID.PressKey(win.VK_ESCAPE)
+		ctx.HID.PressKey(game.KeyEscape)
 	} else {
 		return errors.New("stash is not open")
 	}
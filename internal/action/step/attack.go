@@ -3,6 +3,8 @@ package step
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +19,9 @@ import (
 
 const attackCycleDuration = 120 * time.Millisecond
 
+// Enemies closer than this make ranged characters kite away, when the character config doesn't set a radius
+const defaultKitingDangerRadius = 6
+
 var (
 	statesMutex   sync.RWMutex
 	monsterStates = make(map[data.UnitID]*attackState)
@@ -161,6 +166,8 @@ func attack(settings attackSettings) error {
 
 	numOfAttacksRemaining := settings.numOfAttacks
 	lastRunAt := time.Time{}
+	// Kite once between attacks, otherwise monsters following us would stop us from attacking at all
+	canKite := true
 
 	for {
 		ctx.PauseIfNotPriority()
@@ -179,6 +186,11 @@ func attack(settings attackSettings) error {
 			return nil // Enemy is out of range and followEnemy is disabled, we cannot attack
 		}
 
+		if canKite && kiteAwayFromEnemies(ctx, settings, monster) {
+			canKite = false
+			continue
+		}
+
 		// Check if we need to reposition if we aren't doing any damage (prevent attacking through doors etc.)
 		_, state := checkMonsterDamage(monster)
 		needsRepositioning := !state.failedAttemptStartTime.IsZero() &&
@@ -204,6 +216,7 @@ func attack(settings attackSettings) error {
 
 		lastRunAt = time.Now()
 		numOfAttacksRemaining--
+		canKite = true
 	}
 }
 
@@ -290,6 +303,58 @@ func performAttack(ctx *context.Status, settings attackSettings, x, y int) {
 	}
 }
 
+// kiteAwayFromEnemies moves ranged characters to a safer position when enemies get inside the danger radius, it
+// returns true when the character moved
+func kiteAwayFromEnemies(ctx *context.Status, settings attackSettings, target data.Monster) bool {
+	kiting := ctx.CharacterCfg.Character.Kiting
+	if !kiting.Enabled || !isKitingAttack(ctx.CharacterCfg.Character.Class, settings) {
+		return false
+	}
+
+	dangerRadius := kiting.DangerRadius
+	if dangerRadius <= 0 {
+		dangerRadius = defaultKitingDangerRadius
+	}
+
+	enemies := make([]data.Monster, 0)
+	inDanger := false
+	for _, m := range ctx.Data.Monsters.Enemies() {
+		if !isValidEnemy(m, ctx) {
+			continue
+		}
+		enemies = append(enemies, m)
+		if ctx.PathFinder.DistanceFromMe(m.Position) < dangerRadius {
+			inDanger = true
+		}
+	}
+	if !inDanger {
+		return false
+	}
+
+	dest, found := ctx.PathFinder.FindSafePosition(target.Position, enemies, dangerRadius, settings.minDistance, settings.maxDistance)
+	if !found {
+		return false
+	}
+
+	ctx.Logger.Debug("Enemies too close, moving to a safer position", slog.Any("position", dest))
+	if err := MoveTo(dest); err != nil {
+		ctx.Logger.Debug("Failed to kite away from enemies", slog.Any("error", err))
+	}
+
+	return true
+}
+
+// isKitingAttack tells if the attack can be cast from a safer position. Melee characters and stationary attacks (like FoH)
+// need to stay close to the monsters, ranged attacks can kite even if they follow the enemy to stay in range.
+func isKitingAttack(class string, settings attackSettings) bool {
+	switch strings.ToLower(class) {
+	case "berserker", "mosaic", "hammerdin", "foh", "paladin":
+		return false
+	}
+
+	return !settings.shouldStandStill && settings.maxDistance > 3
+}
+
 func ensureEnemyIsInRange(monster data.Monster, maxDistance, minDistance int, needsRepositioning bool) error {
 	ctx := context.Get()
 	ctx.SetLastStep("ensureEnemyIsInRange")
@@ -349,6 +414,11 @@ func ensureEnemyIsInRange(monster data.Monster, maxDistance, minDistance int, ne
 		}
 	}
 
+	// No clear shot on the way to the monster, look for one around it
+	if dest, found := ctx.PathFinder.FindClearShotPosition(monster.Position, minDistance, maxDistance); found {
+		return MoveTo(dest)
+	}
+
 	return nil
 }
 
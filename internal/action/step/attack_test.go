@@ -0,0 +1,28 @@
+package step
+
+import "testing"
+
+func TestIsKitingAttack(t *testing.T) {
+	tests := map[string]struct {
+		class  string
+		option AttackOption
+		want   bool
+	}{
+		"javazon following the enemy": {class: "javazon", option: Distance(1, 30), want: true},
+		"blizzard sorceress":          {class: "sorceress", option: Distance(8, 13), want: true},
+		"nova ranged distance":        {class: "nova", option: RangedDistance(1, 5), want: true},
+		"melee range":                 {class: "sorceress", option: Distance(1, 3), want: false},
+		"stationary attack":           {class: "trapsin", option: StationaryDistance(10, 20), want: false},
+		"melee class":                 {class: "Hammerdin", option: Distance(1, 10), want: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			settings := attackSettings{}
+			tc.option(&settings)
+			if got := isKitingAttack(tc.class, settings); got != tc.want {
+				t.Errorf("expected kiting %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
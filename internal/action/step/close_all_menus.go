@@ -4,8 +4,8 @@ import (
 	"errors"
 
 	"github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/lxn/win"
 )
 
 func CloseAllMenus() error {
@@ -21,7 +21,7 @@ func CloseAllMenus() error {
 		if attempts > 10 {
 			return errors.New("failed closing game menu")
 		}
-		ctx.HID.PressKey(win.VK_ESCAPE)
+		ctx.HID.PressKey(game.KeyEscape)
 		utils.Sleep(200)
 		attempts++
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/hectorgimenez/d2go/pkg/data/mode"
 	"github.com/hectorgimenez/d2go/pkg/data/skill"
 	"github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/pather"
 	"github.com/hectorgimenez/koolo/internal/utils"
 )
 
@@ -99,7 +100,15 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 			}
 		}
 
-		path, distance, found := ctx.PathFinder.GetPath(dest)
+		// Teleport paths are split in hops, so the amount of positions is not the distance
+		var path pather.Path
+		var distance int
+		var found bool
+		if ctx.Data.CanTeleport() {
+			path, distance, found = ctx.PathFinder.GetTeleportPath(dest)
+		} else {
+			path, distance, found = ctx.PathFinder.GetPath(dest, pather.WithSmoothing())
+		}
 		if !found {
 			if ctx.PathFinder.DistanceFromMe(dest) < minDistanceToFinishMoving+5 {
 				return nil
@@ -107,7 +116,7 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 
 			return errors.New("path could not be calculated. Current area: [" + ctx.Data.PlayerUnit.Area.Area().Name + "]. Trying to path to Destination: [" + fmt.Sprintf("%d,%d", dest.X, dest.Y) + "]")
 		}
-		if distance <= minDistanceToFinishMoving || len(path) <= minDistanceToFinishMoving || len(path) == 0 {
+		if distance <= minDistanceToFinishMoving || len(path) == 0 || (!ctx.Data.CanTeleport() && len(path) <= minDistanceToFinishMoving) {
 			return nil
 		}
 
@@ -146,6 +155,10 @@ func MoveTo(dest data.Position, options ...MoveOption) error {
 
 		previousPosition = ctx.Data.PlayerUnit.Position
 		previousDistance = distance
-		ctx.PathFinder.MoveThroughPath(path, walkDuration)
+		if ctx.Data.CanTeleport() {
+			ctx.PathFinder.TeleportThroughPath(path)
+		} else {
+			ctx.PathFinder.MoveThroughPath(path, walkDuration)
+		}
 	}
 }
@@ -5,8 +5,8 @@ import (
 
 	"github.com/hectorgimenez/koolo/internal/action/step"
 	"github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/town"
-	"github.com/lxn/win"
 
 	"github.com/hectorgimenez/d2go/pkg/data/item"
 	"github.com/hectorgimenez/d2go/pkg/data/npc"
@@ -36,9 +36,9 @@ func VendorRefill(forceRefill, sellJunk bool) error {
 
 	// Jamella trade button is the first one
 	if vendorNPC == npc.Jamella {
-		ctx.HID.KeySequence(win.VK_HOME, win.VK_RETURN)
+		ctx.HID.KeySequence(game.KeyHome, game.KeyEnter)
 	} else {
-		ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_RETURN)
+		ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyEnter)
 	}
 
 	SwitchStashTab(4)
@@ -63,9 +63,9 @@ func BuyAtVendor(vendor npc.ID, items ...VendorItemRequest) error {
 
 	// Jamella trade button is the first one
 	if vendor == npc.Jamella {
-		ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_RETURN)
+		ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyEnter)
 	} else {
-		ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_RETURN)
+		ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyEnter)
 	}
 
 	for _, i := range items {
@@ -41,13 +41,9 @@ func WayPoint(dest area.ID) error {
 			if err != nil {
 				return err
 			}
-			if ctx.Data.LegacyGraphics {
-				actTabX := ui.WpTabStartXClassic + (wpCoords.Tab-1)*ui.WpTabSizeXClassic + (ui.WpTabSizeXClassic / 2)
-				ctx.HID.Click(game.LeftButton, actTabX, ui.WpTabStartYClassic)
-			} else {
-				actTabX := ui.WpTabStartX + (wpCoords.Tab-1)*ui.WpTabSizeX + (ui.WpTabSizeX / 2)
-				ctx.HID.Click(game.LeftButton, actTabX, ui.WpTabStartY)
-			}
+			layout := ui.CurrentLayout()
+			actTabX := layout.WpTabStart.X + (wpCoords.Tab-1)*layout.WpTabWidth + (layout.WpTabWidth / 2)
+			ctx.HID.Click(game.LeftButton, actTabX, layout.WpTabStart.Y)
 			utils.Sleep(200)
 			// Just to make sure no message like TZ change or public game spam prevent bot from clicking on waypoint
 			ClearMessages()
@@ -77,6 +73,7 @@ func useWP(dest area.ID) error {
 	finalDestination := dest
 	traverseAreas := make([]area.ID, 0)
 	currentWP := area.WPAddresses[dest]
+	ctx.RecordWaypoints(ctx.Data.PlayerUnit.AvailableWaypoints)
 	if !slices.Contains(ctx.Data.PlayerUnit.AvailableWaypoints, dest) {
 		for {
 			traverseAreas = append(currentWP.LinkedFrom, traverseAreas...)
@@ -96,13 +93,9 @@ func useWP(dest area.ID) error {
 	currentWP = area.WPAddresses[dest]
 
 	// First use the previous available waypoint that we have discovered
-	if ctx.Data.LegacyGraphics {
-		areaBtnY := ui.WpListStartYClassic + (currentWP.Row-1)*ui.WpAreaBtnHeightClassic + (ui.WpAreaBtnHeightClassic / 2)
-		ctx.HID.Click(game.LeftButton, ui.WpListPositionXClassic, areaBtnY)
-	} else {
-		areaBtnY := ui.WpListStartY + (currentWP.Row-1)*ui.WpAreaBtnHeight + (ui.WpAreaBtnHeight / 2)
-		ctx.HID.Click(game.LeftButton, ui.WpListPositionX, areaBtnY)
-	}
+	layout := ui.CurrentLayout()
+	areaBtnY := layout.WpListStart.Y + (currentWP.Row-1)*layout.WpAreaButtonHeight + (layout.WpAreaButtonHeight / 2)
+	ctx.HID.Click(game.LeftButton, layout.WpListStart.X, areaBtnY)
 	utils.Sleep(1000)
 
 	// We have the WP discovered, just use it
@@ -33,10 +33,11 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 	b.ctx.SwitchPriority(botCtx.PriorityNormal) // Restore priority to normal, in case it was stopped in previous game
 	b.ctx.CurrentGame = botCtx.NewGameHelper()  // Reset current game helper structure
 
-	err := b.ctx.GameReader.FetchMapData()
+	err := b.ctx.MemoryReader.FetchMapData()
 	if err != nil {
 		return err
 	}
+	b.ctx.PathFinder.ResetAreaGraphs()
 
 	// Let's make sure we have updated game data also fully loaded before performing anything
 	b.ctx.WaitForGameToLoad()
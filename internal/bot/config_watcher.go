@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"os"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+const configWatchInterval = 2 * time.Second
+
+// ConfigWatcher polls the config.yaml, profiles and pickit files of the running supervisors, changed configs are
+// validated and queued on the supervisor to be applied between games
+type ConfigWatcher struct {
+	manager *SupervisorManager
+	logger  *slog.Logger
+	stamps  map[string]map[string]fileStamp
+}
+
+type fileStamp struct {
+	modTime int64
+	size    int64
+}
+
+func NewConfigWatcher(manager *SupervisorManager, logger *slog.Logger) *ConfigWatcher {
+	return &ConfigWatcher{
+		manager: manager,
+		logger:  logger,
+		stamps:  make(map[string]map[string]fileStamp),
+	}
+}
+
+func (w *ConfigWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+func (w *ConfigWatcher) check() {
+	supervisors := w.manager.runningSupervisors()
+	for name := range w.stamps {
+		if _, running := supervisors[name]; !running {
+			delete(w.stamps, name)
+		}
+	}
+
+	for name, sup := range supervisors {
+		cfg := sup.CurrentConfig()
+		if cfg == nil {
+			continue
+		}
+
+		stamps := readFileStamps(config.CharacterFiles(name, cfg))
+		previous, seen := w.stamps[name]
+		w.stamps[name] = stamps
+		// Files are only recorded the first time, the supervisor has just loaded them
+		if !seen || maps.Equal(previous, stamps) {
+			continue
+		}
+
+		reloaded, err := config.LoadCharacter(name)
+		if err != nil {
+			w.logger.Warn("Error reloading config, keeping the current one", slog.String("supervisor", name), slog.Any("error", err))
+			continue
+		}
+		w.manager.queueConfig(name, sup, reloaded)
+	}
+}
+
+func readFileStamps(files []string) map[string]fileStamp {
+	stamps := make(map[string]fileStamp, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		stamps[file] = fileStamp{modTime: info.ModTime().UnixNano(), size: info.Size()}
+	}
+
+	return stamps
+}
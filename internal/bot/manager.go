@@ -3,26 +3,28 @@ package bot
 import (
 	"fmt"
 	"log/slog"
+	"maps"
 	"strconv"
-	"syscall"
+	"strings"
+	"sync"
 	"time"
-	"unsafe"
 
 	"github.com/hectorgimenez/koolo/cmd/koolo/log"
-	"github.com/hectorgimenez/koolo/internal/character"
 	"github.com/hectorgimenez/koolo/internal/config"
 	"github.com/hectorgimenez/koolo/internal/context"
 	"github.com/hectorgimenez/koolo/internal/event"
 	"github.com/hectorgimenez/koolo/internal/game"
-	"github.com/hectorgimenez/koolo/internal/health"
-	"github.com/hectorgimenez/koolo/internal/pather"
-	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/hectorgimenez/koolo/internal/utils/winproc"
-	"github.com/lxn/win"
+	"github.com/hectorgimenez/koolo/internal/ui"
 )
 
+// Four frames per second are enough to follow town routines and chicken decisions, recordings grow fast otherwise
+const gameDataRecordingInterval = 250 * time.Millisecond
+
 type SupervisorManager struct {
-	logger         *slog.Logger
+	logger *slog.Logger
+	// mu guards the supervisors and crash detectors, they are changed from the web server and read by the scheduler
+	// and the config watcher
+	mu             sync.RWMutex
 	supervisors    map[string]Supervisor
 	crashDetectors map[string]*game.CrashDetector
 	eventListener  *event.Listener
@@ -50,7 +52,7 @@ func (mng *SupervisorManager) AvailableSupervisors() []string {
 
 func (mng *SupervisorManager) Start(supervisorName string, attachToExisting bool, pidHwnd ...uint32) error {
 	// Avoid multiple instances of the supervisor - shitstorm prevention
-	if _, exists := mng.supervisors[supervisorName]; exists {
+	if _, exists := mng.supervisor(supervisorName); exists {
 		return fmt.Errorf("supervisor %s is already running", supervisorName)
 	}
 
@@ -60,19 +62,38 @@ func (mng *SupervisorManager) Start(supervisorName string, attachToExisting bool
 		return fmt.Errorf("error loading config: %w", err)
 	}
 
+	if cfg, found := config.Characters[supervisorName]; found {
+		// Config errors would crash the bot or make it misbehave once in game, warnings are allowed
+		if issues := cfg.ValidateFields(); issues.HasErrors() {
+			errs := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				if issue.Severity == config.SeverityError {
+					errs = append(errs, issue.Field+": "+issue.Message)
+				}
+			}
+
+			return fmt.Errorf("invalid %s config: %s", supervisorName, strings.Join(errs, "; "))
+		}
+
+		// Positions are read from the layout profile file, a wrong profile would make the bot click everywhere
+		if _, err = ui.LoadProfile(cfg.LayoutProfile); err != nil {
+			return err
+		}
+	}
+
 	supervisorLogger, err := log.NewLogger(config.Koolo.Debug.Log, config.Koolo.LogSaveDirectory, supervisorName)
 	if err != nil {
 		return err
 	}
 
 	var optionalPID uint32
-	var optionalHWND win.HWND
+	var optionalHWND uint32
 
 	if attachToExisting {
 		if len(pidHwnd) == 2 {
 			mng.logger.Info("Attaching to existing game", "pid", pidHwnd[0], "hwnd", pidHwnd[1])
 			optionalPID = pidHwnd[0]
-			optionalHWND = win.HWND(pidHwnd[1])
+			optionalHWND = pidHwnd[1]
 		} else {
 			return fmt.Errorf("pid and hwnd are required when attaching to an existing game")
 		}
@@ -83,12 +104,14 @@ func (mng *SupervisorManager) Start(supervisorName string, attachToExisting bool
 		return err
 	}
 
+	mng.mu.Lock()
 	if oldCrashDetector, exists := mng.crashDetectors[supervisorName]; exists {
 		oldCrashDetector.Stop() // Stop the old crash detector if it exists
 	}
 
 	mng.supervisors[supervisorName] = supervisor
 	mng.crashDetectors[supervisorName] = crashDetector
+	mng.mu.Unlock()
 
 	if config.Koolo.GameWindowArrangement {
 		go func() {
@@ -110,246 +133,127 @@ func (mng *SupervisorManager) Start(supervisorName string, attachToExisting bool
 	return nil
 }
 
+// ReloadConfig reloads the configs from disk, running supervisors get their new config queued and applied between
+// games. Configs with errors are not applied, the supervisor keeps running with its current config.
 func (mng *SupervisorManager) ReloadConfig() error {
-
-	// Load fresh configs
 	if err := config.Load(); err != nil {
 		return err
 	}
 
-	// Apply new configs to running supervisors
-	for name, sup := range mng.supervisors {
-		newCfg, exists := config.Characters[name]
-		if !exists {
-			continue
+	for name, sup := range mng.runningSupervisors() {
+		if cfg, found := config.Characters[name]; found {
+			mng.queueConfig(name, sup, cfg)
 		}
+	}
 
-		ctx := sup.GetContext()
-		if ctx == nil {
-			continue
-		}
+	return nil
+}
 
-		// Preserve runtime data
-		//oldRuntimeData := ctx.CharacterCfg.Runtime
+// queueConfig validates the reloaded config and queues it on the supervisor if anything changed
+func (mng *SupervisorManager) queueConfig(name string, sup Supervisor, cfg *config.CharacterCfg) {
+	current := sup.CurrentConfig()
+	if current == nil {
+		return
+	}
 
-		// Update the config
-		*ctx.CharacterCfg = *newCfg
-		//ctx.CharacterCfg.Runtime = oldRuntimeData
+	if issues := cfg.ValidateFields(); issues.HasErrors() {
+		errs := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			if issue.Severity == config.SeverityError {
+				errs = append(errs, issue.String())
+			}
+		}
+		mng.logger.Warn("Reloaded config has errors, keeping the current one", slog.String("supervisor", name), slog.String("errors", strings.Join(errs, "; ")))
+		return
 	}
 
-	return nil
+	changes := config.ChangedFields(current, cfg)
+	if len(changes) == 0 {
+		return
+	}
+
+	mng.logger.Info("Config changed, it will be applied after the current game", slog.String("supervisor", name), slog.String("changes", strings.Join(changes, ", ")))
+	sup.QueueConfig(cfg, changes)
 }
 
 func (mng *SupervisorManager) StopAll() {
-	for _, s := range mng.supervisors {
+	for _, s := range mng.runningSupervisors() {
 		s.Stop()
 	}
 }
 
 func (mng *SupervisorManager) Stop(supervisor string) {
-
+	// Delete him from the list of Supervisors
+	mng.mu.Lock()
 	s, found := mng.supervisors[supervisor]
-	if found {
+	cd, cdFound := mng.crashDetectors[supervisor]
+	delete(mng.supervisors, supervisor)
+	delete(mng.crashDetectors, supervisor)
+	mng.mu.Unlock()
 
+	if found {
 		// Stop the Supervisor
 		s.Stop()
 
-		// Delete him from the list of Supervisors
-		delete(mng.supervisors, supervisor)
-
-		if cd, ok := mng.crashDetectors[supervisor]; ok {
+		if cdFound {
 			cd.Stop()
-			delete(mng.crashDetectors, supervisor)
 		}
 	}
 }
 
 func (mng *SupervisorManager) TogglePause(supervisor string) {
-	s, found := mng.supervisors[supervisor]
+	s, found := mng.supervisor(supervisor)
 	if found {
 		s.TogglePause()
 	}
 }
 
 func (mng *SupervisorManager) Status(characterName string) Stats {
-	for name, supervisor := range mng.supervisors {
-		if name == characterName {
-			return supervisor.Stats()
-		}
+	if supervisor, found := mng.supervisor(characterName); found {
+		return supervisor.Stats()
 	}
 
 	return Stats{}
 }
 
 func (mng *SupervisorManager) GetData(characterName string) *game.Data {
-	for name, supervisor := range mng.supervisors {
-		if name == characterName {
-			return supervisor.GetData()
-		}
+	if supervisor, found := mng.supervisor(characterName); found {
+		return supervisor.GetData()
 	}
 
 	return nil
 }
 
 func (mng *SupervisorManager) GetContext(characterName string) *context.Context {
-	for name, supervisor := range mng.supervisors {
-		if name == characterName {
-			return supervisor.GetContext()
-		}
+	if supervisor, found := mng.supervisor(characterName); found {
+		return supervisor.GetContext()
 	}
 
 	return nil
 }
 
-func (mng *SupervisorManager) buildSupervisor(supervisorName string, logger *slog.Logger, attach bool, optionalPID uint32, optionalHWND win.HWND) (Supervisor, *game.CrashDetector, error) {
-	cfg, found := config.Characters[supervisorName]
-	if !found {
-		return nil, nil, fmt.Errorf("character %s not found", supervisorName)
-	}
-
-	var pid uint32
-	var hwnd win.HWND
-
-	if attach {
-		if optionalPID != 0 && optionalHWND != 0 {
-			pid = optionalPID
-			hwnd = optionalHWND
-		} else {
-			return nil, nil, fmt.Errorf("pid and hwnd are required when attaching to an existing game")
-		}
-	} else {
-		var err error
-		pid, hwnd, err = game.StartGame(cfg.Username, cfg.Password, cfg.AuthMethod, cfg.AuthToken, cfg.Realm, cfg.CommandLineArgs, config.Koolo.UseCustomSettings)
-		if err != nil {
-			return nil, nil, fmt.Errorf("error starting game: %w", err)
-		}
-	}
-
-	gr, err := game.NewGameReader(cfg, supervisorName, pid, hwnd, logger)
-	if err != nil {
-		return nil, nil, fmt.Errorf("error creating game reader: %w", err)
-	}
-
-	gi, err := game.InjectorInit(logger, gr.GetPID())
-	if err != nil {
-		return nil, nil, fmt.Errorf("error creating game injector: %w", err)
-	}
-
-	ctx := context.NewContext(supervisorName)
-
-	hidM := game.NewHID(gr, gi)
-	pf := pather.NewPathFinder(gr, ctx.Data, hidM, cfg)
-
-	bm := health.NewBeltManager(ctx.Data, hidM, logger, supervisorName)
-	hm := health.NewHealthManager(bm, ctx.Data)
-
-	ctx.CharacterCfg = cfg
-	ctx.EventListener = mng.eventListener
-	ctx.HID = hidM
-	ctx.Logger = logger
-	ctx.Manager = game.NewGameManager(gr, hidM, supervisorName)
-	ctx.GameReader = gr
-	ctx.MemoryInjector = gi
-	ctx.PathFinder = pf
-	ctx.BeltManager = bm
-	ctx.HealthManager = hm
-	char, err := character.BuildCharacter(ctx.Context)
-	if err != nil {
-		return nil, nil, fmt.Errorf("error creating character: %w", err)
-	}
-	ctx.Char = char
-
-	bot := NewBot(ctx.Context)
-
-	statsHandler := NewStatsHandler(supervisorName, logger)
-	mng.eventListener.Register(statsHandler.Handle)
-
-	var supervisor Supervisor
-
-	supervisor, err = NewSinglePlayerSupervisor(supervisorName, bot, statsHandler)
-
-	if err != nil {
-		return nil, nil, err
-
-	}
-
-	// This function will be used to restart the client - passed to the crashDetector
-	restartFunc := func() {
-		mng.logger.Info("Restarting supervisor after crash", slog.String("supervisor", supervisorName))
-		mng.Stop(supervisorName)
-		time.Sleep(5 * time.Second) // Wait a bit before restarting
-
-		// Get a list of all available Supervisors
-		supervisorList := mng.AvailableSupervisors()
-
-		for {
-
-			// Set the default state
-			tokenAuthStarting := false
-
-			// Get the current supervisor's config
-			supCfg := config.Characters[supervisorName]
-
-			for _, sup := range supervisorList {
-
-				// If the current don't check against the one we're trying to launch
-				if sup == supervisorName {
-					continue
-				}
-
-				if mng.GetSupervisorStats(sup).SupervisorStatus == Starting {
-					if supCfg.AuthMethod == "TokenAuth" {
-						tokenAuthStarting = true
-						mng.logger.Info("Waiting before restart as another client is already starting and we're using token auth", slog.String("supervisor", sup))
-						break
-					}
-
-					sCfg, found := config.Characters[sup]
-					if found {
-						if sCfg.AuthMethod == "TokenAuth" {
-							// A client that uses token auth is currently starting, hold off restart
-							tokenAuthStarting = true
-							mng.logger.Info("Waiting before restart as a client that's using token auth is already starting", slog.String("supervisor", sup))
-							break
-						}
-					}
-				}
-			}
-
-			if !tokenAuthStarting {
-				break
-			}
-
-			// Wait 5 seconds before checking again
-			utils.Sleep(5000)
-		}
-
-		gameTitle := "D2R - [" + strconv.FormatInt(int64(pid), 10) + "] - " + supervisorName + " - " + cfg.Realm
-		winproc.SetWindowText.Call(uintptr(hwnd), uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(gameTitle))))
-
-		err := mng.Start(supervisorName, false)
-		if err != nil {
-			mng.logger.Error("Failed to restart supervisor", slog.String("supervisor", supervisorName), slog.String("Error: ", err.Error()))
-		}
-	}
+func (mng *SupervisorManager) GetSupervisorStats(supervisor string) Stats {
+	return mng.Status(supervisor)
+}
 
-	gameTitle := "D2R - [" + strconv.FormatInt(int64(pid), 10) + "] - " + supervisorName + " - " + cfg.Realm
-	winproc.SetWindowText.Call(uintptr(hwnd), uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(gameTitle))))
-	crashDetector := game.NewCrashDetector(supervisorName, int32(pid), uintptr(hwnd), mng.logger, restartFunc)
+func (mng *SupervisorManager) supervisor(name string) (Supervisor, bool) {
+	mng.mu.RLock()
+	defer mng.mu.RUnlock()
 
-	return supervisor, crashDetector, nil
+	s, found := mng.supervisors[name]
+	return s, found
 }
 
-func (mng *SupervisorManager) GetSupervisorStats(supervisor string) Stats {
-	if mng.supervisors[supervisor] == nil {
-		return Stats{}
-	}
-	return mng.supervisors[supervisor].Stats()
+// runningSupervisors returns a copy of the running supervisors, it can be iterated while supervisors start and stop
+func (mng *SupervisorManager) runningSupervisors() map[string]Supervisor {
+	mng.mu.RLock()
+	defer mng.mu.RUnlock()
+
+	return maps.Clone(mng.supervisors)
 }
 
 func (mng *SupervisorManager) rearrangeWindows() {
-	width := win.GetSystemMetrics(0)
-	height := win.GetSystemMetrics(1)
+	width, height := screenSize()
 	var windowBorderX int32 = 2   // left + right window border is 2px
 	var windowBorderY int32 = 40  // upper window border is usually 40px
 	var windowOffsetX int32 = -10 // offset horizontal window placement by -10 pixel
@@ -365,7 +269,7 @@ func (mng *SupervisorManager) rearrangeWindows() {
 	)
 
 	var column, row int32
-	for _, sp := range mng.supervisors {
+	for _, sp := range mng.runningSupervisors() {
 		// reminder that columns are vertical (they go up and down) and rows are horizontal (they go left and right)
 		if column > maxColumns {
 			column = 0
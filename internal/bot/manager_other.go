@@ -0,0 +1,19 @@
+//go:build !windows
+
+package bot
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+// buildSupervisor fails outside Windows, the game client can't be started or attached to
+func (mng *SupervisorManager) buildSupervisor(_ string, _ *slog.Logger, _ bool, _ uint32, _ uint32) (Supervisor, *game.CrashDetector, error) {
+	return nil, nil, errors.New("game clients can only be started on Windows")
+}
+
+func screenSize() (int32, int32) {
+	return 0, 0
+}
@@ -0,0 +1,191 @@
+//go:build windows
+
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/hectorgimenez/koolo/internal/character"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/health"
+	"github.com/hectorgimenez/koolo/internal/pather"
+	"github.com/hectorgimenez/koolo/internal/secrets"
+	"github.com/hectorgimenez/koolo/internal/utils"
+	"github.com/hectorgimenez/koolo/internal/utils/winproc"
+	"github.com/lxn/win"
+)
+
+func (mng *SupervisorManager) buildSupervisor(supervisorName string, logger *slog.Logger, attach bool, optionalPID uint32, optionalHWND uint32) (Supervisor, *game.CrashDetector, error) {
+	cfg, found := config.Characters[supervisorName]
+	if !found {
+		return nil, nil, fmt.Errorf("character %s not found", supervisorName)
+	}
+
+	var pid uint32
+	var hwnd win.HWND
+
+	if attach {
+		if optionalPID != 0 && optionalHWND != 0 {
+			pid = optionalPID
+			hwnd = win.HWND(optionalHWND)
+		} else {
+			return nil, nil, fmt.Errorf("pid and hwnd are required when attaching to an existing game")
+		}
+	} else {
+		// Credentials can be secret:// references, they are only resolved to start the client
+		password, err := secrets.Resolve(cfg.Password)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading password: %w", err)
+		}
+		authToken, err := secrets.Resolve(cfg.AuthToken)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading auth token: %w", err)
+		}
+
+		pid, hwnd, err = game.StartGame(cfg.Username, password, cfg.AuthMethod, authToken, cfg.Realm, cfg.CommandLineArgs, config.Koolo.UseCustomSettings)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error starting game: %w", err)
+		}
+	}
+
+	gr, err := game.NewGameReader(cfg, supervisorName, pid, hwnd, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating game reader: %w", err)
+	}
+
+	gi, err := game.InjectorInit(logger, gr.GetPID())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating game injector: %w", err)
+	}
+
+	ctx := context.NewContext(supervisorName)
+
+	hidM := game.NewHID(game.NewWindowInput(gr, gi))
+	pf := pather.NewPathFinder(gr, ctx.Data, hidM, cfg)
+
+	bm := health.NewBeltManager(ctx.Data, hidM, logger, supervisorName)
+	hm := health.NewHealthManager(bm, ctx.Data)
+
+	ctx.CharacterCfg = cfg
+	ctx.EventListener = mng.eventListener
+	ctx.HID = hidM
+	ctx.Logger = logger
+	ctx.Manager = game.NewGameManager(gr, hidM, supervisorName)
+	ctx.GameReader = gr
+	ctx.MemoryReader = gr
+	if config.Koolo.Debug.RecordGameData {
+		path := filepath.Join(config.Koolo.LogSaveDirectory, "recordings", fmt.Sprintf("%s-%s.jsonl.gz", supervisorName, time.Now().Format("2006-01-02-15-04-05")))
+		recorder, err := game.NewDataRecorder(gr, path, gameDataRecordingInterval)
+		if err != nil {
+			logger.Warn("Game data will not be recorded", slog.Any("error", err))
+		} else {
+			ctx.GameReader = recorder
+		}
+	}
+	ctx.MemoryInjector = gi
+	ctx.PathFinder = pf
+	ctx.BeltManager = bm
+	ctx.HealthManager = hm
+	char, err := character.BuildCharacter(ctx.Context)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating character: %w", err)
+	}
+	ctx.Char = char
+
+	bot := NewBot(ctx.Context)
+
+	statsHandler := NewStatsHandler(supervisorName, logger)
+	mng.eventListener.Register(statsHandler.Handle)
+
+	var supervisor Supervisor
+
+	supervisor, err = NewSinglePlayerSupervisor(supervisorName, bot, statsHandler)
+
+	if err != nil {
+		return nil, nil, err
+
+	}
+
+	// This function will be used to restart the client - passed to the crashDetector
+	restartFunc := func() {
+		mng.logger.Info("Restarting supervisor after crash", slog.String("supervisor", supervisorName))
+		mng.Stop(supervisorName)
+		time.Sleep(5 * time.Second) // Wait a bit before restarting
+
+		// Get a list of all available Supervisors
+		supervisorList := mng.AvailableSupervisors()
+
+		for {
+
+			// Set the default state
+			tokenAuthStarting := false
+
+			// Get the current supervisor's config
+			supCfg := config.Characters[supervisorName]
+
+			for _, sup := range supervisorList {
+
+				// If the current don't check against the one we're trying to launch
+				if sup == supervisorName {
+					continue
+				}
+
+				if mng.GetSupervisorStats(sup).SupervisorStatus == Starting {
+					if supCfg.AuthMethod == "TokenAuth" {
+						tokenAuthStarting = true
+						mng.logger.Info("Waiting before restart as another client is already starting and we're using token auth", slog.String("supervisor", sup))
+						break
+					}
+
+					sCfg, found := config.Characters[sup]
+					if found {
+						if sCfg.AuthMethod == "TokenAuth" {
+							// A client that uses token auth is currently starting, hold off restart
+							tokenAuthStarting = true
+							mng.logger.Info("Waiting before restart as a client that's using token auth is already starting", slog.String("supervisor", sup))
+							break
+						}
+					}
+				}
+			}
+
+			if !tokenAuthStarting {
+				break
+			}
+
+			// Wait 5 seconds before checking again
+			utils.Sleep(5000)
+		}
+
+		gameTitle := "D2R - [" + strconv.FormatInt(int64(pid), 10) + "] - " + supervisorName + " - " + cfg.Realm
+		winproc.SetWindowText.Call(uintptr(hwnd), uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(gameTitle))))
+
+		err := mng.Start(supervisorName, false)
+		if err != nil {
+			mng.logger.Error("Failed to restart supervisor", slog.String("supervisor", supervisorName), slog.String("Error: ", err.Error()))
+		}
+	}
+
+	gameTitle := "D2R - [" + strconv.FormatInt(int64(pid), 10) + "] - " + supervisorName + " - " + cfg.Realm
+	winproc.SetWindowText.Call(uintptr(hwnd), uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(gameTitle))))
+	probes := game.HangProbes{
+		FrozenGameData:     time.Duration(config.Koolo.CrashDetector.FrozenGameDataSeconds) * time.Second,
+		LoadingScreen:      time.Duration(config.Koolo.CrashDetector.LoadingScreenSeconds) * time.Second,
+		UnresponsiveWindow: time.Duration(config.Koolo.CrashDetector.UnresponsiveWindowSeconds) * time.Second,
+	}
+	crashDetector := game.NewCrashDetector(supervisorName, int32(pid), uintptr(hwnd), gr, probes, mng.logger, restartFunc)
+
+	return supervisor, crashDetector, nil
+}
+
+func screenSize() (int32, int32) {
+	return win.GetSystemMetrics(0), win.GetSystemMetrics(1)
+}
@@ -9,12 +9,12 @@ import (
 	"time"
 
 	"github.com/hectorgimenez/d2go/pkg/data/skill"
-	"github.com/hectorgimenez/koolo/internal/config"
 	ct "github.com/hectorgimenez/koolo/internal/context"
 	"github.com/hectorgimenez/koolo/internal/event"
 	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/health"
 	"github.com/hectorgimenez/koolo/internal/run"
+	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
 )
 
@@ -66,6 +66,9 @@ func (s *SinglePlayerSupervisor) Start() error {
 
 			// By this point, we should be in the character selection screen.
 			if !s.bot.ctx.Manager.InGame() {
+				// Configs reloaded from disk are only applied out of game
+				s.applyPendingConfig()
+
 				// Create the game
 				if err = s.HandleOutOfGameFlow(); err != nil {
 					// Ignore loading screen errors or unhandled errors (for now) and try again
@@ -81,7 +84,7 @@ func (s *SinglePlayerSupervisor) Start() error {
 
 			runs := run.BuildRuns(s.bot.ctx.CharacterCfg)
 			gameStart := time.Now()
-			if config.Characters[s.name].Game.RandomizeRuns {
+			if s.bot.ctx.CharacterCfg.Game.RandomizeRuns {
 				rand.Shuffle(len(runs), func(i, j int) { runs[i], runs[j] = runs[j], runs[i] })
 			}
 			event.Send(event.GameCreated(event.Text(s.name, "New game created"), "", ""))
@@ -150,7 +153,8 @@ func (s *SinglePlayerSupervisor) HandleOutOfGameFlow() error {
 	if s.bot.ctx.CharacterCfg.AuthMethod != "None" && s.bot.ctx.GameReader.IsInCharacterSelectionScreen() && !s.bot.ctx.GameReader.IsOnline() {
 
 		// Try and click the online tab to re-connect to bnet
-		s.bot.ctx.HID.Click(game.LeftButton, 1090, 32) // click the online button
+		onlineTab := ui.LayoutFor(s.bot.ctx, false).OnlineTab
+		s.bot.ctx.HID.Click(game.LeftButton, onlineTab.X, onlineTab.Y) // click the online button
 
 		// Wait a bit
 		utils.Sleep(4000)
@@ -181,7 +185,8 @@ func (s *SinglePlayerSupervisor) HandleOutOfGameFlow() error {
 				}
 
 				// Try to enter bnet lobby
-				s.bot.ctx.HID.Click(game.LeftButton, 744, 650)
+				lobbyBtn := ui.LayoutFor(s.bot.ctx, false).LobbyButton
+				s.bot.ctx.HID.Click(game.LeftButton, lobbyBtn.X, lobbyBtn.Y)
 				utils.Sleep(1000)
 			}
 
@@ -199,7 +204,8 @@ func (s *SinglePlayerSupervisor) HandleOutOfGameFlow() error {
 			if !s.bot.ctx.GameReader.IsOnline() && s.bot.ctx.CharacterCfg.AuthMethod != "None" {
 
 				// Try and click the online tab to re-connect to bnet
-				s.bot.ctx.HID.Click(game.LeftButton, 1090, 32) // click the online button
+				onlineTab := ui.LayoutFor(s.bot.ctx, false).OnlineTab
+				s.bot.ctx.HID.Click(game.LeftButton, onlineTab.X, onlineTab.Y) // click the online button
 
 				// Wait a bit
 				utils.Sleep(4000)
@@ -236,7 +242,8 @@ func (s *SinglePlayerSupervisor) HandleOutOfGameFlow() error {
 				}
 
 				// Try to enter bnet lobby
-				s.bot.ctx.HID.Click(game.LeftButton, 744, 650)
+				lobbyBtn := ui.LayoutFor(s.bot.ctx, false).LobbyButton
+				s.bot.ctx.HID.Click(game.LeftButton, lobbyBtn.X, lobbyBtn.Y)
 				utils.Sleep(1000)
 			}
 
@@ -4,16 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"os"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/hectorgimenez/koolo/internal/config"
 	ct "github.com/hectorgimenez/koolo/internal/context"
 	"github.com/hectorgimenez/koolo/internal/event"
 	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/run"
-	"github.com/hectorgimenez/koolo/internal/utils/winproc"
-	"github.com/lxn/win"
 )
 
 type Supervisor interface {
@@ -25,6 +26,9 @@ type Supervisor interface {
 	SetWindowPosition(x, y int)
 	GetData() *game.Data
 	GetContext() *ct.Context
+	// CurrentConfig returns the config the supervisor is running with, it's safe to call from any goroutine
+	CurrentConfig() *config.CharacterCfg
+	QueueConfig(cfg *config.CharacterCfg, changes []string)
 }
 
 type baseSupervisor struct {
@@ -32,6 +36,12 @@ type baseSupervisor struct {
 	name         string
 	statsHandler *StatsHandler
 	cancelFn     context.CancelFunc
+
+	// cfg is the config published to the bot, it's replaced by a new pointer when a reloaded config is applied
+	cfg            atomic.Pointer[config.CharacterCfg]
+	pendingMu      sync.Mutex
+	pendingCfg     *config.CharacterCfg
+	pendingChanges []string
 }
 
 func newBaseSupervisor(
@@ -39,11 +49,14 @@ func newBaseSupervisor(
 	name string,
 	statsHandler *StatsHandler,
 ) (*baseSupervisor, error) {
-	return &baseSupervisor{
+	s := &baseSupervisor{
 		bot:          bot,
 		name:         name,
 		statsHandler: statsHandler,
-	}, nil
+	}
+	s.cfg.Store(bot.ctx.CharacterCfg)
+
+	return s, nil
 }
 
 func (s *baseSupervisor) Name() string {
@@ -77,7 +90,10 @@ func (s *baseSupervisor) Stop() {
 	s.bot.ctx.SwitchPriority(ct.PriorityStop)
 
 	s.bot.ctx.MemoryInjector.Unload()
-	s.bot.ctx.GameReader.Close()
+	s.bot.ctx.MemoryReader.Close()
+	if recorder, ok := s.bot.ctx.GameReader.(*game.DataRecorder); ok {
+		recorder.Close()
+	}
 
 	if s.bot.ctx.CharacterCfg.KillD2OnStop || s.bot.ctx.CharacterCfg.Scheduler.Enabled {
 		s.KillClient()
@@ -86,26 +102,63 @@ func (s *baseSupervisor) Stop() {
 	s.bot.ctx.Logger.Info("Finished stopping", slog.String("configuration", s.name))
 }
 
-func (s *baseSupervisor) KillClient() error {
+// QueueConfig stores a reloaded config, it's applied by the supervisor loop between games so a run never sees the
+// config changing under it. A newer config replaces the queued one, changes are accumulated.
+func (s *baseSupervisor) QueueConfig(cfg *config.CharacterCfg, changes []string) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
 
-	process, err := os.FindProcess(int(s.bot.ctx.GameReader.Process.GetPID()))
-	if err != nil {
-		s.bot.ctx.Logger.Info("Failed to find process", slog.String("configuration", s.name))
-		return err
+	s.pendingCfg = cfg
+	for _, change := range changes {
+		if !slices.Contains(s.pendingChanges, change) {
+			s.pendingChanges = append(s.pendingChanges, change)
+		}
 	}
-	err = process.Kill()
-	if err != nil {
-		s.bot.ctx.Logger.Info("Failed to kill process", slog.String("configuration", s.name))
-		return err
+}
+
+func (s *baseSupervisor) CurrentConfig() *config.CharacterCfg {
+	return s.cfg.Load()
+}
+
+// applyPendingConfig publishes the queued config, it must be called from the supervisor loop while out of game so no
+// bot goroutine is reading the config
+func (s *baseSupervisor) applyPendingConfig() {
+	changes, applied := s.swapPendingConfig()
+	if !applied {
+		return
 	}
-	return nil
+
+	s.bot.ctx.Logger.Info("Config reloaded", slog.String("supervisor", s.name), slog.String("changes", strings.Join(changes, ", ")))
+	event.Send(event.ConfigReloaded(event.Text(s.name, "Config reloaded: "+strings.Join(changes, ", ")), changes))
 }
 
-func (s *baseSupervisor) ensureProcessIsRunningAndPrepare() error {
-	// Prevent screen from turning off
-	winproc.SetThreadExecutionState.Call(winproc.EXECUTION_STATE_ES_DISPLAY_REQUIRED | winproc.EXECUTION_STATE_ES_CONTINUOUS)
+// swapPendingConfig replaces the config with a new pointer holding the queued config, the current one is never
+// written so readers holding it keep a consistent config. Runtime data (drops and the public game counter) is kept,
+// the pickit rules come from the reloaded config.
+func (s *baseSupervisor) swapPendingConfig() ([]string, bool) {
+	s.pendingMu.Lock()
+	cfg, changes := s.pendingCfg, s.pendingChanges
+	s.pendingCfg, s.pendingChanges = nil, nil
+	s.pendingMu.Unlock()
+
+	if cfg == nil {
+		return nil, false
+	}
+
+	current := s.cfg.Load()
+	reloaded := *cfg
+	reloaded.Runtime.Drops = current.Runtime.Drops
+	reloaded.Game.PublicGameCounter = current.Game.PublicGameCounter
 
-	return s.bot.ctx.MemoryInjector.Load()
+	s.bot.ctx.CharacterCfg = &reloaded
+	s.bot.ctx.GameReader.SetCharacterConfig(&reloaded)
+	if s.bot.ctx.PathFinder != nil {
+		s.bot.ctx.PathFinder.SetCharacterConfig(&reloaded)
+	}
+	s.cfg.Store(&reloaded)
+
+	slices.Sort(changes)
+	return changes, true
 }
 
 func (s *baseSupervisor) logGameStart(runs []run.Run) {
@@ -131,7 +184,7 @@ func (s *baseSupervisor) waitUntilCharacterSelectionScreen() error {
 		s.bot.ctx.Logger.Info("Selecting character...")
 		previousSelection := ""
 		for {
-			characterName := s.bot.ctx.GameReader.GameReader.GetSelectedCharacterName()
+			characterName := s.selectedCharacterName()
 			if strings.EqualFold(previousSelection, characterName) {
 				return fmt.Errorf("character %s not found", s.bot.ctx.CharacterCfg.CharacterName)
 			}
@@ -140,7 +193,7 @@ func (s *baseSupervisor) waitUntilCharacterSelectionScreen() error {
 				return nil
 			}
 
-			s.bot.ctx.HID.PressKey(win.VK_DOWN)
+			s.bot.ctx.HID.PressKey(game.KeyDown)
 			time.Sleep(time.Millisecond * 150)
 			previousSelection = characterName
 		}
@@ -148,8 +201,3 @@ func (s *baseSupervisor) waitUntilCharacterSelectionScreen() error {
 
 	return nil
 }
-
-func (s *baseSupervisor) SetWindowPosition(x, y int) {
-	uFlags := win.SWP_NOZORDER | win.SWP_NOSIZE | win.SWP_NOACTIVATE
-	win.SetWindowPos(s.bot.ctx.GameReader.HWND, 0, int32(x), int32(y), 0, 0, uint32(uFlags))
-}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package bot
+
+import "errors"
+
+// There is no game process outside Windows, supervisors can't be built so these are never reached
+
+func (s *baseSupervisor) KillClient() error {
+	return errors.New("game clients can only be killed on Windows")
+}
+
+func (s *baseSupervisor) ensureProcessIsRunningAndPrepare() error {
+	return s.bot.ctx.MemoryInjector.Load()
+}
+
+func (s *baseSupervisor) selectedCharacterName() string {
+	return ""
+}
+
+func (s *baseSupervisor) SetWindowPosition(_, _ int) {}
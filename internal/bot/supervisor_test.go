@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/config"
+	ct "github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+// configReader only records the config handed to the game reader, the swap never reads game data
+type configReader struct {
+	game.GameReader
+	cfg *config.CharacterCfg
+}
+
+func (r *configReader) SetCharacterConfig(cfg *config.CharacterCfg) {
+	r.cfg = cfg
+}
+
+func newTestSupervisor(t *testing.T, cfg *config.CharacterCfg) (*baseSupervisor, *configReader) {
+	t.Helper()
+
+	reader := &configReader{cfg: cfg}
+	s, err := newBaseSupervisor(NewBot(&ct.Context{CharacterCfg: cfg, GameReader: reader}), "sorc1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, reader
+}
+
+func TestQueueConfigKeepsTheLatestConfig(t *testing.T) {
+	s, _ := newTestSupervisor(t, &config.CharacterCfg{})
+
+	first := &config.CharacterCfg{}
+	first.Health.ChickenAt = 40
+	second := &config.CharacterCfg{}
+	second.Health.ChickenAt = 50
+	s.QueueConfig(first, []string{"health.chickenAt", "game.runs"})
+	s.QueueConfig(second, []string{"health.chickenAt", "pickit/runes.nip"})
+
+	changes, applied := s.swapPendingConfig()
+	if !applied {
+		t.Fatal("expected the queued config to be applied")
+	}
+	if want := []string{"game.runs", "health.chickenAt", "pickit/runes.nip"}; !slices.Equal(changes, want) {
+		t.Errorf("expected changes %v, got %v", want, changes)
+	}
+	if got := s.CurrentConfig().Health.ChickenAt; got != 50 {
+		t.Errorf("expected the last queued config to win, got chickenAt %d", got)
+	}
+
+	if _, applied = s.swapPendingConfig(); applied {
+		t.Error("expected nothing to apply once the queue was consumed")
+	}
+}
+
+func TestSwapPendingConfigKeepsRuntimeData(t *testing.T) {
+	current := &config.CharacterCfg{}
+	current.Health.ChickenAt = 30
+	current.Runtime.Drops = []data.Item{{}, {}}
+	current.Game.PublicGameCounter = 7
+	s, reader := newTestSupervisor(t, current)
+
+	queued := &config.CharacterCfg{}
+	queued.Health.ChickenAt = 40
+	s.QueueConfig(queued, []string{"health.chickenAt"})
+	if s.CurrentConfig() != current {
+		t.Fatal("expected queueing to leave the current config in place")
+	}
+
+	if _, applied := s.swapPendingConfig(); !applied {
+		t.Fatal("expected the queued config to be applied")
+	}
+
+	reloaded := s.CurrentConfig()
+	if reloaded == current || reloaded == queued {
+		t.Fatal("expected the reloaded config to be published as a new pointer")
+	}
+	if reloaded.Health.ChickenAt != 40 {
+		t.Errorf("expected chickenAt 40, got %d", reloaded.Health.ChickenAt)
+	}
+	if len(reloaded.Runtime.Drops) != 2 || reloaded.Game.PublicGameCounter != 7 {
+		t.Errorf("expected drops and the public game counter to be kept, got %d drops and counter %d", len(reloaded.Runtime.Drops), reloaded.Game.PublicGameCounter)
+	}
+	if current.Health.ChickenAt != 30 {
+		t.Errorf("expected the previous config to stay untouched, got chickenAt %d", current.Health.ChickenAt)
+	}
+	if s.bot.ctx.CharacterCfg != reloaded || reader.cfg != reloaded {
+		t.Error("expected the context and the game reader to use the reloaded config")
+	}
+}
@@ -0,0 +1,42 @@
+//go:build windows
+
+package bot
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/hectorgimenez/koolo/internal/utils/winproc"
+	"github.com/lxn/win"
+)
+
+func (s *baseSupervisor) KillClient() error {
+
+	process, err := os.FindProcess(int(s.bot.ctx.MemoryReader.Process.GetPID()))
+	if err != nil {
+		s.bot.ctx.Logger.Info("Failed to find process", slog.String("configuration", s.name))
+		return err
+	}
+	err = process.Kill()
+	if err != nil {
+		s.bot.ctx.Logger.Info("Failed to kill process", slog.String("configuration", s.name))
+		return err
+	}
+	return nil
+}
+
+func (s *baseSupervisor) ensureProcessIsRunningAndPrepare() error {
+	// Prevent screen from turning off
+	winproc.SetThreadExecutionState.Call(winproc.EXECUTION_STATE_ES_DISPLAY_REQUIRED | winproc.EXECUTION_STATE_ES_CONTINUOUS)
+
+	return s.bot.ctx.MemoryInjector.Load()
+}
+
+func (s *baseSupervisor) selectedCharacterName() string {
+	return s.bot.ctx.MemoryReader.GameReader.GetSelectedCharacterName()
+}
+
+func (s *baseSupervisor) SetWindowPosition(x, y int) {
+	uFlags := win.SWP_NOZORDER | win.SWP_NOSIZE | win.SWP_NOACTIVATE
+	win.SetWindowPos(s.bot.ctx.MemoryReader.HWND, 0, int32(x), int32(y), 0, 0, uint32(uFlags))
+}
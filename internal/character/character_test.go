@@ -0,0 +1,27 @@
+package character
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/context"
+)
+
+// The config validation accepts the classes listed in the config package, all of them must be buildable
+func TestBuildCharacterClasses(t *testing.T) {
+	build := func(class string, runs ...config.Run) {
+		cfg := &config.CharacterCfg{}
+		cfg.Character.Class = class
+		cfg.Game.Runs = runs
+		if _, err := BuildCharacter(&context.Context{CharacterCfg: cfg}); err != nil {
+			t.Errorf("class %s: %v", class, err)
+		}
+	}
+
+	for _, class := range config.AvailableClasses {
+		build(class)
+	}
+	for _, class := range config.LevelingClasses {
+		build(class, config.LevelingRun)
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/hectorgimenez/d2go/pkg/nip"
+	"gopkg.in/yaml.v3"
+)
+
+// ChangedFields returns the yaml path of every field with a different value in the two configs, like health.chickenAt.
+// Pickit rule changes are reported per file as pickit/{file}, the rest of the runtime data is never reported.
+func ChangedFields(before, after *CharacterCfg) []string {
+	beforeNode, afterNode := yaml.Node{}, yaml.Node{}
+	if err := beforeNode.Encode(before); err != nil {
+		return nil
+	}
+	if err := afterNode.Encode(after); err != nil {
+		return nil
+	}
+
+	var fields []string
+	collectChanges(&beforeNode, &afterNode, "", &fields)
+	fields = append(fields, changedRuleFiles(before.Runtime.Rules, after.Runtime.Rules)...)
+	slices.Sort(fields)
+
+	return fields
+}
+
+func collectChanges(before, after *yaml.Node, path string, fields *[]string) {
+	if before.Kind != yaml.MappingNode || after.Kind != yaml.MappingNode {
+		if !nodesEqual(before, after) {
+			*fields = append(*fields, path)
+		}
+		return
+	}
+
+	keys := make(map[string]bool)
+	for _, m := range []*yaml.Node{before, after} {
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			keys[m.Content[i].Value] = true
+		}
+	}
+	for key := range keys {
+		field := key
+		if path != "" {
+			field = path + "." + key
+		}
+
+		beforeValue, afterValue := mappingValue(before, key), mappingValue(after, key)
+		if beforeValue == nil || afterValue == nil {
+			*fields = append(*fields, field)
+			continue
+		}
+		collectChanges(beforeValue, afterValue, field, fields)
+	}
+}
+
+func changedRuleFiles(before, after nip.Rules) []string {
+	lines := func(rules nip.Rules) map[string][]string {
+		byFile := make(map[string][]string)
+		for _, rule := range rules {
+			file := filepath.Base(rule.Filename)
+			byFile[file] = append(byFile[file], rule.RawLine)
+		}
+		return byFile
+	}
+	beforeLines, afterLines := lines(before), lines(after)
+
+	var files []string
+	for file, rules := range afterLines {
+		if !slices.Equal(beforeLines[file], rules) {
+			files = append(files, "pickit/"+file)
+		}
+	}
+	for file := range beforeLines {
+		if _, found := afterLines[file]; !found {
+			files = append(files, "pickit/"+file)
+		}
+	}
+
+	return files
+}
+
+// CharacterFiles returns the files the character config is read from: its config.yaml, the profiles and the pickit
+// rules, used to detect changes on disk
+func CharacterFiles(name string, cfg *CharacterCfg) []string {
+	configDir := "config"
+	files := []string{filepath.Join(configDir, name, "config.yaml")}
+
+	profiles, _ := filepath.Glob(filepath.Join(configDir, ProfilesDir, "*.yaml"))
+	files = append(files, profiles...)
+
+	pickitDir := filepath.Join(configDir, name, "pickit")
+	if Koolo != nil && Koolo.CentralizedPickitPath != "" && cfg.UseCentralizedPickit {
+		if _, err := os.Stat(Koolo.CentralizedPickitPath); err == nil {
+			pickitDir = Koolo.CentralizedPickitPath
+		}
+	}
+	for _, dir := range []string{pickitDir, filepath.Join(configDir, name, "pickit_leveling")} {
+		rules, _ := filepath.Glob(filepath.Join(dir, "*.nip"))
+		files = append(files, rules...)
+	}
+
+	return files
+}
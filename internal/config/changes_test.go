@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestChangedFields(t *testing.T) {
+	previous := Koolo
+	Koolo = &KooloCfg{}
+	t.Cleanup(func() { Koolo = previous })
+
+	dir := writeConfigFiles(t, map[string]string{
+		"sorc1/config.yaml":      "characterName: sorc1\nhealth:\n  chickenAt: 30\n",
+		"sorc1/pickit/rings.nip": "[name] == ring && [quality] == unique\n",
+		"sorc1/pickit/runes.nip": "[name] == berrune\n",
+	})
+
+	before, err := loadCharacter(dir, "sorc1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes := ChangedFields(before, before); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "sorc1", name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("config.yaml", "characterName: sorc1\nhealth:\n  chickenAt: 40\ngame:\n  runs: [ pit ]\n")
+	write(filepath.Join("pickit", "runes.nip"), "[name] == berrune\n[name] == jahrune\n")
+
+	after, err := loadCharacter(dir, "sorc1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"game.runs", "health.chickenAt", "pickit/runes.nip"}
+	if changes := ChangedFields(before, after); !slices.Equal(changes, want) {
+		t.Errorf("expected %v, got %v", want, changes)
+	}
+
+	files := CharacterFiles("sorc1", after)
+	if files[0] != filepath.Join("config", "sorc1", "config.yaml") {
+		t.Errorf("expected the character config first, got %v", files)
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+// AvailableClasses are the class values the bot can build a character for
+var AvailableClasses = []string{
+	"sorceress",
+	"fireballsorc",
+	"nova",
+	"hydraorb",
+	"lightsorc",
+	"hammerdin",
+	"foh",
+	"trapsin",
+	"mosaic",
+	"winddruid",
+	"javazon",
+	"berserker",
+}
+
+// LevelingClasses are the class values allowed when leveling is the first run
+var LevelingClasses = []string{
+	"sorceress_leveling",
+	"sorceress_leveling_lightning",
+	"paladin",
+}
@@ -30,10 +30,13 @@ var (
 )
 
 type KooloCfg struct {
+	Version int `yaml:"version"`
+
 	Debug struct {
 		Log         bool `yaml:"log"`
 		Screenshots bool `yaml:"screenshots"`
-		RenderMap   bool `yaml:"renderMap"`
+		// RecordGameData writes the game data read by the bot to the log directory, recordings can be replayed later
+		RecordGameData bool `yaml:"recordGameData"`
 	} `yaml:"debug"`
 	FirstRun              bool   `yaml:"firstRun"`
 	UseCustomSettings     bool   `yaml:"useCustomSettings"`
@@ -42,7 +45,18 @@ type KooloCfg struct {
 	D2LoDPath             string `yaml:"D2LoDPath"`
 	D2RPath               string `yaml:"D2RPath"`
 	CentralizedPickitPath string `yaml:"centralizedPickitPath"`
-	Discord               struct {
+	MapCache              struct {
+		Enabled   bool   `yaml:"enabled"`
+		Directory string `yaml:"directory"`
+		MaxSizeMB int    `yaml:"maxSizeMB"`
+	} `yaml:"mapCache"`
+	// CrashDetector probes restart clients that are still running but stopped working, 0 disables the probe
+	CrashDetector struct {
+		FrozenGameDataSeconds     int `yaml:"frozenGameDataSeconds"`
+		LoadingScreenSeconds      int `yaml:"loadingScreenSeconds"`
+		UnresponsiveWindowSeconds int `yaml:"unresponsiveWindowSeconds"`
+	} `yaml:"crashDetector"`
+	Discord struct {
 		Enabled                      bool     `yaml:"enabled"`
 		EnableGameCreatedMessages    bool     `yaml:"enableGameCreatedMessages"`
 		EnableNewRunMessages         bool     `yaml:"enableNewRunMessages"`
@@ -57,6 +71,21 @@ type KooloCfg struct {
 		ChatID  int64  `yaml:"chatId"`
 		Token   string `yaml:"token"`
 	}
+	// Secrets is the encrypted store read by secret://{name} config values, File defaults to config/secrets.json and
+	// the store is unlocked with KeyFile, the KOOLO_SECRETS_PASSPHRASE variable or from the web UI
+	Secrets struct {
+		File    string `yaml:"file"`
+		KeyFile string `yaml:"keyFile"`
+	} `yaml:"secrets"`
+}
+
+// SecretsFile returns the path of the encrypted secrets store
+func (c *KooloCfg) SecretsFile() string {
+	if c.Secrets.File != "" {
+		return c.Secrets.File
+	}
+
+	return filepath.Join("config", "secrets.json")
 }
 
 type Day struct {
@@ -75,6 +104,8 @@ type TimeRange struct {
 }
 
 type CharacterCfg struct {
+	Version              int    `yaml:"version"`
+	Extends              string `yaml:"extends,omitempty"`
 	MaxGameLength        int    `yaml:"maxGameLength"`
 	Username             string `yaml:"username"`
 	Password             string `yaml:"password"`
@@ -88,6 +119,7 @@ type CharacterCfg struct {
 	CloseMiniPanel       bool   `yaml:"closeMiniPanel"`
 	UseCentralizedPickit bool   `yaml:"useCentralizedPickit"`
 	HidePortraits        bool   `yaml:"hidePortraits"`
+	LayoutProfile        string `yaml:"layoutProfile"`
 
 	Scheduler Scheduler `yaml:"scheduler"`
 	Health    struct {
@@ -123,6 +155,17 @@ type CharacterCfg struct {
 			UseBladesOfIce    bool `yaml:"useBladesOfIce"`
 			UseFistsOfFire    bool `yaml:"useFistsOfFire"`
 		} `yaml:"mosaic_sin"`
+		ThreatAvoidance struct {
+			Enabled bool `yaml:"enabled"`
+			// Radius and Weight default to the class values when not set
+			Radius int `yaml:"radius"`
+			Weight int `yaml:"weight"`
+		} `yaml:"threat_avoidance"`
+		Kiting struct {
+			Enabled bool `yaml:"enabled"`
+			// DangerRadius defaults to 6 tiles when not set
+			DangerRadius int `yaml:"danger_radius"`
+		} `yaml:"kiting"`
 	} `yaml:"character"`
 
 	Game struct {
@@ -280,6 +323,9 @@ func (bm BeltColumns) Total(potionType data.PotionType) int {
 	return total
 }
 
+// LayoutsDir is the directory inside config holding the UI layout profiles, it isn't a character config
+const LayoutsDir = "layouts"
+
 // Load reads the config.ini file and returns a Config struct filled with data from the ini file
 func Load() error {
 	Characters = make(map[string]*CharacterCfg)
@@ -296,6 +342,9 @@ func Load() error {
 	}
 
 	kooloPath := getAbsPath("config/koolo.yaml")
+	if err = migrateFile(kooloPath, kooloMigrations); err != nil && !os.IsNotExist(err) {
+		return err
+	}
 	r, err := os.Open(kooloPath)
 	if err != nil {
 		return fmt.Errorf("error loading koolo.yaml: %w", err)
@@ -313,71 +362,100 @@ func Load() error {
 		return fmt.Errorf("error reading config directory %s: %w", configDir, err)
 	}
 
+	// Profiles are migrated first, character configs are read merged over them
+	profiles, _ := filepath.Glob(filepath.Join(configDir, ProfilesDir, "*.yaml"))
+	for _, profilePath := range profiles {
+		if err = migrateFile(profilePath, characterMigrations); err != nil {
+			return err
+		}
+	}
+
 	// Read character configs
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.IsDir() || isReservedDir(entry.Name()) {
 			continue
 		}
 
-		charCfg := CharacterCfg{}
-
 		// Load character config from the current working directory/config/{charName}/config.yaml
-		charConfigPath := getAbsPath(filepath.Join("config", entry.Name(), "config.yaml"))
-		r, err = os.Open(charConfigPath)
-		if err != nil {
-			return fmt.Errorf("error loading config.yaml: %w", err)
+		charConfigPath := filepath.Join(configDir, entry.Name(), "config.yaml")
+		if err = migrateFile(charConfigPath, characterMigrations); err != nil && !os.IsNotExist(err) {
+			return err
 		}
-		defer r.Close()
 
-		// Load character config
-		d := yaml.NewDecoder(r)
-		if err = d.Decode(&charCfg); err != nil {
-			return fmt.Errorf("error reading %s character config: %w", charConfigPath, err)
+		charCfg, err := loadCharacter(configDir, entry.Name())
+		if err != nil {
+			return err
 		}
+		Characters[entry.Name()] = charCfg
+	}
 
-		var pickitPath string
+	// Validate configs
+	for _, charCfg := range Characters {
+		charCfg.Validate()
+	}
 
-		if Koolo.CentralizedPickitPath != "" && charCfg.UseCentralizedPickit {
-			// Validate centralized pickit path
-			if _, err := os.Stat(Koolo.CentralizedPickitPath); os.IsNotExist(err) {
-				utils.ShowDialog("Error loading pickit rules for "+entry.Name(), "The centralized pickit path does not exist: "+Koolo.CentralizedPickitPath+"\nPlease check your Koolo settings.\nFalling back to local pickit.")
+	return nil
+}
 
-				// Set the pickit path to the current dir/config/{charName}/pickit
-				pickitPath = getAbsPath(filepath.Join("config", entry.Name(), "pickit")) + "\\"
-			} else {
-				pickitPath = Koolo.CentralizedPickitPath + "\\"
-			}
-		} else {
-			// Set the pickit path to the current dir/config/{charName}/pickit
-			pickitPath = getAbsPath(filepath.Join("config", entry.Name(), "pickit")) + "\\"
-		}
+// LoadCharacter reads the config and pickit rules of a single character from disk, Koolo and the other characters are
+// left untouched
+func LoadCharacter(name string) (*CharacterCfg, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current working directory: %w", err)
+	}
 
-		// Load the pickit rules from the directory
-		rules, err := nip.ReadDir(pickitPath)
-		if err != nil {
-			return fmt.Errorf("error reading pickit directory %s: %w", pickitPath, err)
-		}
+	charCfg, err := loadCharacter(filepath.Join(cwd, "config"), name)
+	if err != nil {
+		return nil, err
+	}
+	charCfg.Validate()
+
+	return charCfg, nil
+}
+
+func loadCharacter(configDir, name string) (*CharacterCfg, error) {
+	charCfg := CharacterCfg{}
+
+	// Load character config, merged over the profile it extends
+	charConfigPath := filepath.Join(configDir, name, "config.yaml")
+	doc, err := readCharacterDocument(configDir, charConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config.yaml: %w", err)
+	}
+	if err = doc.Decode(&charCfg); err != nil {
+		return nil, fmt.Errorf("error reading %s character config: %w", charConfigPath, err)
+	}
 
-		// Load the leveling pickit rules
-		if len(charCfg.Game.Runs) > 0 && charCfg.Game.Runs[0] == "leveling" {
-			levelingPickitPath := getAbsPath(filepath.Join("config", entry.Name(), "pickit_leveling")) + "\\"
-			levelingRules, err := nip.ReadDir(levelingPickitPath)
-			if err != nil {
-				return fmt.Errorf("error reading pickit_leveling directory %s: %w", levelingPickitPath, err)
-			}
-			rules = append(rules, levelingRules...)
+	pickitPath := filepath.Join(configDir, name, "pickit") + string(filepath.Separator)
+	if Koolo.CentralizedPickitPath != "" && charCfg.UseCentralizedPickit {
+		// Validate centralized pickit path
+		if _, err := os.Stat(Koolo.CentralizedPickitPath); os.IsNotExist(err) {
+			utils.ShowDialog("Error loading pickit rules for "+name, "The centralized pickit path does not exist: "+Koolo.CentralizedPickitPath+"\nPlease check your Koolo settings.\nFalling back to local pickit.")
+		} else {
+			pickitPath = Koolo.CentralizedPickitPath + string(filepath.Separator)
 		}
+	}
 
-		charCfg.Runtime.Rules = rules
-		Characters[entry.Name()] = &charCfg
+	// Load the pickit rules from the directory
+	rules, err := nip.ReadDir(pickitPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading pickit directory %s: %w", pickitPath, err)
 	}
 
-	// Validate configs
-	for _, charCfg := range Characters {
-		charCfg.Validate()
+	// Load the leveling pickit rules
+	if len(charCfg.Game.Runs) > 0 && charCfg.Game.Runs[0] == "leveling" {
+		levelingPickitPath := filepath.Join(configDir, name, "pickit_leveling") + string(filepath.Separator)
+		levelingRules, err := nip.ReadDir(levelingPickitPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading pickit_leveling directory %s: %w", levelingPickitPath, err)
+		}
+		rules = append(rules, levelingRules...)
 	}
 
-	return nil
+	charCfg.Runtime.Rules = rules
+
+	return &charCfg, nil
 }
 
 func CreateFromTemplate(name string) error {
@@ -385,6 +463,10 @@ func CreateFromTemplate(name string) error {
 		return errors.New("name cannot be empty")
 	}
 
+	if isReservedDir(name) {
+		return errors.New("name is reserved for the UI layout and config profiles")
+	}
+
 	if _, err := os.Stat("config/" + name); !os.IsNotExist(err) {
 		return errors.New("configuration with that name already exists")
 	}
@@ -411,6 +493,7 @@ func ValidateAndSaveConfig(config KooloCfg) error {
 		return errors.New("D2RPath is not valid")
 	}
 
+	config.Version = KooloConfigVersion
 	text, err := yaml.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("error parsing koolo config: %w", err)
@@ -426,7 +509,8 @@ func ValidateAndSaveConfig(config KooloCfg) error {
 
 func SaveSupervisorConfig(supervisorName string, config *CharacterCfg) error {
 	filePath := filepath.Join("config", supervisorName, "config.yaml")
-	d, err := yaml.Marshal(config)
+	config.Version = CharacterConfigVersion
+	d, err := marshalCharacterConfig("config", config)
 	config.Validate()
 	if err != nil {
 		return err
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package config
+
+// GetCurrentDisplayScale always returns 1, display scaling is only read from Windows
+func GetCurrentDisplayScale() float64 {
+	return 1.0
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema versions written by this release, files with a lower version are migrated when loaded. Bump the version and
+// add a migration every time a key is renamed, moved or added with a default value other than the zero value.
+const (
+	KooloConfigVersion     = 1
+	CharacterConfigVersion = 2
+)
+
+const (
+	versionKey     = "version"
+	versionComment = "# Config schema version, Koolo updates it when migrating the file"
+)
+
+// migration rewrites a config document from the previous schema version to version, it works on the YAML nodes so
+// keys unknown to the current structs, comments and the key order are kept
+type migration struct {
+	version     int
+	description string
+	apply       func(root *yaml.Node) error
+}
+
+var kooloMigrations = []migration{
+	{
+		version:     1,
+		description: "add the map cache and crash detector settings with their default values",
+		apply: func(root *yaml.Node) error {
+			setDefault(root, "mapCache", mappingNode(
+				"enabled", "true",
+				"directory", "cache/maps",
+				"maxSizeMB", "512",
+			))
+			setDefault(root, "crashDetector", mappingNode(
+				"frozenGameDataSeconds", "60",
+				"loadingScreenSeconds", "60",
+				"unresponsiveWindowSeconds", "30",
+			))
+
+			return nil
+		},
+	},
+}
+
+var characterMigrations = []migration{
+	{
+		version:     1,
+		description: "move enableCubeRecipes to cubing.enabled",
+		apply: func(root *yaml.Node) error {
+			enabled := removeKey(root, "enableCubeRecipes")
+			if enabled == nil {
+				return nil
+			}
+
+			setDefault(childMapping(root, "cubing"), "enabled", enabled)
+			return nil
+		},
+	},
+	{
+		version:     2,
+		description: "rename game.diablo.onlyElites to focusOnElitePacks and remove game.diablo.clearArea",
+		apply: func(root *yaml.Node) error {
+			diablo := mappingValue(mappingValue(root, "game"), "diablo")
+			if diablo == nil || diablo.Kind != yaml.MappingNode {
+				return nil
+			}
+
+			removeKey(diablo, "clearArea")
+			if onlyElites := removeKey(diablo, "onlyElites"); onlyElites != nil {
+				setDefault(diablo, "focusOnElitePacks", onlyElites)
+			}
+
+			return nil
+		},
+	},
+}
+
+// migrateDocument applies the pending migrations to the YAML document b, it returns the document version before
+// migrating. Documents already at the latest version are returned untouched.
+func migrateDocument(b []byte, migrations []migration) ([]byte, int, error) {
+	doc := yaml.Node{}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, 0, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, 0, errors.New("config must be a YAML mapping")
+	}
+	root := doc.Content[0]
+
+	from := 0
+	if v := mappingValue(root, versionKey); v != nil {
+		var err error
+		if from, err = strconv.Atoi(v.Value); err != nil {
+			return nil, 0, fmt.Errorf("invalid config version %q", v.Value)
+		}
+	}
+
+	latest := migrations[len(migrations)-1].version
+	if from > latest {
+		return nil, from, fmt.Errorf("config version %d was written by a newer Koolo release, latest known version is %d", from, latest)
+	}
+	if from == latest {
+		return b, from, nil
+	}
+
+	for _, m := range migrations {
+		if m.version <= from {
+			continue
+		}
+		if err := m.apply(root); err != nil {
+			return nil, from, fmt.Errorf("error migrating config to version %d (%s): %w", m.version, m.description, err)
+		}
+	}
+	setVersion(root, latest)
+
+	buf := bytes.Buffer{}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, from, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, from, err
+	}
+
+	return buf.Bytes(), from, nil
+}
+
+// migrateFile migrates the config file in place, the original file is kept next to it as {path}.v{version}.bak
+func migrateFile(path string, migrations []migration) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	migrated, from, err := migrateDocument(b, migrations)
+	if err != nil {
+		return fmt.Errorf("error migrating %s: %w", path, err)
+	}
+	if bytes.Equal(migrated, b) {
+		return nil
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d.bak", path, from)
+	if err = os.WriteFile(backupPath, b, 0644); err != nil {
+		return fmt.Errorf("error writing config backup %s: %w", backupPath, err)
+	}
+	if err = os.WriteFile(path, migrated, 0644); err != nil {
+		return fmt.Errorf("error writing migrated config %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// setVersion writes the version as the first key of the document
+func setVersion(root *yaml.Node, version int) {
+	if v := mappingValue(root, versionKey); v != nil {
+		v.Tag, v.Value = "!!int", strconv.Itoa(version)
+		return
+	}
+
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: versionKey}
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version), LineComment: versionComment}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
+
+// mappingValue returns the value of key in the mapping m, nil if m is not a mapping or the key is missing
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// setDefault adds key to the mapping m, values already set by the user are kept
+func setDefault(m *yaml.Node, key string, value *yaml.Node) {
+	if mappingValue(m, key) != nil {
+		return
+	}
+
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// removeKey removes key from the mapping m and returns its value, nil if it wasn't set
+func removeKey(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			value := m.Content[i+1]
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return value
+		}
+	}
+
+	return nil
+}
+
+// childMapping returns the mapping under key, it's created when missing
+func childMapping(m *yaml.Node, key string) *yaml.Node {
+	if child := mappingValue(m, key); child != nil && child.Kind == yaml.MappingNode {
+		return child
+	}
+
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	removeKey(m, key)
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+
+	return child
+}
+
+// mappingNode builds a mapping from key and value pairs, values are parsed as plain YAML scalars
+func mappingNode(pairs ...string) *yaml.Node {
+	m := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		m.Content = append(m.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: pairs[i]},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: pairs[i+1]},
+		)
+	}
+
+	return m
+}
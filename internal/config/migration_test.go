@@ -0,0 +1,141 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the migration golden files")
+
+// Every file in testdata/migrations is migrated and compared with its .golden file, run with -update to rewrite them
+func TestMigrationsGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "migrations", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			migrations := characterMigrations
+			if strings.HasPrefix(name, "koolo") {
+				migrations = kooloMigrations
+			}
+
+			b, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _, err := migrateDocument(b, migrations)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goldenPath := strings.TrimSuffix(input, ".yaml") + ".golden"
+			if *updateGolden {
+				if err = os.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("migrated document doesn't match %s\ngot:\n%s\nwant:\n%s", goldenPath, got, want)
+			}
+
+			// Migrating again must not change anything
+			again, _, err := migrateDocument(got, migrations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again) != string(got) {
+				t.Errorf("migrating an already migrated document changed it:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestMigratedDocumentDecodes(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "migrations", "character_v0.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, from, err := migrateDocument(b, characterMigrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != 0 {
+		t.Errorf("expected a document without version to be version 0, got %d", from)
+	}
+
+	cfg := CharacterCfg{}
+	if err = yaml.Unmarshal(b, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Version != CharacterConfigVersion || !cfg.CubeRecipes.Enabled || !cfg.Game.Diablo.FocusOnElitePacks {
+		t.Errorf("unexpected migrated config: version %d, cubing %v, diablo elites %v", cfg.Version, cfg.CubeRecipes.Enabled, cfg.Game.Diablo.FocusOnElitePacks)
+	}
+}
+
+func TestShippedConfigsAreCurrent(t *testing.T) {
+	files := map[string]int{
+		filepath.Join("..", "..", "config", "template", "config.yaml"): CharacterConfigVersion,
+		filepath.Join("..", "..", "config", "koolo.yaml.dist"):         KooloConfigVersion,
+	}
+	for path, version := range files {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v := struct {
+			Version int `yaml:"version"`
+		}{}
+		if err = yaml.Unmarshal(b, &v); err != nil {
+			t.Fatal(err)
+		}
+		if v.Version != version {
+			t.Errorf("%s is at version %d, expected %d", path, v.Version, version)
+		}
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	original := []byte("enableCubeRecipes: false\n")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrateFile(path, characterMigrations); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(backup) != string(original) {
+		t.Errorf("expected the original document in the backup, got %s", backup)
+	}
+	migrated, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(migrated), "version: 2") {
+		t.Errorf("expected the migrated document, got %s", migrated)
+	}
+
+	if err = os.WriteFile(path, []byte("version: 99\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = migrateFile(path, characterMigrations); err == nil {
+		t.Error("expected an error for a document from a newer release")
+	}
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfilesDir is the directory inside config holding the shared profiles, character configs set "extends: {name}" to
+// use config/profiles/{name}.yaml as the base for every field they don't set
+const ProfilesDir = "profiles"
+
+const extendsKey = "extends"
+
+// isReservedDir returns true for the directories inside config that aren't character configs
+func isReservedDir(name string) bool {
+	return name == LayoutsDir || name == ProfilesDir
+}
+
+// readCharacterDocument reads the character config at path merged over the profiles it extends, documents are
+// migrated in memory
+func readCharacterDocument(configDir, path string) (*yaml.Node, error) {
+	root, err := readConfigDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeProfile(configDir, root, nil)
+}
+
+func readProfileDocument(configDir, name string, chain []string) (*yaml.Node, error) {
+	if slices.Contains(chain, name) {
+		return nil, fmt.Errorf("profile %s extends itself: %s", name, strings.Join(append(chain, name), " -> "))
+	}
+
+	root, err := readConfigDocument(filepath.Join(configDir, ProfilesDir, name+".yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading profile %s: %w", name, err)
+	}
+
+	return mergeProfile(configDir, root, append(chain, name))
+}
+
+// mergeProfile returns root merged over the profile it extends, if any
+func mergeProfile(configDir string, root *yaml.Node, chain []string) (*yaml.Node, error) {
+	extends := mappingValue(root, extendsKey)
+	if extends == nil || extends.Value == "" {
+		return root, nil
+	}
+
+	base, err := readProfileDocument(configDir, extends.Value, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeNodes(base, root), nil
+}
+
+func readConfigDocument(path string) (*yaml.Node, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if b, _, err = migrateDocument(b, characterMigrations); err != nil {
+		return nil, err
+	}
+
+	doc := yaml.Node{}
+	if err = yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc.Content[0], nil
+}
+
+// mergeNodes deep merges two mappings, values from override win. Lists and scalars are replaced as a whole, so a
+// character listing its runs doesn't get the profile runs appended.
+func mergeNodes(base, override *yaml.Node) *yaml.Node {
+	if base.Kind != yaml.MappingNode || override.Kind != yaml.MappingNode {
+		return override
+	}
+
+	merged := *base
+	merged.Content = slices.Clone(base.Content)
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+
+		found := false
+		for j := 0; j+1 < len(merged.Content); j += 2 {
+			if merged.Content[j].Value == key.Value {
+				merged.Content[j+1] = mergeNodes(merged.Content[j+1], value)
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged.Content = append(merged.Content, key, value)
+		}
+	}
+
+	return &merged
+}
+
+// marshalCharacterConfig returns the YAML document to save for cfg, configs extending a profile only keep the fields
+// with a different value than the profile
+func marshalCharacterConfig(configDir string, cfg *CharacterCfg) ([]byte, error) {
+	if cfg.Extends == "" {
+		return yaml.Marshal(cfg)
+	}
+
+	profile, err := readProfileDocument(configDir, cfg.Extends, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Both sides are encoded from the struct, so values are compared in the same format
+	profileCfg := CharacterCfg{}
+	if err = profile.Decode(&profileCfg); err != nil {
+		return nil, fmt.Errorf("error reading profile %s: %w", cfg.Extends, err)
+	}
+	base, node := yaml.Node{}, yaml.Node{}
+	if err = base.Encode(&profileCfg); err != nil {
+		return nil, err
+	}
+	if err = node.Encode(cfg); err != nil {
+		return nil, err
+	}
+
+	overrides := diffNodes(&base, &node)
+	// The version and the profile are always written, even if they match the profile document
+	for _, key := range []string{extendsKey, versionKey} {
+		removeKey(overrides, key)
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+		overrides.Content = append([]*yaml.Node{keyNode, mappingValue(&node, key)}, overrides.Content...)
+	}
+
+	return yaml.Marshal(overrides)
+}
+
+// diffNodes returns the keys of the node mapping with a different value than in base
+func diffNodes(base, node *yaml.Node) *yaml.Node {
+	diff := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		baseValue := mappingValue(base, key.Value)
+		switch {
+		case baseValue == nil:
+			diff.Content = append(diff.Content, key, value)
+		case baseValue.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			if child := diffNodes(baseValue, value); len(child.Content) > 0 {
+				diff.Content = append(diff.Content, key, child)
+			}
+		case !nodesEqual(baseValue, value):
+			diff.Content = append(diff.Content, key, value)
+		}
+	}
+
+	return diff
+}
+
+func nodesEqual(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !nodesEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+var profileFiles = map[string]string{
+	"profiles/base.yaml": `
+health:
+  healingPotionAt: 70
+  chickenAt: 20
+game:
+  difficulty: hell
+  runs: [ pit, stony_tomb ]
+cubing:
+  enabled: true
+`,
+	"profiles/sorc.yaml": `
+extends: base
+character:
+  class: sorceress
+health:
+  chickenAt: 30
+`,
+	"sorc1/config.yaml": `
+extends: sorc
+characterName: sorc1
+game:
+  runs: [ mephisto ]
+`,
+}
+
+func readCharacter(t *testing.T, dir, name string) CharacterCfg {
+	t.Helper()
+
+	doc, err := readCharacterDocument(dir, filepath.Join(dir, name, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := CharacterCfg{}
+	if err = doc.Decode(&cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	return cfg
+}
+
+func TestReadCharacterDocumentMergesProfiles(t *testing.T) {
+	dir := writeConfigFiles(t, profileFiles)
+	cfg := readCharacter(t, dir, "sorc1")
+
+	if cfg.Extends != "sorc" || cfg.CharacterName != "sorc1" {
+		t.Errorf("expected the character fields, got extends %q and name %q", cfg.Extends, cfg.CharacterName)
+	}
+	if cfg.Character.Class != "sorceress" || cfg.Health.ChickenAt != 30 {
+		t.Errorf("expected the sorc profile fields, got class %q and chicken %d", cfg.Character.Class, cfg.Health.ChickenAt)
+	}
+	if cfg.Health.HealingPotionAt != 70 || cfg.Game.Difficulty != "hell" || !cfg.CubeRecipes.Enabled {
+		t.Errorf("expected the base profile fields, got %+v", cfg.Health)
+	}
+	// Lists are replaced, not appended
+	if len(cfg.Game.Runs) != 1 || cfg.Game.Runs[0] != MephistoRun {
+		t.Errorf("expected the character runs only, got %v", cfg.Game.Runs)
+	}
+}
+
+func TestReadCharacterDocumentProfileErrors(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"profiles/a.yaml":     "extends: b\n",
+		"profiles/b.yaml":     "extends: a\n",
+		"loop/config.yaml":    "extends: a\n",
+		"missing/config.yaml": "extends: nope\n",
+	})
+
+	for _, name := range []string{"loop", "missing"} {
+		if _, err := readCharacterDocument(dir, filepath.Join(dir, name, "config.yaml")); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMarshalCharacterConfigWritesOverrides(t *testing.T) {
+	dir := writeConfigFiles(t, profileFiles)
+	cfg := readCharacter(t, dir, "sorc1")
+	cfg.Health.ManaPotionAt = 15
+
+	b, err := marshalCharacterConfig(dir, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	overrides := map[string]any{}
+	if err = yaml.Unmarshal(b, &overrides); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"version":       CharacterConfigVersion,
+		"extends":       "sorc",
+		"characterName": "sorc1",
+		"health":        map[string]any{"manaPotionAt": 15},
+		"game":          map[string]any{"runs": []any{"mephisto"}},
+	}
+	for key := range overrides {
+		if _, found := want[key]; !found {
+			t.Errorf("unexpected override %s in:\n%s", key, b)
+		}
+	}
+	if !strings.HasPrefix(string(b), fmt.Sprintf("version: %d\nextends: sorc\n", CharacterConfigVersion)) {
+		t.Errorf("expected the version and profile first, got:\n%s", b)
+	}
+
+	// Reading the saved overrides back gives the same config
+	if err = os.WriteFile(filepath.Join(dir, "sorc1", "config.yaml"), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	saved := readCharacter(t, dir, "sorc1")
+	if saved.Health != cfg.Health || saved.CharacterName != cfg.CharacterName || len(saved.Game.Runs) != 1 || !saved.CubeRecipes.Enabled {
+		t.Errorf("saved config doesn't match, got %+v", saved.Health)
+	}
+}
@@ -0,0 +1,334 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/d2go/pkg/nip"
+	"github.com/hectorgimenez/koolo/internal/secrets"
+	"gopkg.in/yaml.v3"
+)
+
+type Severity string
+
+const (
+	// SeverityError issues stop the bot from starting, the config would crash or misbehave
+	SeverityError Severity = "error"
+	// SeverityWarning issues are allowed, but likely not what the user wants
+	SeverityWarning Severity = "warning"
+)
+
+const (
+	inventoryLockRows    = 4
+	inventoryLockColumns = 10
+	maxAttackDistance    = 25
+)
+
+// FieldIssue is a problem found in a single config field, Field is the yaml path of the field, like game.runs[2]
+type FieldIssue struct {
+	Field    string
+	Severity Severity
+	Message  string
+}
+
+func (i FieldIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Severity, i.Field, i.Message)
+}
+
+type ValidationIssues []FieldIssue
+
+func (vi ValidationIssues) HasErrors() bool {
+	for _, i := range vi {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// For returns the issues of the field and its children, For("game.runs") includes the issues of game.runs[2]
+func (vi ValidationIssues) For(field string) ValidationIssues {
+	var issues ValidationIssues
+	for _, i := range vi {
+		rest, found := strings.CutPrefix(i.Field, field)
+		if found && (rest == "" || rest[0] == '.' || rest[0] == '[') {
+			issues = append(issues, i)
+		}
+	}
+
+	return issues
+}
+
+func (vi *ValidationIssues) errorf(field, format string, args ...any) {
+	*vi = append(*vi, FieldIssue{Field: field, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+}
+
+func (vi *ValidationIssues) warnf(field, format string, args ...any) {
+	*vi = append(*vi, FieldIssue{Field: field, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateFields checks every field of the character config, it doesn't change the config like Validate does
+func (c *CharacterCfg) ValidateFields() ValidationIssues {
+	var vi ValidationIssues
+
+	if c.MaxGameLength < 0 {
+		vi.errorf("maxGameLength", "can not be negative")
+	}
+	switch c.AuthMethod {
+	case "", "None", "BattleNetClient", "TokenAuth":
+	case "UsernamePassword":
+		if c.Username == "" || c.Password == "" {
+			vi.errorf("authMethod", "UsernamePassword requires the username and password")
+		}
+	default:
+		vi.warnf("authMethod", "unknown auth method %q, the game will be started without authentication", c.AuthMethod)
+	}
+	for _, credential := range []struct{ field, value string }{{"password", c.Password}, {"authToken", c.AuthToken}} {
+		if _, _, err := secrets.ParseRef(credential.value); err != nil {
+			vi.errorf(credential.field, "%s, names can only have letters, digits, '.', '_' and '-'", err)
+		}
+	}
+
+	c.validateHealth(&vi)
+	c.validateInventory(&vi)
+	c.validateCharacter(&vi)
+	c.validateGame(&vi)
+
+	for i, recipe := range c.CubeRecipes.EnabledRecipes {
+		if !slices.Contains(AvailableRecipes, recipe) {
+			vi.warnf(fmt.Sprintf("cubing.enabledRecipes[%d]", i), "unknown recipe %q, it will be ignored", recipe)
+		}
+	}
+
+	return vi
+}
+
+func (c *CharacterCfg) validateHealth(vi *ValidationIssues) {
+	values := []struct {
+		field string
+		value int
+	}{
+		{"health.healingPotionAt", c.Health.HealingPotionAt},
+		{"health.manaPotionAt", c.Health.ManaPotionAt},
+		{"health.rejuvPotionAtLife", c.Health.RejuvPotionAtLife},
+		{"health.rejuvPotionAtMana", c.Health.RejuvPotionAtMana},
+		{"health.mercHealingPotionAt", c.Health.MercHealingPotionAt},
+		{"health.mercRejuvPotionAt", c.Health.MercRejuvPotionAt},
+		{"health.chickenAt", c.Health.ChickenAt},
+		{"health.mercChickenAt", c.Health.MercChickenAt},
+	}
+	for _, v := range values {
+		if v.value < 0 || v.value > 100 {
+			vi.errorf(v.field, "must be a percentage between 0 and 100, got %d", v.value)
+		}
+	}
+
+	// Thresholds are checked top to bottom, chicken at or above a potion threshold means the potion is never used
+	h := c.Health
+	if h.ChickenAt > 0 && h.ChickenAt >= h.HealingPotionAt && h.HealingPotionAt > 0 {
+		vi.warnf("health.chickenAt", "chicken at %d%% is not below the healing potion threshold (%d%%), healing potions will never be used", h.ChickenAt, h.HealingPotionAt)
+	}
+	if h.ChickenAt > 0 && h.ChickenAt >= h.RejuvPotionAtLife && h.RejuvPotionAtLife > 0 {
+		vi.warnf("health.chickenAt", "chicken at %d%% is not below the rejuvenation potion threshold (%d%%), rejuvenation potions will never be used", h.ChickenAt, h.RejuvPotionAtLife)
+	}
+	if h.RejuvPotionAtLife > h.HealingPotionAt {
+		vi.warnf("health.rejuvPotionAtLife", "rejuvenation potions are used before healing potions (%d%% > %d%%)", h.RejuvPotionAtLife, h.HealingPotionAt)
+	}
+	if h.MercChickenAt > 0 && h.MercChickenAt >= h.MercHealingPotionAt && h.MercHealingPotionAt > 0 {
+		vi.warnf("health.mercChickenAt", "merc chicken at %d%% is not below the merc healing potion threshold (%d%%), merc healing potions will never be used", h.MercChickenAt, h.MercHealingPotionAt)
+	}
+	if h.MercChickenAt > 0 && h.MercChickenAt >= h.MercRejuvPotionAt && h.MercRejuvPotionAt > 0 {
+		vi.warnf("health.mercChickenAt", "merc chicken at %d%% is not below the merc rejuvenation potion threshold (%d%%), merc rejuvenation potions will never be used", h.MercChickenAt, h.MercRejuvPotionAt)
+	}
+	if h.MercRejuvPotionAt > h.MercHealingPotionAt {
+		vi.warnf("health.mercRejuvPotionAt", "merc rejuvenation potions are used before healing potions (%d%% > %d%%)", h.MercRejuvPotionAt, h.MercHealingPotionAt)
+	}
+}
+
+func (c *CharacterCfg) validateInventory(vi *ValidationIssues) {
+	// Indexed as [y][x] with the inventory item positions, any other size panics when moving items
+	lock := c.Inventory.InventoryLock
+	if len(lock) != inventoryLockRows {
+		vi.errorf("inventory.inventoryLock", "must have %d rows, got %d", inventoryLockRows, len(lock))
+	}
+	for y, row := range lock {
+		if len(row) != inventoryLockColumns {
+			vi.errorf(fmt.Sprintf("inventory.inventoryLock[%d]", y), "must have %d columns, got %d", inventoryLockColumns, len(row))
+		}
+		for x, v := range row {
+			if v != 0 && v != 1 {
+				vi.errorf(fmt.Sprintf("inventory.inventoryLock[%d][%d]", y, x), "must be 0 (locked) or 1 (unlocked), got %d", v)
+			}
+		}
+	}
+
+	for i, column := range c.Inventory.BeltColumns {
+		switch strings.ToLower(column) {
+		case "healing", "mana", "rejuvenation":
+		default:
+			vi.errorf(fmt.Sprintf("inventory.beltColumns[%d]", i), "must be healing, mana or rejuvenation, got %q", column)
+		}
+	}
+}
+
+func (c *CharacterCfg) validateCharacter(vi *ValidationIssues) {
+	class := strings.ToLower(c.Character.Class)
+	leveling := len(c.Game.Runs) > 0 && c.Game.Runs[0] == LevelingRun
+	switch {
+	case leveling && !slices.Contains(LevelingClasses, class):
+		vi.errorf("character.class", "%q can not be used for leveling, allowed values: %s", c.Character.Class, strings.Join(LevelingClasses, ", "))
+	case !leveling && !slices.Contains(AvailableClasses, class):
+		vi.errorf("character.class", "unknown class %q, allowed values: %s", c.Character.Class, strings.Join(AvailableClasses, ", "))
+	}
+
+	if c.Character.ThreatAvoidance.Radius < 0 {
+		vi.errorf("character.threat_avoidance.radius", "can not be negative")
+	}
+	if c.Character.ThreatAvoidance.Weight < 0 {
+		vi.errorf("character.threat_avoidance.weight", "can not be negative")
+	}
+	if c.Character.Kiting.DangerRadius < 0 {
+		vi.errorf("character.kiting.danger_radius", "can not be negative")
+	}
+
+	if class == "nova" || class == "lightsorc" {
+		if t := c.Character.NovaSorceress.BossStaticThreshold; t < 1 || t > 100 {
+			vi.warnf("character.nova_sorceress.boss_static_threshold", "must be between 1 and 100, got %d, the difficulty default will be used", t)
+		}
+	}
+}
+
+func (c *CharacterCfg) validateGame(vi *ValidationIssues) {
+	switch c.Game.Difficulty {
+	case difficulty.Normal, difficulty.Nightmare, difficulty.Hell:
+	default:
+		vi.errorf("game.difficulty", "must be normal, nightmare or hell, got %q", c.Game.Difficulty)
+	}
+
+	if len(c.Game.Runs) == 0 {
+		vi.warnf("game.runs", "no runs enabled")
+	}
+	seen := make(map[Run]bool)
+	for i, run := range c.Game.Runs {
+		field := fmt.Sprintf("game.runs[%d]", i)
+		if _, found := AvailableRuns[run]; !found {
+			vi.errorf(field, "unknown run %q", run)
+			continue
+		}
+		if seen[run] {
+			vi.warnf(field, "run %q is enabled more than once", run)
+		}
+		seen[run] = true
+		if run == LevelingRun && i > 0 {
+			vi.errorf(field, "leveling must be the first run")
+		}
+	}
+
+	for i, id := range c.Game.TerrorZone.Areas {
+		if a, found := area.Areas[id]; !found || !a.CanBeTerrorized() {
+			vi.errorf(fmt.Sprintf("game.terror_zone.areas[%d]", i), "area %d can not be terrorized", id)
+		}
+	}
+	if seen[TerrorZoneRun] && len(c.Game.TerrorZone.Areas) == 0 {
+		vi.warnf("game.terror_zone.areas", "terror_zone run is enabled without areas, it will always be skipped")
+	}
+
+	if d := c.Game.Diablo.AttackFromDistance; d < 0 || d > maxAttackDistance {
+		vi.errorf("game.diablo.attackFromDistance", "must be between 0 and %d, got %d", maxAttackDistance, d)
+	}
+}
+
+// CharacterReport holds the issues found for a single config/{name} directory
+type CharacterReport struct {
+	Name   string
+	Issues ValidationIssues
+}
+
+// ValidateFiles checks every character config and pickit directory inside configDir without loading them, so it
+// can be used on a broken setup
+func ValidateFiles(configDir string) ([]CharacterReport, error) {
+	kooloCfg := KooloCfg{}
+	b, err := os.ReadFile(filepath.Join(configDir, "koolo.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("error loading koolo.yaml: %w", err)
+	}
+	// Files are migrated in memory only, they are written when the bot loads them
+	if b, _, err = migrateDocument(b, kooloMigrations); err != nil {
+		return nil, fmt.Errorf("error migrating koolo.yaml: %w", err)
+	}
+	if err = yaml.Unmarshal(b, &kooloCfg); err != nil {
+		return nil, fmt.Errorf("error reading koolo.yaml: %w", err)
+	}
+
+	entries, err := os.ReadDir(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config directory %s: %w", configDir, err)
+	}
+
+	reports := make([]CharacterReport, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || isReservedDir(entry.Name()) {
+			continue
+		}
+
+		reports = append(reports, CharacterReport{
+			Name:   entry.Name(),
+			Issues: validateCharacterDir(configDir, entry.Name(), kooloCfg),
+		})
+	}
+
+	return reports, nil
+}
+
+func validateCharacterDir(configDir, name string, kooloCfg KooloCfg) ValidationIssues {
+	var vi ValidationIssues
+
+	dir := filepath.Join(configDir, name)
+	root, err := readConfigDocument(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		vi.errorf("config.yaml", "%v", err)
+		return vi
+	}
+	if root, err = mergeProfile(configDir, root, nil); err != nil {
+		vi.errorf("extends", "%v", err)
+		return vi
+	}
+	charCfg := CharacterCfg{}
+	if err = root.Decode(&charCfg); err != nil {
+		vi.errorf("config.yaml", "%v", err)
+		return vi
+	}
+	vi = append(vi, charCfg.ValidateFields()...)
+
+	if charCfg.LayoutProfile != "" && charCfg.LayoutProfile != "default" {
+		if _, err = os.Stat(filepath.Join(configDir, LayoutsDir, charCfg.LayoutProfile+".yaml")); err != nil {
+			vi.errorf("layoutProfile", "layout profile %q not found in %s", charCfg.LayoutProfile, filepath.Join(configDir, LayoutsDir))
+		}
+	}
+
+	pickitPath := filepath.Join(dir, "pickit")
+	if kooloCfg.CentralizedPickitPath != "" && charCfg.UseCentralizedPickit {
+		if _, err = os.Stat(kooloCfg.CentralizedPickitPath); err != nil {
+			vi.warnf("useCentralizedPickit", "the centralized pickit path %s does not exist, the local pickit will be used", kooloCfg.CentralizedPickitPath)
+		} else {
+			pickitPath = kooloCfg.CentralizedPickitPath
+		}
+	}
+	if _, err = nip.ReadDir(pickitPath + string(filepath.Separator)); err != nil {
+		vi.errorf("pickit", "%v", err)
+	}
+
+	if len(charCfg.Game.Runs) > 0 && charCfg.Game.Runs[0] == LevelingRun {
+		if _, err = nip.ReadDir(filepath.Join(dir, "pickit_leveling") + string(filepath.Separator)); err != nil {
+			vi.errorf("pickit_leveling", "%v", err)
+		}
+	}
+
+	return vi
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"gopkg.in/yaml.v3"
+)
+
+func templateConfig(t *testing.T) CharacterCfg {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("..", "..", "config", "template", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := CharacterCfg{}
+	if err = yaml.Unmarshal(b, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	return cfg
+}
+
+func TestTemplateConfigIsValid(t *testing.T) {
+	cfg := templateConfig(t)
+	if issues := cfg.ValidateFields(); len(issues) > 0 {
+		t.Errorf("expected no issues for the template config, got %v", issues)
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(c *CharacterCfg)
+		field    string
+		severity Severity
+	}{
+		{"unknown run", func(c *CharacterCfg) { c.Game.Runs = []Run{PitRun, "pitt"} }, "game.runs[1]", SeverityError},
+		{"duplicated run", func(c *CharacterCfg) { c.Game.Runs = []Run{PitRun, PitRun} }, "game.runs[1]", SeverityWarning},
+		{"leveling not first", func(c *CharacterCfg) { c.Game.Runs = []Run{PitRun, LevelingRun} }, "game.runs[1]", SeverityError},
+		{"leveling class", func(c *CharacterCfg) { c.Game.Runs = []Run{LevelingRun} }, "character.class", SeverityError},
+		{"unknown class", func(c *CharacterCfg) { c.Character.Class = "necromancer" }, "character.class", SeverityError},
+		{"inventory lock rows", func(c *CharacterCfg) { c.Inventory.InventoryLock = c.Inventory.InventoryLock[:3] }, "inventory.inventoryLock", SeverityError},
+		{"inventory lock columns", func(c *CharacterCfg) { c.Inventory.InventoryLock[2] = []int{1, 1} }, "inventory.inventoryLock[2]", SeverityError},
+		{"inventory lock value", func(c *CharacterCfg) { c.Inventory.InventoryLock[0][5] = 2 }, "inventory.inventoryLock[0][5]", SeverityError},
+		{"belt column", func(c *CharacterCfg) { c.Inventory.BeltColumns[3] = "rejuv" }, "inventory.beltColumns[3]", SeverityError},
+		{"health out of range", func(c *CharacterCfg) { c.Health.ManaPotionAt = 120 }, "health.manaPotionAt", SeverityError},
+		{"chicken above healing", func(c *CharacterCfg) { c.Health.ChickenAt = 80 }, "health.chickenAt", SeverityWarning},
+		{"rejuv above healing", func(c *CharacterCfg) { c.Health.RejuvPotionAtLife = 90 }, "health.rejuvPotionAtLife", SeverityWarning},
+		{"merc chicken above healing", func(c *CharacterCfg) { c.Health.MercChickenAt = 85 }, "health.mercChickenAt", SeverityWarning},
+		{"difficulty", func(c *CharacterCfg) { c.Game.Difficulty = "hardcore" }, "game.difficulty", SeverityError},
+		{"terror zone area", func(c *CharacterCfg) { c.Game.TerrorZone.Areas = []area.ID{area.RogueEncampment} }, "game.terror_zone.areas[0]", SeverityError},
+		{"terror zone without areas", func(c *CharacterCfg) {
+			c.Game.Runs = []Run{TerrorZoneRun}
+			c.Game.TerrorZone.Areas = nil
+		}, "game.terror_zone.areas", SeverityWarning},
+		{"kiting radius", func(c *CharacterCfg) { c.Character.Kiting.DangerRadius = -1 }, "character.kiting.danger_radius", SeverityError},
+		{"unknown recipe", func(c *CharacterCfg) { c.CubeRecipes.EnabledRecipes = []string{"Upgrade Zod"} }, "cubing.enabledRecipes[0]", SeverityWarning},
+		{"username password", func(c *CharacterCfg) { c.AuthMethod = "UsernamePassword" }, "authMethod", SeverityError},
+		{"secret reference", func(c *CharacterCfg) { c.AuthToken = "secret://my token" }, "authToken", SeverityError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := templateConfig(t)
+			tt.change(&cfg)
+
+			issues := cfg.ValidateFields()
+			found := issues.For(tt.field)
+			if len(found) == 0 {
+				t.Fatalf("expected an issue for %s, got %v", tt.field, issues)
+			}
+			if found[0].Severity != tt.severity {
+				t.Errorf("expected a %s, got %v", tt.severity, found[0])
+			}
+			if issues.HasErrors() != (tt.severity == SeverityError) {
+				t.Errorf("unexpected HasErrors result for %v", issues)
+			}
+		})
+	}
+}
+
+func TestValidationIssuesFor(t *testing.T) {
+	issues := ValidationIssues{
+		{Field: "game.runs[1]"},
+		{Field: "game.runs"},
+		{Field: "game.runsExtra"},
+		{Field: "game.terror_zone.areas[0]"},
+	}
+
+	if n := len(issues.For("game.runs")); n != 2 {
+		t.Errorf("expected 2 issues for game.runs, got %d", n)
+	}
+	if n := len(issues.For("game")); n != 4 {
+		t.Errorf("expected 4 issues for game, got %d", n)
+	}
+}
+
+func TestValidateFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	template, err := os.ReadFile(filepath.Join("..", "..", "config", "template", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	write("koolo.yaml", "debug:\n  log: false\n")
+	write(filepath.Join("valid", "config.yaml"), string(template))
+	write(filepath.Join("valid", "pickit", "rules.nip"), "[name] == ring && [quality] == unique\n")
+	write(filepath.Join("nopickit", "config.yaml"), string(template))
+	write(filepath.Join("broken", "config.yaml"), "health: [1, 2\n")
+	write(filepath.Join(LayoutsDir, "fullhd.yaml"), "width: 1920\nheight: 1080\n")
+
+	reports, err := ValidateFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues := make(map[string]ValidationIssues)
+	for _, r := range reports {
+		issues[r.Name] = r.Issues
+	}
+	if len(issues) != 3 {
+		t.Fatalf("expected a report for the 3 character directories, got %v", reports)
+	}
+	if len(issues["valid"]) > 0 {
+		t.Errorf("expected no issues for the valid character, got %v", issues["valid"])
+	}
+	if !issues["nopickit"].HasErrors() || len(issues["nopickit"].For("pickit")) == 0 {
+		t.Errorf("expected a pickit error, got %v", issues["nopickit"])
+	}
+	if len(issues["broken"].For("config.yaml")) == 0 {
+		t.Errorf("expected a config.yaml error, got %v", issues["broken"])
+	}
+}
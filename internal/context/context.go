@@ -3,6 +3,7 @@ package context
 import (
 	"log/slog"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -44,7 +45,8 @@ type Context struct {
 	HID               *game.HID
 	Logger            *slog.Logger
 	Manager           *game.Manager
-	GameReader        *game.MemoryReader
+	GameReader        game.GameReader
+	MemoryReader      *game.MemoryReader
 	MemoryInjector    *game.MemoryInjector
 	PathFinder        *pather.PathFinder
 	BeltManager       *health.BeltManager
@@ -53,6 +55,8 @@ type Context struct {
 	LastBuffAt        time.Time
 	ContextDebug      map[Priority]*Debug
 	CurrentGame       *CurrentGameHelper
+	// Waypoints seen in the waypoint menu, kept between games since they are discovered per character
+	DiscoveredWaypoints []area.ID
 }
 
 type Debug struct {
@@ -103,6 +107,16 @@ func Get() *Status {
 	return botContexts[getGoroutineID()]
 }
 
+// RecordWaypoints adds the waypoints listed by the open waypoint menu to the discovered ones, the menu only lists the
+// waypoints of the selected act
+func (ctx *Context) RecordWaypoints(waypoints []area.ID) {
+	for _, wp := range waypoints {
+		if !slices.Contains(ctx.DiscoveredWaypoints, wp) {
+			ctx.DiscoveredWaypoints = append(ctx.DiscoveredWaypoints, wp)
+		}
+	}
+}
+
 func (s *Status) SetLastAction(actionName string) {
 	s.Context.ContextDebug[s.Priority].LastAction = actionName
 }
@@ -7,6 +7,7 @@ import (
 
 type FinishReason string
 type InteractionType string
+type HangProbe string
 
 type Event interface {
 	Message() string
@@ -14,6 +14,10 @@ const (
 	InteractionTypeEntrance InteractionType = "entrance"
 	InteractionTypeNPC      InteractionType = "npc"
 	InteractionTypeObject   InteractionType = "object"
+
+	HangFrozenGameData     HangProbe = "frozen game data"
+	HangLoadingScreen      HangProbe = "loading screen"
+	HangUnresponsiveWindow HangProbe = "unresponsive window"
 )
 
 type UsedPotionEvent struct {
@@ -151,3 +155,31 @@ func GamePaused(be BaseEvent, paused bool) GamePausedEvent {
 		Paused:    paused,
 	}
 }
+
+// ClientHangEvent is sent when the game process is still running but one of the crash detector probes found it stuck,
+// the client is restarted right after
+type ClientHangEvent struct {
+	BaseEvent
+	Probe HangProbe
+}
+
+func ClientHang(be BaseEvent, probe HangProbe) ClientHangEvent {
+	return ClientHangEvent{
+		BaseEvent: be,
+		Probe:     probe,
+	}
+}
+
+// ConfigReloadedEvent is sent when a character config or pickit change on disk has been applied to a running
+// supervisor, Changes holds the changed config fields and pickit files
+type ConfigReloadedEvent struct {
+	BaseEvent
+	Changes []string
+}
+
+func ConfigReloaded(be BaseEvent, changes []string) ConfigReloadedEvent {
+	return ConfigReloadedEvent{
+		BaseEvent: be,
+		Changes:   changes,
+	}
+}
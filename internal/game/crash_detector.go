@@ -2,34 +2,67 @@ package game
 
 import (
 	"log/slog"
+	"os"
 	"time"
 
-	"golang.org/x/sys/windows"
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/mode"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/event"
 )
 
+const crashDetectorInterval = 5 * time.Second
+
+// HangProbes are the checks used to detect a client that is still running but stopped working, every probe restarts
+// the client when it fails for longer than its timeout. A zero timeout disables the probe.
+type HangProbes struct {
+	// FrozenGameData fails while the game data doesn't change at all in game
+	FrozenGameData time.Duration
+	// LoadingScreen fails while the loading screen is shown
+	LoadingScreen time.Duration
+	// UnresponsiveWindow fails while the game window doesn't process its messages
+	UnresponsiveWindow time.Duration
+}
+
 type CrashDetector struct {
 	pid         int32
 	supervisor  string
 	hwnd        uintptr
+	gr          GameReader
+	probes      HangProbes
+	interval    time.Duration
 	logger      *slog.Logger
 	restartFunc func()
+	killFunc    func() error
 	stopChan    chan struct{}
+
+	lastSnapshot       gameSnapshot
+	dataUnchangedSince time.Time
+	loadingSince       time.Time
+	unresponsiveSince  time.Time
 }
 
-func NewCrashDetector(sup string, pid int32, hwnd uintptr, logger *slog.Logger, restartFunc func()) *CrashDetector {
-	return &CrashDetector{
+func NewCrashDetector(sup string, pid int32, hwnd uintptr, gr GameReader, probes HangProbes, logger *slog.Logger, restartFunc func()) *CrashDetector {
+	cd := &CrashDetector{
 		supervisor:  sup,
 		pid:         pid,
 		hwnd:        hwnd,
+		gr:          gr,
+		probes:      probes,
+		interval:    crashDetectorInterval,
 		logger:      logger,
 		restartFunc: restartFunc,
 		stopChan:    make(chan struct{}),
 	}
+	cd.killFunc = cd.killProcess
+
+	return cd
 }
 
 func (cd *CrashDetector) Start() {
 	cd.logger.Info("Starting Crash Detector ...", slog.Int("PID", int(cd.pid)), slog.String("Supervisor", cd.supervisor))
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(cd.interval)
 	defer ticker.Stop()
 
 	for {
@@ -37,13 +70,22 @@ func (cd *CrashDetector) Start() {
 		case <-cd.stopChan:
 			cd.logger.Info("Crash Detector stopped.", slog.Int("PID", int(cd.pid)), slog.String("Supervisor", cd.supervisor))
 			return
-		case <-ticker.C:
+		case now := <-ticker.C:
 			if !cd.isProcessRunning() {
 				cd.logger.Error("Client crash detected ...", slog.Int("PID", int(cd.pid)), slog.String("Supervisor", cd.supervisor))
-				if cd.restartFunc != nil {
-					cd.logger.Info("Attempting to restart client ...", slog.String("Supervisor", cd.supervisor))
-					cd.restartFunc()
+				cd.restart()
+				return
+			}
+
+			if probe, failed := cd.checkHangProbes(now); failed {
+				cd.logger.Error("Client hang detected ...", slog.Int("PID", int(cd.pid)), slog.String("Supervisor", cd.supervisor), slog.String("probe", string(probe)))
+				event.Send(event.ClientHang(event.Text(cd.supervisor, "Client stopped responding: "+string(probe)), probe))
+
+				// The process is still alive, it has to be closed before starting a new one
+				if err := cd.killFunc(); err != nil {
+					cd.logger.Warn("Failed to kill hung client", slog.Int("PID", int(cd.pid)), slog.Any("error", err))
 				}
+				cd.restart()
 				return
 			}
 		}
@@ -55,22 +97,95 @@ func (cd *CrashDetector) Stop() {
 	close(cd.stopChan)
 }
 
-func (cd *CrashDetector) isProcessRunning() bool {
-	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_INFORMATION, false, uint32(cd.pid))
-	if err != nil {
-		cd.logger.Debug("Failed to open process", slog.Int("PID", int(cd.pid)), slog.String("err", err.Error()))
-		return false
+func (cd *CrashDetector) restart() {
+	if cd.restartFunc != nil {
+		cd.logger.Info("Attempting to restart client ...", slog.String("Supervisor", cd.supervisor))
+		cd.restartFunc()
 	}
-	defer windows.CloseHandle(handle)
+}
 
-	var exitCode uint32
-	err = windows.GetExitCodeProcess(handle, &exitCode)
-	if err != nil {
-		cd.logger.Debug("Failed to get exit code", slog.Int("PID", int(cd.pid)), slog.String("error", err.Error()))
-		return false
+// checkHangProbes updates the probe timers and returns the first probe failing for longer than its timeout
+func (cd *CrashDetector) checkHangProbes(now time.Time) (event.HangProbe, bool) {
+	if cd.probes.UnresponsiveWindow > 0 {
+		if cd.isWindowResponsive() {
+			cd.unresponsiveSince = time.Time{}
+		} else if cd.unresponsiveSince.IsZero() {
+			cd.unresponsiveSince = now
+		} else if now.Sub(cd.unresponsiveSince) >= cd.probes.UnresponsiveWindow {
+			return event.HangUnresponsiveWindow, true
+		}
 	}
 
-	isRunning := exitCode == 259 // STILL_ACTIVE
+	if cd.gr == nil || cd.probes.FrozenGameData <= 0 && cd.probes.LoadingScreen <= 0 {
+		return "", false
+	}
+
+	d := cd.gr.GetData()
+
+	if cd.probes.LoadingScreen > 0 {
+		if !d.OpenMenus.LoadingScreen {
+			cd.loadingSince = time.Time{}
+		} else if cd.loadingSince.IsZero() {
+			cd.loadingSince = now
+		} else if now.Sub(cd.loadingSince) >= cd.probes.LoadingScreen {
+			return event.HangLoadingScreen, true
+		}
+	}
+
+	if cd.probes.FrozenGameData > 0 {
+		// Menus and loading screens don't update the game data, only games in progress are checked
+		snapshot := newGameSnapshot(d)
+		if !d.IsIngame || d.OpenMenus.LoadingScreen || snapshot != cd.lastSnapshot || cd.dataUnchangedSince.IsZero() {
+			cd.dataUnchangedSince = now
+		} else if now.Sub(cd.dataUnchangedSince) >= cd.probes.FrozenGameData {
+			return event.HangFrozenGameData, true
+		}
+		cd.lastSnapshot = snapshot
+	}
+
+	return "", false
+}
+
+// gameSnapshot holds the parts of the game data that keep changing while the game is running, even when the
+// character is standing still monsters move and the frame rate changes
+type gameSnapshot struct {
+	area     area.ID
+	position data.Position
+	mode     mode.PlayerMode
+	life     int
+	mana     int
+	fps      int
+	monsters int
+	// monsterPositions mixes the position of every monster, any movement changes it
+	monsterPositions int
+}
+
+func newGameSnapshot(d Data) gameSnapshot {
+	s := gameSnapshot{
+		area:     d.PlayerUnit.Area,
+		position: d.PlayerUnit.Position,
+		mode:     d.PlayerUnit.Mode,
+		fps:      d.Game.FPS,
+		monsters: len(d.Monsters),
+	}
+	if life, found := d.PlayerUnit.Stats.FindStat(stat.Life, 0); found {
+		s.life = life.Value
+	}
+	if mana, found := d.PlayerUnit.Stats.FindStat(stat.Mana, 0); found {
+		s.mana = mana.Value
+	}
+	for _, m := range d.Monsters {
+		s.monsterPositions = s.monsterPositions*31 + m.Position.X*7 + m.Position.Y
+	}
+
+	return s
+}
+
+func (cd *CrashDetector) killProcess() error {
+	process, err := os.FindProcess(int(cd.pid))
+	if err != nil {
+		return err
+	}
 
-	return isRunning
+	return process.Kill()
 }
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package game
+
+import (
+	"log/slog"
+	"os"
+	"syscall"
+)
+
+func (cd *CrashDetector) isProcessRunning() bool {
+	process, err := os.FindProcess(int(cd.pid))
+	if err != nil {
+		cd.logger.Debug("Failed to find process", slog.Int("PID", int(cd.pid)), slog.String("err", err.Error()))
+		return false
+	}
+
+	// Signal 0 only checks that the process exists
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// isWindowResponsive can't be checked without the Windows API, the probe never fails
+func (cd *CrashDetector) isWindowResponsive() bool {
+	return true
+}
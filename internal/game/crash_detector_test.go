@@ -0,0 +1,89 @@
+package game
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/event"
+)
+
+// stuckReader returns the same data until it's changed by the test
+type stuckReader struct {
+	fakeReader
+	data Data
+}
+
+func (r *stuckReader) GetData() Data {
+	return r.data
+}
+
+func TestHangProbes(t *testing.T) {
+	start := time.Now()
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+
+	tests := []struct {
+		name   string
+		probes HangProbes
+		// steps changes the data before every check, the probe is expected to fail on the last one
+		steps []func(d *Data)
+	}{
+		{
+			name:   "frozen game data",
+			probes: HangProbes{FrozenGameData: 30 * time.Second},
+			steps: []func(d *Data){
+				func(d *Data) { d.IsIngame = true },
+				func(d *Data) { d.PlayerUnit.Position.X++ },
+				func(d *Data) {},
+				func(d *Data) {},
+				func(d *Data) {},
+			},
+		},
+		{
+			name:   "loading screen",
+			probes: HangProbes{LoadingScreen: 20 * time.Second, FrozenGameData: 20 * time.Second},
+			steps: []func(d *Data){
+				func(d *Data) { d.OpenMenus.LoadingScreen = true },
+				func(d *Data) { d.OpenMenus.LoadingScreen = false },
+				func(d *Data) { d.OpenMenus.LoadingScreen = true },
+				func(d *Data) {},
+				func(d *Data) {},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &stuckReader{data: Data{Data: data.Data{PlayerUnit: data.PlayerUnit{Position: data.Position{X: 100, Y: 100}}}}}
+			cd := NewCrashDetector("test", 0, 0, r, tt.probes, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+			for i, step := range tt.steps {
+				step(&r.data)
+				probe, failed := cd.checkHangProbes(at(i * 10))
+				last := i == len(tt.steps)-1
+				if failed != last {
+					t.Fatalf("Step %d: expected failed %v, got %v (%s)", i, last, failed, probe)
+				}
+				if last && probe != event.HangProbe(tt.name) {
+					t.Errorf("Expected %q probe, got %q", tt.name, probe)
+				}
+			}
+		})
+	}
+}
+
+func TestHangProbesIgnoreDataOutOfGame(t *testing.T) {
+	r := &stuckReader{}
+	cd := NewCrashDetector("test", 0, 0, r, HangProbes{FrozenGameData: time.Second}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	now := time.Now()
+	for i := range 10 {
+		if probe, failed := cd.checkHangProbes(now.Add(time.Duration(i) * time.Minute)); failed {
+			t.Fatalf("Expected no hang out of game, got %s", probe)
+		}
+	}
+}
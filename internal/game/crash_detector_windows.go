@@ -0,0 +1,36 @@
+//go:build windows
+
+package game
+
+import (
+	"log/slog"
+
+	"github.com/hectorgimenez/koolo/internal/utils/winproc"
+	"golang.org/x/sys/windows"
+)
+
+func (cd *CrashDetector) isProcessRunning() bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_INFORMATION, false, uint32(cd.pid))
+	if err != nil {
+		cd.logger.Debug("Failed to open process", slog.Int("PID", int(cd.pid)), slog.String("err", err.Error()))
+		return false
+	}
+	defer windows.CloseHandle(handle)
+
+	var exitCode uint32
+	err = windows.GetExitCodeProcess(handle, &exitCode)
+	if err != nil {
+		cd.logger.Debug("Failed to get exit code", slog.Int("PID", int(cd.pid)), slog.String("error", err.Error()))
+		return false
+	}
+
+	isRunning := exitCode == 259 // STILL_ACTIVE
+
+	return isRunning
+}
+
+func (cd *CrashDetector) isWindowResponsive() bool {
+	hung, _, _ := winproc.IsHungAppWindow.Call(cd.hwnd)
+
+	return hung == 0
+}
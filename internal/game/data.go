@@ -50,7 +50,7 @@ func (d Data) PlayerCastDuration() time.Duration {
 func (d Data) MonsterFilterAnyReachable() data.MonsterFilter {
 	return func(monsters data.Monsters) (filtered []data.Monster) {
 		for _, m := range monsters {
-			if d.AreaData.IsWalkable(m.Position) {
+			if d.AreaData.IsReachable(d.PlayerUnit.Position, m.Position) {
 				filtered = append(filtered, m)
 			}
 		}
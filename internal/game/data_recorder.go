@@ -0,0 +1,124 @@
+package game
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+)
+
+// recordedFrame is a line of a game data recording. Map data is not stored, it's generated again from the map seed
+// and difficulty, and the character config is left out since it contains credentials.
+type recordedFrame struct {
+	Time                         time.Time             `json:"time"`
+	MapSeed                      uint                  `json:"mapSeed"`
+	Difficulty                   difficulty.Difficulty `json:"difficulty"`
+	InGame                       bool                  `json:"inGame"`
+	IsInCharacterSelectionScreen bool                  `json:"isInCharacterSelectionScreen"`
+	IsInLobby                    bool                  `json:"isInLobby"`
+	IsOnline                     bool                  `json:"isOnline"`
+	LegacyGraphics               bool                  `json:"legacyGraphics"`
+	GameAreaSizeX                int                   `json:"gameAreaSizeX"`
+	GameAreaSizeY                int                   `json:"gameAreaSizeY"`
+	Data                         data.Data             `json:"data"`
+}
+
+// DataRecorder writes the data returned by another GameReader to a gzipped JSON lines file, it can be played back
+// with ReplayReader. Frames are written at most once every interval, the bot reads the game way more often than needed
+// to reproduce most of the issues.
+type DataRecorder struct {
+	GameReader
+	interval time.Duration
+	lastAt   time.Time
+	mu       sync.Mutex
+	file     *os.File
+	zw       *gzip.Writer
+	enc      *json.Encoder
+}
+
+var _ GameReader = (*DataRecorder)(nil)
+
+func NewDataRecorder(reader GameReader, path string, interval time.Duration) (*DataRecorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("error creating recordings directory: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating game data recording: %w", err)
+	}
+
+	zw := gzip.NewWriter(f)
+	return &DataRecorder{
+		GameReader: reader,
+		interval:   interval,
+		file:       f,
+		zw:         zw,
+		enc:        json.NewEncoder(zw),
+	}, nil
+}
+
+func (r *DataRecorder) GetData() Data {
+	d := r.GameReader.GetData()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.enc == nil || time.Since(r.lastAt) < r.interval {
+		return d
+	}
+	r.lastAt = time.Now()
+
+	x, y := r.GameAreaSize()
+	err := r.enc.Encode(recordedFrame{
+		Time:                         r.lastAt,
+		MapSeed:                      r.MapSeed(),
+		Difficulty:                   d.CharacterCfg.Game.Difficulty,
+		InGame:                       r.InGame(),
+		IsInCharacterSelectionScreen: r.IsInCharacterSelectionScreen(),
+		IsInLobby:                    r.IsInLobby(),
+		IsOnline:                     r.IsOnline(),
+		LegacyGraphics:               r.LegacyGraphics(),
+		GameAreaSizeX:                x,
+		GameAreaSizeY:                y,
+		Data:                         d.Data,
+	})
+	// Flushing every frame keeps the recording readable if the bot crashes, which is when it's most needed
+	if err == nil {
+		err = r.zw.Flush()
+	}
+	if err != nil {
+		// Recording is a debugging aid, a broken file should never stop the bot
+		r.closeFile()
+	}
+
+	return d
+}
+
+// Close finishes the recording, the wrapped reader is not closed
+func (r *DataRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.closeFile()
+}
+
+func (r *DataRecorder) closeFile() error {
+	if r.enc == nil {
+		return nil
+	}
+	r.enc = nil
+
+	err := r.zw.Close()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
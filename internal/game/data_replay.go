@@ -0,0 +1,181 @@
+package game
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/game/map_client"
+)
+
+// ReplayReader feeds back a recording written by DataRecorder, every GetData call returns the next frame and the last
+// one is repeated once the recording is over. The rest of the methods answer with the frame returned by the last
+// GetData call, or the first one if it was never called.
+type ReplayReader struct {
+	mu          sync.Mutex
+	cfg         *config.CharacterCfg
+	mapProvider map_client.MapDataProvider
+	frames      []recordedFrame
+	current     int
+	started     bool
+	mapSeed     uint
+	areas       map[area.ID]AreaData
+}
+
+var _ GameReader = (*ReplayReader)(nil)
+
+// NewReplayReader loads the whole recording in memory. Map data is generated with the given provider, a
+// map_client.FixtureProvider or the map cache can be used to replay without the game installed, areas are empty if
+// mapProvider is nil. The character config is not recorded, cfg is returned with the data like MemoryReader does.
+func NewReplayReader(path string, cfg *config.CharacterCfg, mapProvider map_client.MapDataProvider) (*ReplayReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening game data recording: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading game data recording: %w", err)
+	}
+
+	var frames []recordedFrame
+	dec := json.NewDecoder(bufio.NewReader(zr))
+	for {
+		var frame recordedFrame
+		err = dec.Decode(&frame)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// Recordings of crashed bots can end with a truncated frame, the complete ones are still valid
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error decoding game data recording frame %d: %w", len(frames), err)
+		}
+		frames = append(frames, frame)
+	}
+
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("game data recording %s has no frames", path)
+	}
+
+	return &ReplayReader{
+		cfg:         cfg,
+		mapProvider: mapProvider,
+		frames:      frames,
+	}, nil
+}
+
+// Frames returns the amount of frames in the recording
+func (r *ReplayReader) Frames() int {
+	return len(r.frames)
+}
+
+// Done returns true once GetData returned the last frame
+func (r *ReplayReader) Done() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.started && r.current == len(r.frames)-1
+}
+
+func (r *ReplayReader) SetCharacterConfig(cfg *config.CharacterCfg) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cfg = cfg
+}
+
+func (r *ReplayReader) GetData() Data {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started && r.current < len(r.frames)-1 {
+		r.current++
+	}
+	r.started = true
+
+	frame := r.frames[r.current]
+	if frame.MapSeed != r.mapSeed || r.areas == nil {
+		r.mapSeed = frame.MapSeed
+		r.areas = r.loadAreas(frame)
+	}
+
+	// Static map data is not part of the recorded frames, it's added back like MemoryReader does
+	d := frame.Data
+	currentArea := r.areas[d.PlayerUnit.Area]
+
+	var cfgCopy config.CharacterCfg
+	if r.cfg != nil {
+		cfgCopy = *r.cfg
+	}
+
+	return Data{
+		Data:         d,
+		CharacterCfg: cfgCopy,
+		AreaData:     currentArea,
+		Areas:        r.areas,
+	}
+}
+
+func (r *ReplayReader) loadAreas(frame recordedFrame) map[area.ID]AreaData {
+	if r.mapProvider == nil || frame.MapSeed == 0 {
+		return map[area.ID]AreaData{}
+	}
+
+	mapData, err := r.mapProvider.GetMapData(strconv.Itoa(int(frame.MapSeed)), frame.Difficulty)
+	if err != nil {
+		return map[area.ID]AreaData{}
+	}
+
+	return AreasFromMapData(mapData)
+}
+
+func (r *ReplayReader) frame() *recordedFrame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &r.frames[r.current]
+}
+
+func (r *ReplayReader) InGame() bool {
+	return r.frame().InGame
+}
+
+func (r *ReplayReader) IsInCharacterSelectionScreen() bool {
+	return r.frame().IsInCharacterSelectionScreen
+}
+
+func (r *ReplayReader) IsInLobby() bool {
+	return r.frame().IsInLobby
+}
+
+func (r *ReplayReader) IsOnline() bool {
+	return r.frame().IsOnline
+}
+
+func (r *ReplayReader) LegacyGraphics() bool {
+	return r.frame().LegacyGraphics
+}
+
+func (r *ReplayReader) MapSeed() uint {
+	return r.frame().MapSeed
+}
+
+// Screenshot returns a black image, screenshots are not recorded
+func (r *ReplayReader) Screenshot() image.Image {
+	x, y := r.GameAreaSize()
+	return image.NewRGBA(image.Rect(0, 0, x, y))
+}
+
+func (r *ReplayReader) GameAreaSize() (int, int) {
+	frame := r.frame()
+	return frame.GameAreaSizeX, frame.GameAreaSizeY
+}
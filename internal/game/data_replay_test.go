@@ -0,0 +1,89 @@
+package game
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/game/map_client"
+)
+
+type fakeReader struct {
+	frame int
+}
+
+func (r *fakeReader) GetData() Data {
+	r.frame++
+	d := Data{Data: data.Data{PlayerUnit: data.PlayerUnit{Name: "frame", Area: area.RogueEncampment, Position: data.Position{X: r.frame}}}}
+	d.CharacterCfg.Game.Difficulty = difficulty.Hell
+	d.CharacterCfg.Password = "secret"
+	return d
+}
+func (r *fakeReader) InGame() bool                       { return r.frame > 1 }
+func (r *fakeReader) IsInCharacterSelectionScreen() bool { return r.frame == 1 }
+func (r *fakeReader) IsInLobby() bool                    { return false }
+func (r *fakeReader) IsOnline() bool                     { return false }
+func (r *fakeReader) LegacyGraphics() bool               { return true }
+func (r *fakeReader) MapSeed() uint                      { return 1234 }
+func (r *fakeReader) Screenshot() image.Image            { return image.NewRGBA(image.Rect(0, 0, 1, 1)) }
+func (r *fakeReader) GameAreaSize() (int, int)           { return 1280, 720 }
+
+func (r *fakeReader) SetCharacterConfig(*config.CharacterCfg) {}
+
+func TestReplayRecordedData(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "recording.jsonl.gz")
+	recorder, err := NewDataRecorder(&fakeReader{}, path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		recorder.GetData()
+	}
+	if err = recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Single 1x1 walkable level, enough to check the map data is added back to the frames
+	fixture := `[{"type":"map","id":1,"name":"Rogue Encampment","offset":{"x":0,"y":0},"size":{"width":1,"height":1},"map":[[0,1]]}]`
+	fixturePath := filepath.Join(dir, map_client.FixtureName("1234", difficulty.Hell))
+	if err = os.WriteFile(fixturePath, []byte(fixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.CharacterCfg{CharacterName: "replayed"}
+	replay, err := NewReplayReader(path, cfg, map_client.NewFixtureProvider(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.Frames() != 3 {
+		t.Fatalf("Expected 3 frames, got %d", replay.Frames())
+	}
+	if !replay.IsInCharacterSelectionScreen() || replay.InGame() {
+		t.Errorf("Expected first frame state before calling GetData")
+	}
+
+	for i := 1; i <= 4; i++ {
+		d := replay.GetData()
+		if expected := min(i, 3); d.PlayerUnit.Position.X != expected {
+			t.Errorf("Expected frame %d, got %d", expected, d.PlayerUnit.Position.X)
+		}
+		if d.CharacterCfg.CharacterName != "replayed" || d.CharacterCfg.Password != "" {
+			t.Errorf("Expected character config given to the replay, got %+v", d.CharacterCfg)
+		}
+		if d.AreaData.Grid == nil || !d.AreaData.IsWalkable(data.Position{}) {
+			t.Errorf("Expected area data generated from the map fixture")
+		}
+	}
+	if !replay.Done() || !replay.InGame() || replay.MapSeed() != 1234 || !replay.LegacyGraphics() {
+		t.Errorf("Expected last frame state after the whole recording")
+	}
+	if x, y := replay.GameAreaSize(); x != 1280 || y != 720 {
+		t.Errorf("Expected 1280x720 game area, got %dx%d", x, y)
+	}
+}
@@ -0,0 +1,24 @@
+package game
+
+import (
+	"image"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+// GameReader is what the bot reads from the game. MemoryReader reads it from a live game process, DataRecorder
+// stores it while playing and ReplayReader feeds a recording back, without the game.
+type GameReader interface {
+	GetData() Data
+	InGame() bool
+	IsInCharacterSelectionScreen() bool
+	IsInLobby() bool
+	IsOnline() bool
+	LegacyGraphics() bool
+	MapSeed() uint
+	Screenshot() image.Image
+	// GameAreaSize returns the size in pixels of the game window client area
+	GameAreaSize() (int, int)
+	// SetCharacterConfig replaces the config returned with the game data, used when the config is reloaded
+	SetCharacterConfig(cfg *config.CharacterCfg)
+}
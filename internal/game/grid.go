@@ -8,6 +8,10 @@ const (
 	CollisionTypeLowPriority
 	CollisionTypeMonster
 	CollisionTypeObject
+	// Threat types are walkable tiles close to dangerous monsters, from the outer to the inner part of the area
+	CollisionTypeThreatLow
+	CollisionTypeThreatMedium
+	CollisionTypeThreatHigh
 )
 
 type CollisionType uint8
@@ -18,6 +22,10 @@ type Grid struct {
 	Width         int
 	Height        int
 	CollisionGrid [][]CollisionType
+	// regions holds the connected region of every tile, see LabelRegions
+	regions []uint16
+	// ownedRows is only set on overlays, rows not owned yet are shared with the base grid
+	ownedRows []bool
 }
 
 func NewGrid(rawCollisionGrid [][]CollisionType, offsetX, offsetY int) *Grid {
@@ -78,5 +86,52 @@ func (g *Grid) Copy() *Grid {
 		Width:         g.Width,
 		Height:        g.Height,
 		CollisionGrid: cg,
+		regions:       g.regions,
 	}
 }
+
+// Overlay returns a grid on top of g for dynamic obstacles like monsters or objects. Rows are shared with g and only
+// copied the first time one of their tiles is changed with Set, so g is never modified and most of the grid is not
+// copied at all.
+func (g *Grid) Overlay() *Grid {
+	rows := make([][]CollisionType, len(g.CollisionGrid))
+	copy(rows, g.CollisionGrid)
+
+	return &Grid{
+		OffsetX:       g.OffsetX,
+		OffsetY:       g.OffsetY,
+		Width:         g.Width,
+		Height:        g.Height,
+		CollisionGrid: rows,
+		regions:       g.regions,
+		ownedRows:     make([]bool, len(rows)),
+	}
+}
+
+// Set changes the tile at the given relative position, overlays copy the row before changing it if still shared
+func (g *Grid) Set(x, y int, t CollisionType) {
+	if g.CollisionGrid[y][x] == t {
+		return
+	}
+
+	if g.ownedRows != nil && !g.ownedRows[y] {
+		row := make([]CollisionType, len(g.CollisionGrid[y]))
+		copy(row, g.CollisionGrid[y])
+		g.CollisionGrid[y] = row
+		g.ownedRows[y] = true
+	}
+
+	g.CollisionGrid[y][x] = t
+}
+
+// OverlayRows returns the rows copied by Set on an overlay, the only ones that can differ from its base grid
+func (g *Grid) OverlayRows() []int {
+	var rows []int
+	for y, owned := range g.ownedRows {
+		if owned {
+			rows = append(rows, y)
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,85 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOverlaySetKeepsTheBaseGrid(t *testing.T) {
+	base := regionsGrid(`
+.....
+.....
+.....
+.....`)
+	original := base.Copy()
+
+	overlay := base.Overlay()
+	overlay.Set(1, 1, CollisionTypeMonster)
+	overlay.Set(3, 1, CollisionTypeObject)
+	overlay.Set(2, 3, CollisionTypeNonWalkable)
+
+	for y := range base.CollisionGrid {
+		if !slices.Equal(base.CollisionGrid[y], original.CollisionGrid[y]) {
+			t.Errorf("expected base row %d to be unchanged, got %v", y, base.CollisionGrid[y])
+		}
+	}
+	if overlay.CollisionGrid[1][1] != CollisionTypeMonster || overlay.CollisionGrid[1][3] != CollisionTypeObject || overlay.CollisionGrid[3][2] != CollisionTypeNonWalkable {
+		t.Error("expected the overlay to hold the changed tiles")
+	}
+
+	if rows := overlay.OverlayRows(); !slices.Equal(rows, []int{1, 3}) {
+		t.Errorf("expected only the changed rows to be copied, got %v", rows)
+	}
+	for _, y := range []int{0, 2} {
+		if &overlay.CollisionGrid[y][0] != &base.CollisionGrid[y][0] {
+			t.Errorf("expected unmodified row %d to be shared with the base grid", y)
+		}
+	}
+	if &overlay.CollisionGrid[1][0] == &base.CollisionGrid[1][0] {
+		t.Error("expected the changed row to be a copy")
+	}
+}
+
+func TestOverlaySetSameValue(t *testing.T) {
+	base := regionsGrid(`
+...
+...`)
+
+	overlay := base.Overlay()
+	overlay.Set(1, 0, base.CollisionGrid[0][1])
+	if rows := overlay.OverlayRows(); len(rows) != 0 {
+		t.Errorf("expected setting the same tile type to leave the rows shared, got %v", rows)
+	}
+}
+
+func TestNestedOverlay(t *testing.T) {
+	base := regionsGrid(`
+...
+...`)
+
+	first := base.Overlay()
+	first.Set(0, 0, CollisionTypeMonster)
+	second := first.Overlay()
+	second.Set(0, 0, CollisionTypeObject)
+	second.Set(0, 1, CollisionTypeObject)
+
+	if base.CollisionGrid[0][0] != CollisionTypeWalkable || base.CollisionGrid[1][0] != CollisionTypeWalkable {
+		t.Error("expected the base grid to be unchanged")
+	}
+	if first.CollisionGrid[0][0] != CollisionTypeMonster || first.CollisionGrid[1][0] != CollisionTypeWalkable {
+		t.Error("expected the first overlay to be unchanged by the second one")
+	}
+}
+
+func TestSetOnPlainGrid(t *testing.T) {
+	g := regionsGrid(`
+...`)
+
+	g.Set(1, 0, CollisionTypeMonster)
+	if g.CollisionGrid[0][1] != CollisionTypeMonster {
+		t.Error("expected Set to change plain grids in place")
+	}
+	if rows := g.OverlayRows(); rows != nil {
+		t.Errorf("expected no overlay rows on a plain grid, got %v", rows)
+	}
+}
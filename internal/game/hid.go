@@ -1,13 +1,24 @@
 package game
 
+// InputBackend sends the input events to the game, coordinates are relative to the top-left corner of the game area.
+// WindowInput posts them to the game window, InputRecorder keeps them in memory.
+type InputBackend interface {
+	MovePointer(x, y int)
+	Click(btn MouseButton, x, y int)
+	ClickWithModifier(btn MouseButton, x, y int, modifier ModifierKey)
+	PressKey(key byte)
+	PressKeyWithModifier(key byte, modifier ModifierKey)
+	KeySequence(keys ...byte)
+	KeyDown(key byte)
+	KeyUp(key byte)
+}
+
 type HID struct {
-	gr *MemoryReader
-	gi *MemoryInjector
+	backend InputBackend
 }
 
-func NewHID(gr *MemoryReader, gi *MemoryInjector) *HID {
+func NewHID(backend InputBackend) *HID {
 	return &HID{
-		gr: gr,
-		gi: gi,
+		backend: backend,
 	}
 }
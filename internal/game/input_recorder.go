@@ -0,0 +1,152 @@
+package game
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type InputEventType string
+
+const (
+	InputMovePointer InputEventType = "move"
+	InputClick       InputEventType = "click"
+	InputKeyPress    InputEventType = "key"
+	InputKeyDown     InputEventType = "keydown"
+	InputKeyUp       InputEventType = "keyup"
+)
+
+// InputEvent is an input sent through the InputRecorder, X and Y are screen coordinates relative to the game area.
+// Fields not related to the event type are left empty.
+type InputEvent struct {
+	Time     time.Time
+	Type     InputEventType
+	X, Y     int
+	Button   MouseButton
+	Key      byte
+	Modifier ModifierKey
+}
+
+func (e InputEvent) String() string {
+	switch e.Type {
+	case InputMovePointer:
+		return fmt.Sprintf("move %d,%d", e.X, e.Y)
+	case InputClick:
+		button := "left"
+		if e.Button == RightButton {
+			button = "right"
+		}
+		return fmt.Sprintf("click %s %d,%d%s", button, e.X, e.Y, modifierSuffix(e.Modifier))
+	}
+
+	return fmt.Sprintf("%s %#x%s", e.Type, e.Key, modifierSuffix(e.Modifier))
+}
+
+func modifierSuffix(m ModifierKey) string {
+	switch m {
+	case 0:
+		return ""
+	case ShiftKey:
+		return " +shift"
+	case CtrlKey:
+		return " +ctrl"
+	}
+
+	return fmt.Sprintf(" +%#x", byte(m))
+}
+
+// InputRecorder keeps every input event in memory, so tests can check what would be sent to the game. Events are
+// forwarded to next when it's set, to record inputs while playing.
+type InputRecorder struct {
+	next   InputBackend
+	mu     sync.Mutex
+	events []InputEvent
+}
+
+func NewInputRecorder(next InputBackend) *InputRecorder {
+	return &InputRecorder{next: next}
+}
+
+// Events returns a copy of the events recorded so far
+func (r *InputRecorder) Events() []InputEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]InputEvent(nil), r.events...)
+}
+
+// Reset drops the recorded events
+func (r *InputRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = nil
+}
+
+func (r *InputRecorder) record(e InputEvent) {
+	e.Time = time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, e)
+}
+
+func (r *InputRecorder) MovePointer(x, y int) {
+	r.record(InputEvent{Type: InputMovePointer, X: x, Y: y})
+	if r.next != nil {
+		r.next.MovePointer(x, y)
+	}
+}
+
+func (r *InputRecorder) Click(btn MouseButton, x, y int) {
+	r.record(InputEvent{Type: InputClick, X: x, Y: y, Button: btn})
+	if r.next != nil {
+		r.next.Click(btn, x, y)
+	}
+}
+
+func (r *InputRecorder) ClickWithModifier(btn MouseButton, x, y int, modifier ModifierKey) {
+	r.record(InputEvent{Type: InputClick, X: x, Y: y, Button: btn, Modifier: modifier})
+	if r.next != nil {
+		r.next.ClickWithModifier(btn, x, y, modifier)
+	}
+}
+
+func (r *InputRecorder) PressKey(key byte) {
+	r.record(InputEvent{Type: InputKeyPress, Key: key})
+	if r.next != nil {
+		r.next.PressKey(key)
+	}
+}
+
+func (r *InputRecorder) PressKeyWithModifier(key byte, modifier ModifierKey) {
+	r.record(InputEvent{Type: InputKeyPress, Key: key, Modifier: modifier})
+	if r.next != nil {
+		r.next.PressKeyWithModifier(key, modifier)
+	}
+}
+
+// KeySequence is recorded as one key press per key
+func (r *InputRecorder) KeySequence(keys ...byte) {
+	for _, key := range keys {
+		r.record(InputEvent{Type: InputKeyPress, Key: key})
+	}
+	if r.next != nil {
+		r.next.KeySequence(keys...)
+	}
+}
+
+func (r *InputRecorder) KeyDown(key byte) {
+	r.record(InputEvent{Type: InputKeyDown, Key: key})
+	if r.next != nil {
+		r.next.KeyDown(key)
+	}
+}
+
+func (r *InputRecorder) KeyUp(key byte) {
+	r.record(InputEvent{Type: InputKeyUp, Key: key})
+	if r.next != nil {
+		r.next.KeyUp(key)
+	}
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+)
+
+func TestInputRecorderThroughHID(t *testing.T) {
+	recorder := NewInputRecorder(nil)
+	hid := NewHID(recorder)
+
+	hid.Click(LeftButton, 100, 200)
+	hid.ClickWithModifier(RightButton, 10, 20, CtrlKey)
+	hid.PressKeyBinding(data.KeyBinding{Key1: [2]byte{'Q', 0}})
+	hid.PressKeyBinding(data.KeyBinding{Key1: [2]byte{255, 0}, Key2: [2]byte{'W', byte(ShiftKey)}})
+	hid.KeySequence(KeyEnter, 'A')
+	hid.KeyDown(data.KeyBinding{Key1: [2]byte{KeyShift, 0}})
+	hid.KeyUp(data.KeyBinding{Key1: [2]byte{KeyShift, 0}})
+	hid.MovePointer(5, 6)
+
+	var events []string
+	for _, e := range recorder.Events() {
+		if e.Time.IsZero() {
+			t.Errorf("Expected event time to be set: %v", e)
+		}
+		events = append(events, e.String())
+	}
+
+	expected := []string{
+		"click left 100,200",
+		"click right 10,20 +ctrl",
+		"key 0x51",
+		"key 0x57 +shift",
+		"key 0xd",
+		"key 0x41",
+		"keydown 0x10",
+		"keyup 0x10",
+		"move 5,6",
+	}
+	if !slices.Equal(events, expected) {
+		t.Errorf("Expected events %v, got %v", expected, events)
+	}
+
+	recorder.Reset()
+	if len(recorder.Events()) != 0 {
+		t.Errorf("Expected no events after reset")
+	}
+}
@@ -0,0 +1,39 @@
+package game
+
+// Virtual-key codes sent to the game. They have the same values as the Windows ones, so they can be posted to the game
+// window as they are, but they don't depend on the Windows API packages.
+const (
+	KeyBackspace byte = 0x08
+	KeyTab       byte = 0x09
+	KeyEnter     byte = 0x0D
+	KeyShift     byte = 0x10
+	KeyControl   byte = 0x11
+	KeyAlt       byte = 0x12
+	KeyEscape    byte = 0x1B
+	KeySpace     byte = 0x20
+	KeyEnd       byte = 0x23
+	KeyHome      byte = 0x24
+	KeyLeft      byte = 0x25
+	KeyUp        byte = 0x26
+	KeyRight     byte = 0x27
+	KeyDown      byte = 0x28
+	KeyLeftWin   byte = 0x5B
+	KeyRightWin  byte = 0x5C
+	KeyF1        byte = 0x70
+	KeyF2        byte = 0x71
+	KeyF3        byte = 0x72
+	KeyF4        byte = 0x73
+	KeyF5        byte = 0x74
+	KeyF6        byte = 0x75
+	KeyF7        byte = 0x76
+	KeyF8        byte = 0x77
+	KeyF9        byte = 0x78
+	KeyF10       byte = 0x79
+	KeyF11       byte = 0x7A
+	KeyF12       byte = 0x7B
+	KeyLeftShift byte = 0xA0
+	KeyLeftCtrl  byte = 0xA2
+	KeyLeftAlt   byte = 0xA4
+	KeyRightAlt  byte = 0xA5
+	KeyMinus     byte = 0xBD
+)
@@ -0,0 +1,154 @@
+//go:build windows
+
+package game
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/billgraziano/dpapi"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/lxn/win"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+func StartGame(username string, password string, authmethod string, authToken string, realm string, arguments string, useCustomSettings bool) (uint32, win.HWND, error) {
+	// First check for other instances of the game and kill the handles, otherwise we will not be able to start the game
+	err := KillAllClientHandles()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Depending on the authentication method set base arguments
+	var baseArgs []string
+
+	if authmethod == "TokenAuth" {
+		baseArgs = []string{"-uid", "osi"}
+	} else if authmethod == "UsernamePassword" {
+		baseArgs = []string{"-username", username, "-password", password, "-address", realm}
+	} else if authmethod == "None" {
+		baseArgs = []string{}
+	} else {
+		// Default to no auth method
+		baseArgs = []string{}
+	}
+
+	// Parse the provided additional arguments
+	additionalArguments := strings.Fields(arguments)
+
+	// Let's use the mod directory for storing the settings, so we stop overwriting the default config
+	if useCustomSettings {
+		modName := "koolo"
+		found := false
+		for i, arg := range additionalArguments {
+			if arg == "-mod" {
+				modName = additionalArguments[i+1]
+				found = true
+				break
+			}
+		}
+		if !found {
+			additionalArguments = append(additionalArguments, "-mod", modName)
+		}
+
+		// If there is no real mod, let's create a fake mod called "koolo" so we can store our own config
+		if modName == "koolo" {
+			err = config.InstallMod()
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+
+		// Replace game mod settings with the custom ones
+		err = config.ReplaceGameSettings(modName)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	// Add them to the full argument list
+	fullArgs := append(baseArgs, additionalArguments...)
+
+	if authmethod == "TokenAuth" {
+		// Entropy buffer
+		entropy := []byte{0xc8, 0x76, 0xf4, 0xae, 0x4c, 0x95, 0x2e, 0xfe, 0xf2, 0xfa, 0x0f, 0x54, 0x19, 0xc0, 0x9c, 0x43}
+		tokenBytes := []byte(authToken)
+
+		encryptedToken, err := dpapi.EncryptBytesEntropy(tokenBytes, entropy)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to encrypt auth token: %v", err)
+		}
+
+		// Create or Open the OSI registry folder
+		key, _, err := registry.CreateKey(registry.CURRENT_USER, `SOFTWARE\Blizzard Entertainment\Battle.net\Launch Options\OSI`, registry.ALL_ACCESS)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to open registry key: %v", err)
+		}
+		defer key.Close()
+
+		region := "EU"
+		switch realm {
+		case "eu.actual.battle.net":
+			region = "EU"
+		case "us.actual.battle.net":
+			region = "US"
+		case "kr.actual.battle.net":
+			region = "KR"
+		default:
+			region = "EU"
+		}
+
+		// Update the region registry
+		err = key.SetStringValue("REGION", region)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to set REGION registry value: %v", err)
+		}
+
+		err = key.SetBinaryValue("WEB_TOKEN", encryptedToken)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to set WEB_TOKEN registry value: %v", err)
+		}
+
+		// If we got to here we've successfully updated the auth token :)
+	}
+
+	// Start the game
+	cmd := exec.Command(config.Koolo.D2RPath+"\\D2R.exe", fullArgs...)
+	err = cmd.Start()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var foundHwnd windows.HWND
+	cb := syscall.NewCallback(func(hwnd windows.HWND, lParam uintptr) uintptr {
+		var pid uint32
+		windows.GetWindowThreadProcessId(hwnd, &pid)
+		if pid == uint32(cmd.Process.Pid) {
+			foundHwnd = hwnd
+			return 0
+		}
+		return 1
+	})
+	for {
+		windows.EnumWindows(cb, unsafe.Pointer(&cmd.Process.Pid))
+		if foundHwnd != 0 {
+			// Small delay and read again, to be sure we are capturing the right hwnd
+			time.Sleep(time.Second)
+			windows.EnumWindows(cb, unsafe.Pointer(&cmd.Process.Pid))
+			break
+		}
+	}
+
+	// Close the handle for the new process, it will allow the user to open another instance of the game
+	err = KillAllClientHandles()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return uint32(cmd.Process.Pid), win.HWND(foundHwnd), nil
+}
@@ -0,0 +1,67 @@
+package game
+
+import "github.com/hectorgimenez/d2go/pkg/data"
+
+// LineOfSightMode selects which tiles block a line of sight
+type LineOfSightMode int
+
+const (
+	// LineOfSightMissile is only blocked by non walkable tiles, missiles can pass between two diagonal walls
+	LineOfSightMissile LineOfSightMode = iota
+	// LineOfSightWalk is also blocked by monsters and objects, and by diagonal steps between two blocked tiles
+	LineOfSightWalk
+)
+
+// LineOfSight walks the tiles in a straight line between both world positions, the tiles where the line starts and
+// ends are not checked, units standing there are the ones shooting and being shot.
+func (g *Grid) LineOfSight(from, to data.Position, mode LineOfSightMode) bool {
+	from, to = g.RelativePosition(from), g.RelativePosition(to)
+
+	dx, dy := to.X-from.X, to.Y-from.Y
+	sx, sy := 1, 1
+	if dx < 0 {
+		dx, sx = -dx, -1
+	}
+	if dy < 0 {
+		dy, sy = -dy, -1
+	}
+
+	err := dx - dy
+	x, y := from.X, from.Y
+	for x != to.X || y != to.Y {
+		prevX, prevY := x, y
+		e2 := 2 * err
+		if e2 > -dy {
+			err -= dy
+			x += sx
+		}
+		if e2 < dx {
+			err += dx
+			y += sy
+		}
+
+		if mode == LineOfSightWalk && x != prevX && y != prevY && g.blocksLineOfSight(prevX, y, mode) && g.blocksLineOfSight(x, prevY, mode) {
+			return false
+		}
+		if (x != to.X || y != to.Y) && g.blocksLineOfSight(x, y, mode) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (g *Grid) blocksLineOfSight(x, y int, mode LineOfSightMode) bool {
+	if x < 0 || y < 0 || x >= g.Width || y >= g.Height {
+		return true
+	}
+
+	switch g.CollisionGrid[y][x] {
+	case CollisionTypeNonWalkable:
+		return true
+	case CollisionTypeMonster, CollisionTypeObject:
+		return mode == LineOfSightWalk
+	}
+
+	return false
+}
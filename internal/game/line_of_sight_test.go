@@ -0,0 +1,74 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+)
+
+// lineOfSightGrid parses a fixture like regionsGrid, 'S' and 'G' are the world positions of both ends of the line
+func lineOfSightGrid(fixture string) (*Grid, data.Position, data.Position) {
+	var start, goal data.Position
+	for y, row := range strings.Split(strings.TrimSpace(fixture), "\n") {
+		if x := strings.IndexByte(row, 'S'); x >= 0 {
+			start = worldPos(x, y)
+		}
+		if x := strings.IndexByte(row, 'G'); x >= 0 {
+			goal = worldPos(x, y)
+		}
+	}
+
+	return regionsGrid(fixture), start, goal
+}
+
+func TestLineOfSight(t *testing.T) {
+	tests := map[string]struct {
+		grid    string
+		mode    LineOfSightMode
+		visible bool
+	}{
+		"open room": {
+			grid: `
+S.....
+......
+.....G`,
+			mode:    LineOfSightMissile,
+			visible: true,
+		},
+		"wall in between": {
+			grid: `
+S..#..
+...#..
+...#.G`,
+			mode:    LineOfSightMissile,
+			visible: false,
+		},
+		"diagonal gap, missile passes": {
+			grid: `
+S#
+#G`,
+			mode:    LineOfSightMissile,
+			visible: true,
+		},
+		"diagonal gap, walking is blocked": {
+			grid: `
+S#
+#G`,
+			mode:    LineOfSightWalk,
+			visible: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			grid, start, goal := lineOfSightGrid(tt.grid)
+			if visible := grid.LineOfSight(start, goal, tt.mode); visible != tt.visible {
+				t.Errorf("Expected line of sight %v, got %v", tt.visible, visible)
+			}
+			if visible := grid.LineOfSight(goal, start, tt.mode); visible != tt.visible {
+				t.Errorf("Expected reverse line of sight %v, got %v", tt.visible, visible)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package map_client
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+)
+
+// Bump it when MapData changes, entries written by previous versions are ignored and evicted eventually
+const cacheVersion = 1
+
+const cacheExtension = ".json.gz"
+
+// CachedProvider stores the map data returned by another provider on disk, the same seed and difficulty is only
+// generated once. Entries are gzipped JSON, the least recently used ones are removed when the cache is over maxSize.
+type CachedProvider struct {
+	provider MapDataProvider
+	dir      string
+	maxSize  int64
+	mu       sync.Mutex
+}
+
+func NewCachedProvider(provider MapDataProvider, dir string, maxSize int64) *CachedProvider {
+	return &CachedProvider{
+		provider: provider,
+		dir:      dir,
+		maxSize:  maxSize,
+	}
+}
+
+func (p *CachedProvider) GetMapData(seed string, difficulty difficulty.Difficulty) (MapData, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	path := filepath.Join(p.dir, cacheKey(seed, difficulty))
+	if md, err := readCacheEntry(path); err == nil {
+		// Modification time is used as last access time for the eviction
+		now := time.Now()
+		_ = os.Chtimes(path, now, now)
+		return md, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		// Corrupted or truncated entry, generate it again
+		_ = os.Remove(path)
+	}
+
+	md, err := p.provider.GetMapData(seed, difficulty)
+	if err != nil {
+		return nil, err
+	}
+
+	// Failing to write the cache is not a reason to fail the game, map data is still valid
+	if err = p.store(path, md); err == nil {
+		err = p.evict(path)
+	}
+	if err != nil {
+		_ = os.Remove(path)
+	}
+
+	return md, nil
+}
+
+func (p *CachedProvider) store(path string, md MapData) error {
+	if err := os.MkdirAll(p.dir, 0755); err != nil {
+		return fmt.Errorf("error creating map cache directory: %w", err)
+	}
+
+	// Write to a temp file first, a crash while writing can not leave a truncated entry behind
+	f, err := os.CreateTemp(p.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating map cache entry: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	zw := gzip.NewWriter(f)
+	err = json.NewEncoder(zw).Encode(md)
+	if err == nil {
+		err = zw.Close()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error writing map cache entry: %w", err)
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// evict removes the least recently used entries until the cache fits in maxSize, the entry just written is only
+// removed when it is bigger than the whole cache
+func (p *CachedProvider) evict(current string) error {
+	if p.maxSize <= 0 {
+		return nil
+	}
+
+	dirEntries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return err
+	}
+
+	type entry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var entries []entry
+	var total int64
+	for _, de := range dirEntries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), cacheExtension) {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{path: filepath.Join(p.dir, de.Name()), size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int {
+		return a.modTime.Compare(b.modTime)
+	})
+
+	for _, e := range entries {
+		if total <= p.maxSize {
+			return nil
+		}
+		if e.path == current {
+			continue
+		}
+		if err = os.Remove(e.path); err == nil {
+			total -= e.size
+		}
+	}
+
+	if total > p.maxSize {
+		return errors.New("map cache entry is bigger than the max cache size")
+	}
+
+	return nil
+}
+
+func readCacheEntry(path string) (MapData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var md MapData
+	if err = json.NewDecoder(zr).Decode(&md); err != nil {
+		return nil, err
+	}
+
+	return md, nil
+}
+
+func cacheKey(seed string, difficulty difficulty.Difficulty) string {
+	return fmt.Sprintf("v%d_%s_%s%s", cacheVersion, seed, strings.ToLower(string(difficulty)), cacheExtension)
+}
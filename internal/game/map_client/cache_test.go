@@ -0,0 +1,133 @@
+package map_client
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+)
+
+// koolo-map output for a single 4x2 level, rows are run lengths alternating non walkable and walkable tiles
+const kooloMapOutput = `{"type":"map","id":1,"name":"Rogue Encampment","offset":{"x":100,"y":200},"size":{"width":4,"height":2},"objects":[{"id":2,"type":"exit","x":1,"y":1}],"rooms":[],"map":[[0,2,2],[1,3]]}` + "\r\n\r\n"
+
+type countingProvider struct {
+	calls int
+}
+
+func (p *countingProvider) GetMapData(string, difficulty.Difficulty) (MapData, error) {
+	p.calls++
+	return parseMapData([]byte(kooloMapOutput)), nil
+}
+
+func TestCachedProviderGeneratesEachSeedOnce(t *testing.T) {
+	provider := &countingProvider{}
+	cache := NewCachedProvider(provider, t.TempDir(), 0)
+
+	for range 2 {
+		md, err := cache.GetMapData("1234", difficulty.Hell)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(md) != 1 || md[0].Name != "Rogue Encampment" || md[0].Offset.X != 100 {
+			t.Fatalf("Unexpected map data: %+v", md)
+		}
+		if cg := md[0].CollisionGrid(); !cg[0][0] || cg[0][2] || cg[1][0] || !cg[1][1] {
+			t.Fatalf("Unexpected collision grid: %v", cg)
+		}
+	}
+	if provider.calls != 1 {
+		t.Errorf("Expected 1 call to the provider, got %d", provider.calls)
+	}
+
+	if _, err := cache.GetMapData("1234", difficulty.Normal); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if provider.calls != 2 {
+		t.Errorf("Expected 2 calls to the provider, got %d", provider.calls)
+	}
+}
+
+func TestCachedProviderRegeneratesCorruptedEntries(t *testing.T) {
+	dir := t.TempDir()
+	provider := &countingProvider{}
+	cache := NewCachedProvider(provider, dir, 0)
+
+	if err := os.WriteFile(filepath.Join(dir, cacheKey("1234", difficulty.Hell)), []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if md, err := cache.GetMapData("1234", difficulty.Hell); err != nil || len(md) != 1 {
+		t.Fatalf("Expected map data to be generated again, got %v %v", md, err)
+	}
+	if _, err := readCacheEntry(filepath.Join(dir, cacheKey("1234", difficulty.Hell))); err != nil {
+		t.Errorf("Expected corrupted entry to be replaced, got %v", err)
+	}
+}
+
+func TestCachedProviderEvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	provider := &countingProvider{}
+	cache := NewCachedProvider(provider, dir, 0)
+	for _, seed := range []string{"1", "2"} {
+		if _, err := cache.GetMapData(seed, difficulty.Hell); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Room for two entries only, the oldest one goes away when the third one is written
+	info, err := os.Stat(filepath.Join(dir, cacheKey("1", difficulty.Hell)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.maxSize = 2*info.Size() + info.Size()/2
+	old := info.ModTime().Add(-time.Minute)
+	if err = os.Chtimes(filepath.Join(dir, cacheKey("1", difficulty.Hell)), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = cache.GetMapData("3", difficulty.Hell); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if len(names) != 2 || strings.Contains(strings.Join(names, ","), cacheKey("1", difficulty.Hell)) {
+		t.Errorf("Expected the least recently used entry to be removed, got %v", names)
+	}
+
+	// Entries that can not fit in the cache are not kept
+	cache.maxSize = 1
+	if _, err = cache.GetMapData("4", difficulty.Hell); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, cacheKey("4", difficulty.Hell))); !os.IsNotExist(err) {
+		t.Errorf("Expected entry bigger than the cache to be removed, got %v", err)
+	}
+}
+
+func TestFixtureProvider(t *testing.T) {
+	dir := t.TempDir()
+	md := parseMapData([]byte(kooloMapOutput))
+	if err := WriteFixture(filepath.Join(dir, FixtureName("1234", difficulty.Hell)), md); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{dir, filepath.Join(dir, FixtureName("1234", difficulty.Hell))} {
+		loaded, err := NewFixtureProvider(path).GetMapData("1234", difficulty.Hell)
+		if err != nil {
+			t.Fatalf("Unexpected error loading %s: %v", path, err)
+		}
+		if len(loaded) != 1 || loaded[0].Name != md[0].Name || len(loaded[0].Objects) != 1 {
+			t.Errorf("Unexpected map data loaded from %s: %+v", path, loaded)
+		}
+	}
+
+	if _, err := NewFixtureProvider(dir).GetMapData("5678", difficulty.Hell); err == nil {
+		t.Errorf("Expected error for a seed without fixture")
+	}
+}
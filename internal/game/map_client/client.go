@@ -5,37 +5,56 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
-	"syscall"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
 	"github.com/hectorgimenez/d2go/pkg/data/area"
 	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
 	"github.com/hectorgimenez/d2go/pkg/data/npc"
 	"github.com/hectorgimenez/d2go/pkg/data/object"
-	"github.com/hectorgimenez/koolo/internal/config"
 )
 
-func GetMapData(seed string, difficulty difficulty.Difficulty) (MapData, error) {
-	cmd := exec.Command("./tools/koolo-map.exe", config.Koolo.D2LoDPath, "-s", seed, "-d", getDifficultyAsNum(difficulty))
-	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+// MapDataProvider returns the layout of all the levels generated for a seed and difficulty
+type MapDataProvider interface {
+	GetMapData(seed string, difficulty difficulty.Difficulty) (MapData, error)
+}
+
+// ExecProvider generates the map data running koolo-map, it needs a Diablo II: LoD 1.13c installation
+type ExecProvider struct {
+	executable string
+	d2LoDPath  string
+}
+
+func NewExecProvider(d2LoDPath string) *ExecProvider {
+	return &ExecProvider{
+		executable: "./tools/koolo-map.exe",
+		d2LoDPath:  d2LoDPath,
+	}
+}
+
+func (p *ExecProvider) GetMapData(seed string, difficulty difficulty.Difficulty) (MapData, error) {
+	cmd := exec.Command(p.executable, p.d2LoDPath, "-s", seed, "-d", getDifficultyAsNum(difficulty))
+	hideWindow(cmd)
 	stdout, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("error fetching Map data from Diablo II: LoD 1.13c game: %w", err)
 	}
 
-	stdoutLines := strings.Split(string(stdout), "\r\n")
+	return parseMapData(stdout), nil
+}
 
+// parseMapData reads the koolo-map output, one JSON document per line
+func parseMapData(output []byte) MapData {
 	lvls := make([]serverLevel, 0)
-	for _, line := range stdoutLines {
+	for _, line := range strings.Split(string(output), "\n") {
 		var lvl serverLevel
-		err = json.Unmarshal([]byte(line), &lvl)
+		err := json.Unmarshal([]byte(strings.TrimSpace(line)), &lvl)
 		// Discard empty lines or lines that don't contain level information
 		if err == nil && lvl.Type != "" && len(lvl.Map) > 0 {
 			lvls = append(lvls, lvl)
 		}
 	}
 
-	return lvls, nil
+	return lvls
 }
 
 func getDifficultyAsNum(df difficulty.Difficulty) string {
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package map_client
+
+import "os/exec"
+
+func hideWindow(*exec.Cmd) {}
@@ -0,0 +1,57 @@
+package map_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+)
+
+// FixtureProvider loads the map data from JSON files written by WriteFixture, so real map layouts can be used
+// without the game. Path can be a single file, returned for any seed, or a directory with one file per seed and
+// difficulty named "<seed>_<difficulty>.json".
+type FixtureProvider struct {
+	path string
+}
+
+func NewFixtureProvider(path string) *FixtureProvider {
+	return &FixtureProvider{path: path}
+}
+
+func (p *FixtureProvider) GetMapData(seed string, difficulty difficulty.Difficulty) (MapData, error) {
+	path := p.path
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, FixtureName(seed, difficulty))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening map data fixture: %w", err)
+	}
+	defer f.Close()
+
+	var md MapData
+	if err = json.NewDecoder(f).Decode(&md); err != nil {
+		return nil, fmt.Errorf("error decoding map data fixture %s: %w", path, err)
+	}
+
+	return md, nil
+}
+
+// FixtureName is the file name FixtureProvider looks for when it points to a directory
+func FixtureName(seed string, difficulty difficulty.Difficulty) string {
+	return fmt.Sprintf("%s_%s.json", seed, strings.ToLower(string(difficulty)))
+}
+
+// WriteFixture saves the map data in the format read by FixtureProvider
+func WriteFixture(path string, md MapData) error {
+	text, err := json.Marshal(md)
+	if err != nil {
+		return fmt.Errorf("error encoding map data fixture: %w", err)
+	}
+
+	return os.WriteFile(path, text, 0644)
+}
@@ -0,0 +1,56 @@
+package game
+
+import (
+	"sync"
+
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/koolo/internal/game/map_client"
+	"golang.org/x/sync/errgroup"
+)
+
+// AreasFromMapData builds the collision grids and the static data of all the levels returned by a map data provider
+func AreasFromMapData(mapData map_client.MapData) map[area.ID]AreaData {
+	areas := make(map[area.ID]AreaData)
+	var mu sync.Mutex
+	g := errgroup.Group{}
+	for _, lvl := range mapData {
+		g.Go(func() error {
+			cg := lvl.CollisionGrid()
+			resultGrid := make([][]CollisionType, lvl.Size.Height)
+			for i := range resultGrid {
+				resultGrid[i] = make([]CollisionType, lvl.Size.Width)
+			}
+
+			for y := 0; y < lvl.Size.Height; y++ {
+				for x := 0; x < lvl.Size.Width; x++ {
+					if cg[y][x] {
+						resultGrid[y][x] = CollisionTypeWalkable
+					} else {
+						resultGrid[y][x] = CollisionTypeNonWalkable
+					}
+				}
+			}
+
+			npcs, exits, objects, rooms := lvl.NPCsExitsAndObjects()
+			grid := NewGrid(resultGrid, lvl.Offset.X, lvl.Offset.Y)
+			grid.LabelRegions()
+			mu.Lock()
+			areas[area.ID(lvl.ID)] = AreaData{
+				Area:           area.ID(lvl.ID),
+				Name:           lvl.Name,
+				NPCs:           npcs,
+				AdjacentLevels: exits,
+				Objects:        objects,
+				Rooms:          rooms,
+				Grid:           grid,
+			}
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return areas
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+
+package game
+
+import (
+	"errors"
+
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+)
+
+// The game process can only be read and patched through the Windows API. MemoryReader and MemoryInjector exist on
+// other systems so the packages holding them can be built and tested, but they can't be created.
+
+var errGameProcessUnsupported = errors.New("reading the game process is only supported on Windows")
+
+type MemoryReader struct{}
+
+func (gd *MemoryReader) FetchMapData() error {
+	return errGameProcessUnsupported
+}
+
+func (gd *MemoryReader) MapData() map[area.ID]AreaData {
+	return nil
+}
+
+func (gd *MemoryReader) Close() error {
+	return nil
+}
+
+type MemoryInjector struct{}
+
+func (i *MemoryInjector) Load() error {
+	return errGameProcessUnsupported
+}
+
+func (i *MemoryInjector) Unload() error {
+	return nil
+}
+
+func (i *MemoryInjector) RestoreMemory() error {
+	return nil
+}
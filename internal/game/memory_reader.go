@@ -1,3 +1,5 @@
+//go:build windows
+
 package game
 
 import (
@@ -5,7 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strconv"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
@@ -15,11 +17,10 @@ import (
 	"github.com/hectorgimenez/koolo/internal/config"
 	"github.com/hectorgimenez/koolo/internal/game/map_client"
 	"github.com/lxn/win"
-	"golang.org/x/sync/errgroup"
 )
 
 type MemoryReader struct {
-	cfg *config.CharacterCfg
+	cfg atomic.Pointer[config.CharacterCfg]
 	*memory.GameReader
 	mapSeed        uint
 	HWND           win.HWND
@@ -29,9 +30,12 @@ type MemoryReader struct {
 	GameAreaSizeY  int
 	supervisorName string
 	cachedMapData  map[area.ID]AreaData
+	mapProvider    map_client.MapDataProvider
 	logger         *slog.Logger
 }
 
+var _ GameReader = (*MemoryReader)(nil)
+
 func NewGameReader(cfg *config.CharacterCfg, supervisorName string, pid uint32, window win.HWND, logger *slog.Logger) (*MemoryReader, error) {
 	process, err := memory.NewProcessForPID(pid)
 	if err != nil {
@@ -42,72 +46,46 @@ func NewGameReader(cfg *config.CharacterCfg, supervisorName string, pid uint32,
 		GameReader:     memory.NewGameReader(process),
 		HWND:           window,
 		supervisorName: supervisorName,
-		cfg:            cfg,
+		mapProvider:    newMapDataProvider(),
 		logger:         logger,
 	}
 
+	gr.cfg.Store(cfg)
 	gr.updateWindowPositionData()
 
 	return gr, nil
 }
 
+func newMapDataProvider() map_client.MapDataProvider {
+	var provider map_client.MapDataProvider = map_client.NewExecProvider(config.Koolo.D2LoDPath)
+	if config.Koolo.MapCache.Enabled {
+		provider = map_client.NewCachedProvider(provider, config.Koolo.MapCache.Directory, int64(config.Koolo.MapCache.MaxSizeMB)*1024*1024)
+	}
+
+	return provider
+}
+
 func (gd *MemoryReader) MapSeed() uint {
 	return gd.mapSeed
 }
 
+// MapData returns the areas loaded by the last FetchMapData call
+func (gd *MemoryReader) MapData() map[area.ID]AreaData {
+	return gd.cachedMapData
+}
+
 func (gd *MemoryReader) FetchMapData() error {
 	d := gd.GameReader.GetData()
 	gd.mapSeed, _ = gd.getMapSeed(d.PlayerUnit.Address)
 	t := time.Now()
 	gd.logger.Debug("Fetching map data...", slog.Uint64("seed", uint64(gd.mapSeed)), slog.String("difficulty", string(config.Characters[gd.supervisorName].Game.Difficulty)))
 
-	mapData, err := map_client.GetMapData(strconv.Itoa(int(gd.mapSeed)), config.Characters[gd.supervisorName].Game.Difficulty)
+	mapData, err := gd.mapProvider.GetMapData(strconv.Itoa(int(gd.mapSeed)), config.Characters[gd.supervisorName].Game.Difficulty)
 	if err != nil {
 		return fmt.Errorf("error fetching map data: %w", err)
 	}
 
-	areas := make(map[area.ID]AreaData)
-	var mu sync.Mutex
-	g := errgroup.Group{}
-	for _, lvl := range mapData {
-		g.Go(func() error {
-			cg := lvl.CollisionGrid()
-			resultGrid := make([][]CollisionType, lvl.Size.Height)
-			for i := range resultGrid {
-				resultGrid[i] = make([]CollisionType, lvl.Size.Width)
-			}
-
-			for y := 0; y < lvl.Size.Height; y++ {
-				for x := 0; x < lvl.Size.Width; x++ {
-					if cg[y][x] {
-						resultGrid[y][x] = CollisionTypeWalkable
-					} else {
-						resultGrid[y][x] = CollisionTypeNonWalkable
-					}
-				}
-			}
-
-			npcs, exits, objects, rooms := lvl.NPCsExitsAndObjects()
-			grid := NewGrid(resultGrid, lvl.Offset.X, lvl.Offset.Y)
-			mu.Lock()
-			areas[area.ID(lvl.ID)] = AreaData{
-				Area:           area.ID(lvl.ID),
-				Name:           lvl.Name,
-				NPCs:           npcs,
-				AdjacentLevels: exits,
-				Objects:        objects,
-				Rooms:          rooms,
-				Grid:           grid,
-			}
-			mu.Unlock()
-
-			return nil
-		})
-	}
-
-	_ = g.Wait()
-
-	gd.cachedMapData = areas
+	gd.cachedMapData = AreasFromMapData(mapData)
 	gd.logger.Debug("Fetch completed", slog.Int64("ms", time.Since(t).Milliseconds()))
 
 	return nil
@@ -153,8 +131,8 @@ func (gd *MemoryReader) GetData() Data {
 	}
 
 	var cfgCopy config.CharacterCfg
-	if gd.cfg != nil {
-		cfgCopy = *gd.cfg
+	if cfg := gd.cfg.Load(); cfg != nil {
+		cfgCopy = *cfg
 	}
 
 	return Data{
@@ -165,6 +143,10 @@ func (gd *MemoryReader) GetData() Data {
 	}
 }
 
+func (gd *MemoryReader) SetCharacterConfig(cfg *config.CharacterCfg) {
+	gd.cfg.Store(cfg)
+}
+
 func (gd *MemoryReader) getMapSeed(playerUnit uintptr) (uint, error) {
 	actPtr := uintptr(gd.Process.ReadUInt(playerUnit+0x20, memory.Uint64))
 	actMiscPtr := uintptr(gd.Process.ReadUInt(actPtr+0x78, memory.Uint64))
@@ -180,3 +162,7 @@ func (gd *MemoryReader) getMapSeed(playerUnit uintptr) (uint, error) {
 
 	return mapSeed, nil
 }
+
+func (gd *MemoryReader) GameAreaSize() (int, int) {
+	return gd.GameAreaSizeX, gd.GameAreaSizeY
+}
@@ -1,18 +1,12 @@
 package game
 
-import (
-	"math/rand"
-	"time"
-
-	"github.com/lxn/win"
-)
-
 const (
-	RightButton MouseButton = win.MK_RBUTTON
-	LeftButton  MouseButton = win.MK_LBUTTON
+	// Same values as the MK_* flags of the window mouse messages
+	RightButton MouseButton = 0x0002
+	LeftButton  MouseButton = 0x0001
 
-	ShiftKey ModifierKey = win.VK_SHIFT
-	CtrlKey  ModifierKey = win.VK_CONTROL
+	ShiftKey ModifierKey = ModifierKey(KeyShift)
+	CtrlKey  ModifierKey = ModifierKey(KeyControl)
 )
 
 type MouseButton uint
@@ -21,43 +15,14 @@ type ModifierKey byte
 // MovePointer moves the mouse to the requested position, x and y should be the final position based on
 // pixels shown in the screen. Top-left corner is 0,0
 func (hid *HID) MovePointer(x, y int) {
-	hid.gr.updateWindowPositionData()
-	x = hid.gr.WindowLeftX + x
-	y = hid.gr.WindowTopY + y
-
-	hid.gi.CursorPos(x, y)
-	lParam := calculateLparam(x, y)
-	win.SendMessage(hid.gr.HWND, win.WM_NCHITTEST, 0, lParam)
-	win.SendMessage(hid.gr.HWND, win.WM_SETCURSOR, 0x000105A8, 0x2010001)
-	win.PostMessage(hid.gr.HWND, win.WM_MOUSEMOVE, 0, lParam)
+	hid.backend.MovePointer(x, y)
 }
 
 // Click just does a single mouse click at current pointer position
 func (hid *HID) Click(btn MouseButton, x, y int) {
-	hid.MovePointer(x, y)
-	x = hid.gr.WindowLeftX + x
-	y = hid.gr.WindowTopY + y
-
-	lParam := calculateLparam(x, y)
-	buttonDown := uint32(win.WM_LBUTTONDOWN)
-	buttonUp := uint32(win.WM_LBUTTONUP)
-	if btn == RightButton {
-		buttonDown = win.WM_RBUTTONDOWN
-		buttonUp = win.WM_RBUTTONUP
-	}
-
-	win.SendMessage(hid.gr.HWND, buttonDown, 1, lParam)
-	sleepTime := rand.Intn(keyPressMaxTime-keyPressMinTime) + keyPressMinTime
-	time.Sleep(time.Duration(sleepTime) * time.Millisecond)
-	win.SendMessage(hid.gr.HWND, buttonUp, 1, lParam)
+	hid.backend.Click(btn, x, y)
 }
 
 func (hid *HID) ClickWithModifier(btn MouseButton, x, y int, modifier ModifierKey) {
-	hid.gi.OverrideGetKeyState(byte(modifier))
-	hid.Click(btn, x, y)
-	hid.gi.RestoreGetKeyState()
-}
-
-func calculateLparam(x, y int) uintptr {
-	return uintptr(y<<16 | x)
+	hid.backend.ClickWithModifier(btn, x, y, modifier)
 }
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"math"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+)
+
+const (
+	regionNone = 0
+	// regionUnknown is used when the grid has more regions than we can label, those tiles are never discarded
+	regionUnknown = math.MaxUint16
+)
+
+// LabelRegions tags every walkable tile with the connected region it belongs to, two tiles in different regions
+// can not be reached walking from each other. Connections are the same 8 directions used by the path finder.
+func (g *Grid) LabelRegions() {
+	regions := make([]uint16, g.Width*g.Height)
+	queue := make([]int, 0, 1024)
+	label := uint16(regionNone)
+
+	for start := range regions {
+		if regions[start] != regionNone || g.CollisionGrid[start/g.Width][start%g.Width] == CollisionTypeNonWalkable {
+			continue
+		}
+
+		if label < regionUnknown-1 {
+			label++
+		} else {
+			label = regionUnknown
+		}
+
+		regions[start] = label
+		queue = append(queue[:0], start)
+		for len(queue) > 0 {
+			idx := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			x, y := idx%g.Width, idx/g.Width
+
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || nx >= g.Width || ny >= g.Height {
+						continue
+					}
+
+					nIdx := ny*g.Width + nx
+					if regions[nIdx] == regionNone && g.CollisionGrid[ny][nx] != CollisionTypeNonWalkable {
+						regions[nIdx] = label
+						queue = append(queue, nIdx)
+					}
+				}
+			}
+		}
+	}
+
+	g.regions = regions
+}
+
+// Region returns the region label for the given world position, 0 if the tile is not walkable or outside the grid
+func (g *Grid) Region(p data.Position) uint16 {
+	p = g.RelativePosition(p)
+	if g.regions == nil || p.X < 0 || p.X >= g.Width || p.Y < 0 || p.Y >= g.Height {
+		return regionNone
+	}
+
+	return g.regions[p.Y*g.Width+p.X]
+}
+
+// IsReachable returns false only when we are sure there is no walking connection between both world positions.
+// Grids without labels (like merged grids) and positions on non walkable tiles, like the player standing on an
+// incorrectly mapped tile, fall back to a walkable check of the destination.
+func (g *Grid) IsReachable(from, to data.Position) bool {
+	if !g.IsWalkable(to) {
+		return false
+	}
+
+	fromRegion, toRegion := g.Region(from), g.Region(to)
+	if fromRegion == regionNone || fromRegion == regionUnknown || toRegion == regionUnknown {
+		return true
+	}
+
+	return fromRegion == toRegion
+}
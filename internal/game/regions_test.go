@@ -0,0 +1,115 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+)
+
+const (
+	regionsOffsetX = 1000
+	regionsOffsetY = 2000
+)
+
+// regionsGrid parses a fixture, '#' is non walkable and any other tile is walkable. The grid is placed at an offset so
+// positions are world positions like in the game.
+func regionsGrid(fixture string) *Grid {
+	rows := strings.Split(strings.TrimSpace(fixture), "\n")
+	cg := make([][]CollisionType, len(rows))
+	for y, row := range rows {
+		cg[y] = make([]CollisionType, len(row))
+		for x, c := range row {
+			cg[y][x] = CollisionTypeWalkable
+			if c == '#' {
+				cg[y][x] = CollisionTypeNonWalkable
+			}
+		}
+	}
+
+	return NewGrid(cg, regionsOffsetX, regionsOffsetY)
+}
+
+func worldPos(x, y int) data.Position {
+	return data.Position{X: regionsOffsetX + x, Y: regionsOffsetY + y}
+}
+
+func TestLabelRegions(t *testing.T) {
+	g := regionsGrid(`
+...#....
+...#....
+...#....
+########
+..#.....
+.#......`)
+	g.LabelRegions()
+
+	tests := map[string]struct {
+		from, to  data.Position
+		reachable bool
+	}{
+		"same room":              {from: worldPos(0, 0), to: worldPos(2, 2), reachable: true},
+		"rooms split by a wall":  {from: worldPos(0, 0), to: worldPos(7, 0), reachable: false},
+		"rooms split by a floor": {from: worldPos(7, 2), to: worldPos(7, 5), reachable: false},
+		"diagonal gap in a wall": {from: worldPos(0, 4), to: worldPos(7, 5), reachable: true},
+		"destination in a wall":  {from: worldPos(0, 0), to: worldPos(3, 0), reachable: false},
+		"origin in a wall":       {from: worldPos(3, 0), to: worldPos(7, 0), reachable: true},
+		"destination outside":    {from: worldPos(0, 0), to: worldPos(20, 0), reachable: false},
+		"origin outside":         {from: worldPos(-5, 0), to: worldPos(7, 0), reachable: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := g.IsReachable(tc.from, tc.to); got != tc.reachable {
+				t.Errorf("expected reachable %t from %v to %v, got %t", tc.reachable, tc.from, tc.to, got)
+			}
+		})
+	}
+}
+
+func TestRegionDiagonalConnectivity(t *testing.T) {
+	// Rooms only touching through a corner are connected, the path finder moves in 8 directions
+	g := regionsGrid(`
+..##
+..##
+##..
+##..`)
+	g.LabelRegions()
+
+	if g.Region(worldPos(0, 0)) != g.Region(worldPos(3, 3)) {
+		t.Error("expected rooms touching through a corner to be the same region")
+	}
+	if g.Region(worldPos(0, 0)) == regionNone {
+		t.Error("expected walkable tiles to be labeled")
+	}
+	if g.Region(worldPos(3, 0)) != regionNone {
+		t.Error("expected non walkable tiles to have no region")
+	}
+}
+
+func TestRegionOutsideTheGrid(t *testing.T) {
+	g := regionsGrid(`
+...
+...`)
+	g.LabelRegions()
+
+	for _, p := range []data.Position{worldPos(-1, 0), worldPos(0, -1), worldPos(3, 0), worldPos(0, 2), {X: 0, Y: 0}} {
+		if r := g.Region(p); r != regionNone {
+			t.Errorf("expected no region outside the grid at %v, got %d", p, r)
+		}
+	}
+}
+
+func TestIsReachableWithoutLabels(t *testing.T) {
+	// Merged grids are never labeled, any walkable destination has to be searched
+	g := regionsGrid(`
+.#.
+.#.`)
+
+	if !g.IsReachable(worldPos(0, 0), worldPos(2, 0)) {
+		t.Error("expected unlabeled grids to fall back to the walkable check")
+	}
+	if g.IsReachable(worldPos(0, 0), worldPos(1, 0)) {
+		t.Error("expected non walkable destinations to be unreachable")
+	}
+}
@@ -0,0 +1,462 @@
+package sim
+
+import (
+	"math"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/item"
+	"github.com/hectorgimenez/d2go/pkg/data/mode"
+	"github.com/hectorgimenez/d2go/pkg/data/skill"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/ui"
+)
+
+const (
+	// Max distance from the cursor to a unit to hover it, objects are usually pointed with an offset
+	unitHoverRadius   = 2
+	objectHoverRadius = 3
+	// Monsters closer than castRadius to the target of a skill take its damage
+	castRadius = 3
+	// Teleport clicks further than this are ignored
+	teleportRange = 40
+	// Max distance searched for a walkable tile when the player is moved to another area
+	landingSearchRadius = 30
+)
+
+// Destination of the red portals, they can't be read from the map data
+var redPortalDestinations = map[area.ID]area.ID{
+	area.StonyField:          area.Tristram,
+	area.RogueEncampment:     area.MooMooFarm,
+	area.Harrogath:           area.NihlathaksTemple,
+	area.ArcaneSanctuary:     area.CanyonOfTheMagi,
+	area.ThroneOfDestruction: area.TheWorldstoneChamber,
+}
+
+func (w *World) MovePointer(x, y int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.cursor = data.Position{X: x, Y: y}
+	w.updateHover()
+}
+
+func (w *World) Click(btn game.MouseButton, x, y int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.cursor = data.Position{X: x, Y: y}
+	w.updateHover()
+
+	if btn == game.RightButton {
+		w.castRightSkill()
+		return
+	}
+	w.leftClick()
+}
+
+// ClickWithModifier works like Click, modifiers only matter in the inventory screens which are not simulated
+func (w *World) ClickWithModifier(btn game.MouseButton, x, y int, _ game.ModifierKey) {
+	w.Click(btn, x, y)
+}
+
+func (w *World) PressKey(key byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pressKey(key)
+}
+
+func (w *World) PressKeyWithModifier(key byte, _ game.ModifierKey) {
+	w.PressKey(key)
+}
+
+func (w *World) KeySequence(keys ...byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, key := range keys {
+		w.pressKey(key)
+	}
+}
+
+func (w *World) KeyDown(key byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if key == w.keyBindings.StandStill.Key1[0] {
+		w.standStill = true
+	}
+}
+
+func (w *World) KeyUp(key byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if key == w.keyBindings.StandStill.Key1[0] {
+		w.standStill = false
+	}
+}
+
+func (w *World) pressKey(key byte) {
+	switch key {
+	case w.keyBindings.ForceMove.Key1[0]:
+		if !w.openMenus.IsMenuOpen() {
+			w.walk(w.cursorTile())
+		}
+		return
+	case KeyEscape:
+		w.openMenus = data.OpenMenus{}
+		return
+	}
+
+	for _, sb := range w.keyBindings.Skills {
+		if sb.SkillID != 0 && sb.Key1[0] == key {
+			w.player.RightSkill = sb.SkillID
+			return
+		}
+	}
+}
+
+func (w *World) leftClick() {
+	if w.openMenus.Waypoint {
+		w.waypointMenuClick()
+		return
+	}
+	if w.openMenus.IsMenuOpen() {
+		return
+	}
+
+	if w.hover.IsHovered {
+		switch w.hover.UnitType {
+		case hoverMonster:
+			w.damage(w.monsterPosition(w.hover.UnitID), 0, w.leftDamage)
+		case hoverObject:
+			w.interactObject(w.hover.UnitID)
+		case hoverItem:
+			w.pickupItem(w.hover.UnitID)
+		case hoverEntrance:
+			w.enterLevel(area.ID(w.hover.UnitID))
+		}
+		return
+	}
+
+	if w.standStill {
+		w.damage(w.cursorTile(), castRadius, w.leftDamage)
+		return
+	}
+	w.walk(w.cursorTile())
+}
+
+func (w *World) castRightSkill() {
+	if w.openMenus.IsMenuOpen() {
+		return
+	}
+
+	target := w.cursorTile()
+	if w.player.RightSkill == skill.Teleport {
+		if w.player.Area.IsTown() || distance(w.player.Position, target) > teleportRange {
+			return
+		}
+		if a, found := w.walkableArea(target); found {
+			w.setPlayerPosition(a, target)
+		}
+		return
+	}
+
+	w.damage(target, castRadius, w.skillDamage[w.player.RightSkill])
+}
+
+// damage hits the alive monsters of the current area around pos, dead monsters are kept as corpses
+func (w *World) damage(pos data.Position, radius, amount int) {
+	if amount <= 0 {
+		return
+	}
+
+	for i := range w.monsters {
+		m := &w.monsters[i]
+		if m.area != w.player.Area || m.Stats[stat.Life] <= 0 || distance(m.Position, pos) > radius {
+			continue
+		}
+
+		m.Stats[stat.Life] = max(m.Stats[stat.Life]-amount, 0)
+		if m.Stats[stat.Life] == 0 {
+			m.Mode = mode.NpcDead
+		}
+	}
+}
+
+// walk moves the player in a straight line to the destination, stopping in front of the first non walkable tile.
+// Crossing the border of an adjacent level moves the player to it.
+func (w *World) walk(to data.Position) {
+	current := w.player.Position
+	a := w.player.Area
+
+	dx, dy := abs(to.X-current.X), -abs(to.Y-current.Y)
+	sx, sy := sign(to.X-current.X), sign(to.Y-current.Y)
+	e := dx + dy
+	for current != to {
+		next := current
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			next.X += sx
+		}
+		if e2 <= dx {
+			e += dx
+			next.Y += sy
+		}
+
+		nextArea, found := w.walkableArea(next)
+		if !found {
+			break
+		}
+		current, a = next, nextArea
+	}
+
+	w.setPlayerPosition(a, current)
+}
+
+func (w *World) interactObject(id data.UnitID) {
+	objects := w.objects[w.player.Area]
+	for i := range objects {
+		o := &objects[i]
+		if o.ID != id {
+			continue
+		}
+
+		switch {
+		case o.IsWaypoint():
+			w.openMenus.Waypoint = true
+			w.wpTab = area.WPAddresses[w.player.Area].Tab
+		case o.IsRedPortal() || o.IsPortal():
+			w.usePortal(o.PortalData.DestArea)
+		default:
+			// Chests, doors, shrines... can only be used once
+			o.Selectable = false
+			o.Mode = mode.ObjectModeOpened
+		}
+		return
+	}
+}
+
+func (w *World) pickupItem(id data.UnitID) {
+	for i, it := range w.items {
+		if it.UnitID != id || it.area != w.player.Area {
+			continue
+		}
+
+		it.Location = item.Location{LocationType: item.LocationInventory}
+		it.IsHovered = false
+		w.inventory = append(w.inventory, it.Item)
+		w.items = append(w.items[:i], w.items[i+1:]...)
+		w.updateHover()
+		return
+	}
+}
+
+// usePortal moves the player next to the portal back to the current area, or to the center of the destination if
+// there is none
+func (w *World) usePortal(dest area.ID) {
+	from := w.player.Area
+	if dest == 0 {
+		dest = redPortalDestinations[from]
+	}
+	target, found := w.areas[dest]
+	if !found {
+		return
+	}
+
+	landing := data.Position{X: target.OffsetX + target.Width/2, Y: target.OffsetY + target.Height/2}
+	for _, o := range w.objects[dest] {
+		if o.IsRedPortal() || o.IsPortal() {
+			landing = o.Position
+			break
+		}
+	}
+
+	w.moveToArea(dest, landing)
+}
+
+// enterLevel moves the player through an entrance (caves, stairs...), landing next to the entrance back
+func (w *World) enterLevel(dest area.ID) {
+	from := w.player.Area
+	target, found := w.areas[dest]
+	if !found {
+		return
+	}
+
+	landing := data.Position{X: target.OffsetX + target.Width/2, Y: target.OffsetY + target.Height/2}
+	for _, l := range target.AdjacentLevels {
+		if l.Area == from {
+			landing = l.Position
+			break
+		}
+	}
+
+	w.moveToArea(dest, landing)
+}
+
+// moveToArea places the player on the closest walkable tile to near, in the given area
+func (w *World) moveToArea(dest area.ID, near data.Position) {
+	target, found := w.areas[dest]
+	if !found {
+		return
+	}
+
+	if pos, found := closestWalkableTile(target, near); found {
+		w.openMenus = data.OpenMenus{}
+		w.setPlayerPosition(dest, pos)
+	}
+}
+
+// waypointMenuClick handles the act tabs and the area list of the waypoint menu
+func (w *World) waypointMenuClick() {
+	layout := ui.DefaultProfile.Layout(w.LegacyGraphics(), gameAreaSizeX, gameAreaSizeY)
+	if w.cursor.Y < layout.WpListStart.Y {
+		w.wpTab = (w.cursor.X-layout.WpTabStart.X)/layout.WpTabWidth + 1
+		return
+	}
+
+	row := (w.cursor.Y-layout.WpListStart.Y)/layout.WpAreaButtonHeight + 1
+	for dest, wp := range area.WPAddresses {
+		if wp.Tab != w.wpTab || wp.Row != row {
+			continue
+		}
+
+		for _, o := range w.objects[dest] {
+			if o.IsWaypoint() {
+				w.moveToArea(dest, o.Position)
+				return
+			}
+		}
+	}
+}
+
+// updateHover sets the unit closest to the cursor as hovered, if any is close enough
+func (w *World) updateHover() {
+	w.hover = data.HoverData{}
+
+	cx, cy := w.cursorWorld()
+	closest := math.MaxFloat64
+	hover := func(radius float64, pos data.Position, unitType int, id data.UnitID) {
+		d := math.Hypot(float64(pos.X)-cx, float64(pos.Y)-cy)
+		if d <= radius && d < closest {
+			closest = d
+			w.hover = data.HoverData{IsHovered: true, UnitID: id, UnitType: unitType}
+		}
+	}
+
+	for _, m := range w.monsters {
+		if m.area == w.player.Area && m.Stats[stat.Life] > 0 {
+			hover(unitHoverRadius, m.Position, hoverMonster, m.UnitID)
+		}
+	}
+	for _, it := range w.items {
+		if it.area == w.player.Area {
+			hover(unitHoverRadius, it.Position, hoverItem, it.UnitID)
+		}
+	}
+	for _, o := range w.objects[w.player.Area] {
+		if o.Selectable {
+			hover(objectHoverRadius, o.Position, hoverObject, o.ID)
+		}
+	}
+	// Entrances are not units, the destination area is used as ID
+	for _, l := range w.areas[w.player.Area].AdjacentLevels {
+		if l.IsEntrance {
+			hover(objectHoverRadius, l.Position, hoverEntrance, data.UnitID(l.Area))
+		}
+	}
+}
+
+// cursorWorld transforms the cursor position to world coordinates, inverse of the transformation used by the bot to
+// click on the screen
+func (w *World) cursorWorld() (float64, float64) {
+	a := (float64(w.cursor.X) - gameAreaSizeX/2) / 19.8
+	b := (float64(w.cursor.Y) - gameAreaSizeY/2) / 9.9
+
+	return float64(w.player.Position.X) + (a+b)/2, float64(w.player.Position.Y) + (b-a)/2
+}
+
+func (w *World) cursorTile() data.Position {
+	x, y := w.cursorWorld()
+	return data.Position{X: int(math.Round(x)), Y: int(math.Round(y))}
+}
+
+func (w *World) monsterPosition(id data.UnitID) data.Position {
+	for _, m := range w.monsters {
+		if m.UnitID == id {
+			return m.Position
+		}
+	}
+
+	return data.Position{}
+}
+
+// walkableArea returns the area where pos is walkable, only the current area and the levels connected to it without
+// an entrance are checked, areas from different acts can share coordinates
+func (w *World) walkableArea(pos data.Position) (area.ID, bool) {
+	current := w.areas[w.player.Area]
+	if isWalkable(current, pos) {
+		return w.player.Area, true
+	}
+
+	for _, l := range current.AdjacentLevels {
+		if !l.IsEntrance && isWalkable(w.areas[l.Area], pos) {
+			return l.Area, true
+		}
+	}
+
+	return 0, false
+}
+
+func isWalkable(a game.AreaData, pos data.Position) bool {
+	// AreaData.IsInside leaves the first row and column out, they are walkable tiles on the area borders
+	x, y := pos.X-a.OffsetX, pos.Y-a.OffsetY
+	if a.Grid == nil || x < 0 || y < 0 || x >= a.Width || y >= a.Height {
+		return false
+	}
+
+	return a.CollisionGrid[y][x] != game.CollisionTypeNonWalkable
+}
+
+func closestWalkableTile(a game.AreaData, near data.Position) (data.Position, bool) {
+	for radius := 0; radius <= landingSearchRadius; radius++ {
+		for y := near.Y - radius; y <= near.Y+radius; y++ {
+			for x := near.X - radius; x <= near.X+radius; x++ {
+				pos := data.Position{X: x, Y: y}
+				if distance(pos, near) == radius && isWalkable(a, pos) {
+					return pos, true
+				}
+			}
+		}
+	}
+
+	return data.Position{}, false
+}
+
+// distance is the chebyshev distance between two tiles
+func distance(a, b data.Position) int {
+	return max(abs(a.X-b.X), abs(a.Y-b.Y))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+
+	return 0
+}
@@ -0,0 +1,351 @@
+package sim_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/d2go/pkg/data/npc"
+	"github.com/hectorgimenez/d2go/pkg/data/object"
+	"github.com/hectorgimenez/d2go/pkg/data/skill"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/action"
+	"github.com/hectorgimenez/koolo/internal/character"
+	"github.com/hectorgimenez/koolo/internal/config"
+	ct "github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/event"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/game/map_client"
+	"github.com/hectorgimenez/koolo/internal/game/sim"
+	"github.com/hectorgimenez/koolo/internal/health"
+	"github.com/hectorgimenez/koolo/internal/pather"
+	"github.com/hectorgimenez/koolo/internal/run"
+)
+
+// Map data fixture used by the run tests instead of the trimmed one in testdata, written with
+// map_client.WriteFixture from a real seed
+const mapFixtureEnv = "KOOLO_SIM_MAP_FIXTURE"
+
+// runsFixture only has the levels used by the runs tested here, with open layouts placed where the runs expect them
+const runsFixture = "testdata/runs.json"
+
+const blizzardDamage = 100
+
+func testCharacterCfg() *config.CharacterCfg {
+	cfg := &config.CharacterCfg{CharacterName: "simulated"}
+	cfg.Character.Class = "sorceress"
+	cfg.Character.UseTeleport = true
+
+	return cfg
+}
+
+// setupSorceress binds the skills used by the blizzard sorceress
+func setupSorceress(w *sim.World) {
+	w.BindSkill(skill.Teleport, 'F', 0)
+	w.BindSkill(skill.Blizzard, 'G', blizzardDamage)
+	w.BindSkill(skill.StaticField, 'H', 0)
+	w.BindSkill(skill.FrozenArmor, 'J', 0)
+	w.BindSkill(skill.TomeOfTownPortal, 'K', 0)
+	w.SetLeftSkill(skill.IceBolt, 10)
+}
+
+// newBotContext wires the world to a bot context like the supervisor manager does with the game, it has to be called
+// from the goroutine running the actions
+func newBotContext(t *testing.T, w *sim.World, cfg *config.CharacterCfg) *ct.Status {
+	t.Helper()
+
+	ctx := ct.NewContext(t.Name())
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	hid := game.NewHID(w)
+
+	ctx.CharacterCfg = cfg
+	ctx.HID = hid
+	ctx.Logger = logger
+	ctx.GameReader = w
+	ctx.PathFinder = pather.NewPathFinder(w, ctx.Data, hid, cfg)
+	ctx.BeltManager = health.NewBeltManager(ctx.Data, hid, logger, t.Name())
+	ctx.HealthManager = health.NewHealthManager(ctx.BeltManager, ctx.Data)
+	char, err := character.BuildCharacter(ctx.Context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.Char = char
+	ctx.RefreshGameData()
+
+	// The bot refreshes the game data in the background, some steps wait for it instead of refreshing it
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ctx.RefreshGameData()
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(done)
+		ctx.Detach()
+	})
+
+	return ctx
+}
+
+// testLevel is a level with four rooms split by a wall with a single gap
+func testLevel() game.AreaData {
+	const size = 80
+	cg := make([][]game.CollisionType, size)
+	for y := range cg {
+		cg[y] = make([]game.CollisionType, size)
+		for x := range cg[y] {
+			cg[y][x] = game.CollisionTypeWalkable
+			if x == 0 || y == 0 || x == size-1 || y == size-1 || x == size/2 && (y < 60 || y > 65) {
+				cg[y][x] = game.CollisionTypeNonWalkable
+			}
+		}
+	}
+	grid := game.NewGrid(cg, 5000, 5000)
+	grid.LabelRegions()
+
+	var rooms []data.Room
+	for _, p := range []data.Position{{X: 5000, Y: 5000}, {X: 5040, Y: 5000}, {X: 5000, Y: 5040}, {X: 5040, Y: 5040}} {
+		rooms = append(rooms, data.Room{Position: p, Width: size / 2, Height: size / 2})
+	}
+
+	return game.AreaData{Area: area.BloodMoor, Name: "Blood Moor", Rooms: rooms, Grid: grid}
+}
+
+func TestClearCurrentLevel(t *testing.T) {
+	if testing.Short() {
+		t.Skip("actions run in real time")
+	}
+
+	cfg := testCharacterCfg()
+	w := sim.NewWorld(map[area.ID]game.AreaData{area.BloodMoor: testLevel()}, cfg)
+	setupSorceress(w)
+	if err := w.SetPlayerPosition(area.BloodMoor, data.Position{X: 5010, Y: 5010}); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []data.Position{{X: 5025, Y: 5030}, {X: 5060, Y: 5015}, {X: 5065, Y: 5070}, {X: 5015, Y: 5065}, {X: 5070, Y: 5050}} {
+		w.SpawnMonster(area.BloodMoor, npc.Zombie, data.MonsterTypeNone, p, 150)
+	}
+
+	newBotContext(t, w, cfg)
+	if err := action.ClearCurrentLevel(false, data.MonsterAnyFilter()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, m := range w.Monsters(area.BloodMoor) {
+		if m.Stats[stat.Life] > 0 {
+			t.Errorf("Monster at %v is still alive", m.Position)
+		}
+	}
+}
+
+// alcoveLevel is a corridor with an alcove room above it, only connected at the right side. The alcove is close enough
+// to the corridor to be skipped by the exploration plan, but its far side is out of the monster view from the end of
+// the corridor.
+func alcoveLevel() game.AreaData {
+	const width, height = 160, 50
+	cg := make([][]game.CollisionType, height)
+	for y := range cg {
+		cg[y] = make([]game.CollisionType, width)
+		for x := range cg[y] {
+			corridor := y >= 36 && y <= 45 && x >= 1 && x <= width-2
+			alcove := y >= 10 && y <= 33 && x >= 60 && x <= 105
+			passage := y >= 10 && y <= 36 && x >= 100 && x <= 105
+			cg[y][x] = game.CollisionTypeNonWalkable
+			if corridor || alcove || passage {
+				cg[y][x] = game.CollisionTypeWalkable
+			}
+		}
+	}
+	grid := game.NewGrid(cg, 5000, 5000)
+	grid.LabelRegions()
+
+	rooms := []data.Room{{Position: data.Position{X: 5060, Y: 5010}, Width: 40, Height: 24}}
+	for x := 0; x < width; x += 20 {
+		rooms = append(rooms, data.Room{Position: data.Position{X: 5000 + x, Y: 5036}, Width: 20, Height: 14})
+	}
+
+	return game.AreaData{Area: area.BloodMoor, Name: "Blood Moor", Rooms: rooms, Grid: grid}
+}
+
+func TestClearCurrentLevelChecksSkippedRooms(t *testing.T) {
+	if testing.Short() {
+		t.Skip("actions run in real time")
+	}
+
+	cfg := testCharacterCfg()
+	level := alcoveLevel()
+	w := sim.NewWorld(map[area.ID]game.AreaData{area.BloodMoor: level}, cfg)
+	setupSorceress(w)
+	w.SetMonsterView(40)
+	if err := w.SetPlayerPosition(area.BloodMoor, data.Position{X: 5005, Y: 5040}); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []data.Position{{X: 5075, Y: 5010}, {X: 5030, Y: 5040}, {X: 5150, Y: 5042}} {
+		w.SpawnMonster(area.BloodMoor, npc.Zombie, data.MonsterTypeNone, p, 150)
+	}
+
+	ctx := newBotContext(t, w, cfg)
+	alcove := level.Rooms[0]
+	skipped := false
+	for _, step := range ctx.PathFinder.PlanExploration(20) {
+		for _, seen := range step.Seen {
+			skipped = skipped || seen.Room == alcove
+		}
+	}
+	if !skipped {
+		t.Fatal("Expected the alcove to be skipped by the exploration plan")
+	}
+
+	if err := action.ClearCurrentLevel(false, data.MonsterAnyFilter()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, m := range w.Monsters(area.BloodMoor) {
+		if m.Stats[stat.Life] > 0 {
+			t.Errorf("Monster at %v is still alive", m.Position)
+		}
+	}
+}
+
+// drainEvents listens to the events sent by the actions like the supervisor does, sending blocks until they are
+// received. The listener creates a screenshots directory in the working directory, so a temporary one is used.
+func drainEvents(t *testing.T) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	previousCfg := config.Koolo
+	config.Koolo = &config.KooloCfg{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		event.NewListener(slog.New(slog.NewTextHandler(io.Discard, nil))).Listen(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		config.Koolo = previousCfg
+		os.Chdir(wd)
+	})
+}
+
+func loadFixtureWorld(t *testing.T) (*sim.World, *config.CharacterCfg) {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("runs are executed in real time")
+	}
+
+	path := runsFixture
+	if env := os.Getenv(mapFixtureEnv); env != "" {
+		path = env
+	}
+
+	cfg := testCharacterCfg()
+	w, err := sim.LoadWorld(map_client.NewFixtureProvider(path), "", difficulty.Hell, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setupSorceress(w)
+	drainEvents(t)
+
+	return w, cfg
+}
+
+// walkableNear returns the closest walkable tile to pos in the given area
+func walkableNear(t *testing.T, w *sim.World, a area.ID, pos data.Position) data.Position {
+	t.Helper()
+
+	grid := w.GetData().Areas[a].Grid
+	for radius := 0; radius < 50; radius++ {
+		for y := pos.Y - radius; y <= pos.Y+radius; y++ {
+			for x := pos.X - radius; x <= pos.X+radius; x++ {
+				p := data.Position{X: x, Y: y}
+				if grid.IsWalkable(p) {
+					return p
+				}
+			}
+		}
+	}
+	t.Fatalf("No walkable tile around %v", pos)
+
+	return data.Position{}
+}
+
+func TestCountessRun(t *testing.T) {
+	w, cfg := loadFixtureWorld(t)
+
+	for _, o := range w.Objects(area.RogueEncampment) {
+		if o.IsWaypoint() {
+			if err := w.SetPlayerPosition(area.RogueEncampment, walkableNear(t, w, area.RogueEncampment, o.Position)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	var countess data.UnitID
+	for _, o := range w.Objects(area.TowerCellarLevel5) {
+		if o.Name == object.GoodChest {
+			pos := walkableNear(t, w, area.TowerCellarLevel5, data.Position{X: o.Position.X + 3, Y: o.Position.Y + 3})
+			countess = w.SpawnMonster(area.TowerCellarLevel5, npc.DarkStalker, data.MonsterTypeSuperUnique, pos, 1000)
+		}
+	}
+	if countess == 0 {
+		t.Fatal("Countess chest not found in the map data")
+	}
+
+	newBotContext(t, w, cfg)
+	if err := run.NewCountess().Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if p := w.Player(); p.Area != area.TowerCellarLevel5 {
+		t.Errorf("Expected player in Tower Cellar Level 5, got %s", p.Area.Area().Name)
+	}
+	for _, m := range w.Monsters(area.TowerCellarLevel5) {
+		if m.UnitID == countess && m.Stats[stat.Life] > 0 {
+			t.Error("Countess is still alive")
+		}
+	}
+}
+
+func TestPindleskinRun(t *testing.T) {
+	w, cfg := loadFixtureWorld(t)
+
+	harrogath := w.GetData().Areas[area.Harrogath]
+	center := data.Position{X: harrogath.OffsetX + harrogath.Width/2, Y: harrogath.OffsetY + harrogath.Height/2}
+	if err := w.SetPlayerPosition(area.Harrogath, walkableNear(t, w, area.Harrogath, center)); err != nil {
+		t.Fatal(err)
+	}
+	pindle := w.SpawnMonster(area.NihlathaksTemple, npc.DefiledWarrior, data.MonsterTypeSuperUnique, walkableNear(t, w, area.NihlathaksTemple, data.Position{X: 10058, Y: 13226}), 1000)
+
+	newBotContext(t, w, cfg)
+	if err := run.NewPindleskin().Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, m := range w.Monsters(area.NihlathaksTemple) {
+		if m.UnitID == pindle && m.Stats[stat.Life] > 0 {
+			t.Error("Pindleskin is still alive")
+		}
+	}
+}
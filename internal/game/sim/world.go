@@ -0,0 +1,440 @@
+// Package sim is a headless Diablo II world, it implements game.GameReader and game.InputBackend so the bot can be run
+// against map fixtures without the game client. Only what the bot needs to move, fight and loot is simulated: the
+// player moves instantly on ForceMove and teleport clicks, monsters don't move nor fight back and take a fixed amount
+// of damage per skill cast.
+package sim
+
+import (
+	"fmt"
+	"image"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/d2go/pkg/data/item"
+	"github.com/hectorgimenez/d2go/pkg/data/mode"
+	"github.com/hectorgimenez/d2go/pkg/data/npc"
+	"github.com/hectorgimenez/d2go/pkg/data/skill"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/game/map_client"
+)
+
+const (
+	gameAreaSizeX = 1280
+	gameAreaSizeY = 720
+
+	// Default key bindings, skills are bound with BindSkill
+	KeyForceMove  byte = 'E'
+	KeyStandStill byte = 0x10 // VK_SHIFT
+	KeyEscape     byte = 0x1B // VK_ESCAPE
+
+	playerLife = 1000
+	playerMana = 500
+)
+
+// HoverData unit types, same values as the game
+const (
+	hoverMonster  = 1
+	hoverObject   = 2
+	hoverItem     = 4
+	hoverEntrance = 5
+)
+
+type monster struct {
+	area area.ID
+	data.Monster
+}
+
+type groundItem struct {
+	area area.ID
+	data.Item
+}
+
+// World holds the simulated game state, it's safe to use from multiple goroutines like the real readers
+type World struct {
+	mu          sync.Mutex
+	cfg         *config.CharacterCfg
+	mapSeed     uint
+	areas       map[area.ID]game.AreaData
+	player      data.PlayerUnit
+	keyBindings data.KeyBindings
+	skillDamage map[skill.ID]int
+	leftDamage  int
+	monsters    []monster
+	monsterView int
+	objects     map[area.ID][]data.Object
+	items       []groundItem
+	inventory   []data.Item
+	openMenus   data.OpenMenus
+	wpTab       int
+	standStill  bool
+	cursor      data.Position
+	hover       data.HoverData
+	lastUnitID  data.UnitID
+}
+
+var (
+	_ game.GameReader   = (*World)(nil)
+	_ game.InputBackend = (*World)(nil)
+)
+
+// NewWorld creates a world with the given levels, the player starts at the first walkable tile of the Rogue
+// Encampment if it's available, use SetPlayerPosition to place it somewhere else.
+func NewWorld(areas map[area.ID]game.AreaData, cfg *config.CharacterCfg) *World {
+	w := &World{
+		cfg:         cfg,
+		areas:       areas,
+		skillDamage: make(map[skill.ID]int),
+		objects:     make(map[area.ID][]data.Object),
+	}
+
+	w.keyBindings.ForceMove = data.KeyBinding{Key1: [2]byte{KeyForceMove, 0}}
+	w.keyBindings.StandStill = data.KeyBinding{Key1: [2]byte{KeyStandStill, 0}}
+
+	w.player = data.PlayerUnit{
+		ID:     w.nextUnitID(),
+		Skills: make(map[skill.ID]skill.Points),
+		Stats: stat.Stats{
+			{ID: stat.Level, Value: 90},
+			{ID: stat.Life, Value: playerLife},
+			{ID: stat.MaxLife, Value: playerLife},
+			{ID: stat.Mana, Value: playerMana},
+			{ID: stat.MaxMana, Value: playerMana},
+		},
+	}
+	if cfg != nil {
+		w.player.Name = cfg.CharacterName
+	}
+
+	// Objects get the unit IDs and the state they would have when read from memory
+	for id, a := range areas {
+		objects := make([]data.Object, 0, len(a.Objects))
+		for _, o := range a.Objects {
+			o.ID = w.nextUnitID()
+			o.Selectable = true
+			if o.IsPortal() || o.IsRedPortal() {
+				o.Mode = mode.ObjectModeOpened
+			}
+			objects = append(objects, o)
+		}
+		w.objects[id] = objects
+	}
+
+	if town, found := areas[area.RogueEncampment]; found {
+		if pos, found := firstWalkableTile(town); found {
+			w.setPlayerPosition(area.RogueEncampment, pos)
+		}
+	}
+
+	return w
+}
+
+// LoadWorld creates a world with the levels returned by the map data provider, a map_client.FixtureProvider can be
+// used to run without the game installed
+func LoadWorld(provider map_client.MapDataProvider, seed string, diff difficulty.Difficulty, cfg *config.CharacterCfg) (*World, error) {
+	mapData, err := provider.GetMapData(seed, diff)
+	if err != nil {
+		return nil, fmt.Errorf("error loading map data: %w", err)
+	}
+
+	w := NewWorld(game.AreasFromMapData(mapData), cfg)
+	if s, err := strconv.ParseUint(seed, 10, 32); err == nil {
+		w.mapSeed = uint(s)
+	}
+
+	return w, nil
+}
+
+// SetPlayerPosition places the player in the given area and position
+func (w *World) SetPlayerPosition(a area.ID, pos data.Position) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ad, found := w.areas[a]
+	if !found {
+		return fmt.Errorf("area %d not found in map data", a)
+	}
+	if !ad.IsInside(pos) {
+		return fmt.Errorf("position %d,%d is outside of area %d", pos.X, pos.Y, a)
+	}
+
+	w.setPlayerPosition(a, pos)
+
+	return nil
+}
+
+func (w *World) setPlayerPosition(a area.ID, pos data.Position) {
+	w.player.Area = a
+	w.player.Position = pos
+	w.player.Mode = mode.StandingOutsideTown
+	if a.IsTown() {
+		w.player.Mode = mode.StandingInTown
+	}
+	w.updateHover()
+}
+
+// BindSkill binds a skill to a key and sets the damage done to the monsters around the target on each cast, utility
+// skills like buffs or Teleport should have 0 damage
+func (w *World) BindSkill(id skill.ID, key byte, damage int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.player.Skills[id] = skill.Points{Level: 20}
+	w.skillDamage[id] = damage
+	for i, sb := range w.keyBindings.Skills {
+		if sb.SkillID == id || sb.SkillID == 0 {
+			w.keyBindings.Skills[i] = data.SkillBinding{SkillID: id, KeyBinding: data.KeyBinding{Key1: [2]byte{key, 0}}}
+			return
+		}
+	}
+}
+
+// SetMonsterView limits the monsters returned by GetData to the ones closer than the given distance to the player,
+// like the game only has the units of the rooms around the player. 0 returns all the monsters of the area.
+func (w *World) SetMonsterView(tiles int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.monsterView = tiles
+}
+
+// SetLeftSkill sets the skill used on left clicks and the damage it does to the clicked monster
+func (w *World) SetLeftSkill(id skill.ID, damage int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.player.Skills[id] = skill.Points{Level: 20}
+	w.player.LeftSkill = id
+	w.leftDamage = damage
+}
+
+// SpawnMonster adds an idle monster with the given life, it returns the unit ID of the new monster
+func (w *World) SpawnMonster(a area.ID, name npc.ID, t data.MonsterType, pos data.Position, life int) data.UnitID {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	m := data.Monster{
+		UnitID:   w.nextUnitID(),
+		Name:     name,
+		Position: pos,
+		Type:     t,
+		Mode:     mode.NpcStandingStill,
+		Stats: map[stat.ID]int{
+			stat.Life:    life,
+			stat.MaxLife: life,
+		},
+	}
+	w.monsters = append(w.monsters, monster{area: a, Monster: m})
+
+	return m.UnitID
+}
+
+// DropItem adds an item on the ground, it returns the unit ID of the new item
+func (w *World) DropItem(a area.ID, name item.Name, quality item.Quality, pos data.Position) data.UnitID {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	it := data.Item{
+		ID:         item.GetIDByName(string(name)),
+		UnitID:     w.nextUnitID(),
+		Name:       name,
+		Quality:    quality,
+		Position:   pos,
+		Location:   item.Location{LocationType: item.LocationGround},
+		Identified: true,
+	}
+	w.items = append(w.items, groundItem{area: a, Item: it})
+
+	return it.UnitID
+}
+
+// Player returns the current state of the player
+func (w *World) Player() data.PlayerUnit {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.player
+}
+
+// Monsters returns the monsters of the given area, including the dead ones
+func (w *World) Monsters(a area.ID) data.Monsters {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	monsters, corpses := w.areaMonsters(a)
+	return append(monsters, corpses...)
+}
+
+// Objects returns the objects of the given area
+func (w *World) Objects(a area.ID) data.Objects {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return slices.Clone(w.objects[a])
+}
+
+// Inventory returns the items picked up by the player
+func (w *World) Inventory() []data.Item {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return slices.Clone(w.inventory)
+}
+
+func (w *World) SetCharacterConfig(cfg *config.CharacterCfg) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.cfg = cfg
+}
+
+func (w *World) GetData() game.Data {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	a := w.player.Area
+	currentArea := w.areas[a]
+
+	items := slices.Clone(w.inventory)
+	for _, it := range w.items {
+		if it.area == a {
+			it.IsHovered = w.hover.IsHovered && w.hover.UnitType == hoverItem && w.hover.UnitID == it.UnitID
+			items = append(items, it.Item)
+		}
+	}
+
+	objects := slices.Clone(w.objects[a])
+	for i := range objects {
+		objects[i].IsHovered = w.hover.IsHovered && w.hover.UnitType == hoverObject && w.hover.UnitID == objects[i].ID
+	}
+
+	monsters, corpses := w.areaMonsters(a)
+	if w.monsterView > 0 {
+		monsters = slices.DeleteFunc(monsters, func(m data.Monster) bool {
+			return distance(w.player.Position, m.Position) > w.monsterView
+		})
+	}
+	d := data.Data{
+		AreaOrigin:     data.Position{X: currentArea.OffsetX, Y: currentArea.OffsetY},
+		Monsters:       monsters,
+		Corpses:        corpses,
+		NPCs:           currentArea.NPCs,
+		AdjacentLevels: currentArea.AdjacentLevels,
+		Rooms:          currentArea.Rooms,
+		Objects:        objects,
+		PlayerUnit:     w.player,
+		OpenMenus:      w.openMenus,
+		Inventory:      data.Inventory{AllItems: items},
+		HoverData:      w.hover,
+		KeyBindings:    w.keyBindings,
+		IsIngame:       true,
+	}
+	d.PlayerUnit.Skills = make(map[skill.ID]skill.Points, len(w.player.Skills))
+	for id, p := range w.player.Skills {
+		d.PlayerUnit.Skills[id] = p
+	}
+	d.PlayerUnit.Stats = slices.Clone(w.player.Stats)
+	if w.openMenus.Waypoint {
+		for id := range area.WPAddresses {
+			if _, found := w.areas[id]; found {
+				d.PlayerUnit.AvailableWaypoints = append(d.PlayerUnit.AvailableWaypoints, id)
+			}
+		}
+	}
+
+	var cfgCopy config.CharacterCfg
+	if w.cfg != nil {
+		cfgCopy = *w.cfg
+	}
+
+	return game.Data{
+		Data:         d,
+		CharacterCfg: cfgCopy,
+		AreaData:     currentArea,
+		Areas:        w.areas,
+	}
+}
+
+// areaMonsters returns the monsters of the area sorted by distance to the player, like the memory reader does.
+// Dead monsters are returned apart, as corpses.
+func (w *World) areaMonsters(a area.ID) (data.Monsters, data.Monsters) {
+	monsters := make(data.Monsters, 0)
+	corpses := make(data.Monsters, 0)
+	for _, m := range w.monsters {
+		if m.area != a {
+			continue
+		}
+		mo := m.Monster
+		mo.Stats = make(map[stat.ID]int, len(m.Stats))
+		for id, v := range m.Stats {
+			mo.Stats[id] = v
+		}
+		if mo.Stats[stat.Life] <= 0 {
+			corpses = append(corpses, mo)
+			continue
+		}
+		mo.IsHovered = w.hover.IsHovered && w.hover.UnitType == hoverMonster && w.hover.UnitID == m.UnitID
+		monsters = append(monsters, mo)
+	}
+
+	slices.SortStableFunc(monsters, func(i, j data.Monster) int {
+		return distance(w.player.Position, i.Position) - distance(w.player.Position, j.Position)
+	})
+
+	return monsters, corpses
+}
+
+func (w *World) InGame() bool {
+	return true
+}
+
+func (w *World) IsInCharacterSelectionScreen() bool {
+	return false
+}
+
+func (w *World) IsInLobby() bool {
+	return false
+}
+
+func (w *World) IsOnline() bool {
+	return false
+}
+
+func (w *World) LegacyGraphics() bool {
+	return false
+}
+
+func (w *World) MapSeed() uint {
+	return w.mapSeed
+}
+
+// Screenshot returns a black image, nothing is rendered
+func (w *World) Screenshot() image.Image {
+	return image.NewRGBA(image.Rect(0, 0, gameAreaSizeX, gameAreaSizeY))
+}
+
+func (w *World) GameAreaSize() (int, int) {
+	return gameAreaSizeX, gameAreaSizeY
+}
+
+func (w *World) nextUnitID() data.UnitID {
+	w.lastUnitID++
+	return w.lastUnitID
+}
+
+func firstWalkableTile(a game.AreaData) (data.Position, bool) {
+	for y := 0; y < a.Height; y++ {
+		for x := 0; x < a.Width; x++ {
+			if a.CollisionGrid[y][x] == game.CollisionTypeWalkable {
+				return data.Position{X: x + a.OffsetX, Y: y + a.OffsetY}, true
+			}
+		}
+	}
+
+	return data.Position{}, false
+}
@@ -0,0 +1,194 @@
+package sim
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/item"
+	"github.com/hectorgimenez/d2go/pkg/data/npc"
+	"github.com/hectorgimenez/d2go/pkg/data/object"
+	"github.com/hectorgimenez/d2go/pkg/data/skill"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/ui"
+)
+
+func testArea(id area.ID, offsetX, offsetY, width, height int) game.AreaData {
+	cg := make([][]game.CollisionType, height)
+	for y := range cg {
+		cg[y] = make([]game.CollisionType, width)
+		for x := range cg[y] {
+			cg[y][x] = game.CollisionTypeWalkable
+		}
+	}
+
+	return game.AreaData{Area: id, Name: id.Area().Name, Grid: game.NewGrid(cg, offsetX, offsetY)}
+}
+
+// screenCoords is the transformation used by the bot to click on the world
+func screenCoords(from, to data.Position) (int, int) {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	return int(float32(dx-dy)*19.8 + gameAreaSizeX/2), int(float32(dx+dy)*9.9 + gameAreaSizeY/2)
+}
+
+func newTestWorld(t *testing.T, areas ...game.AreaData) *World {
+	t.Helper()
+
+	m := make(map[area.ID]game.AreaData)
+	for _, a := range areas {
+		m[a.Area] = a
+	}
+	w := NewWorld(m, nil)
+	if err := w.SetPlayerPosition(areas[0].Area, data.Position{X: areas[0].OffsetX + 10, Y: areas[0].OffsetY + 10}); err != nil {
+		t.Fatal(err)
+	}
+
+	return w
+}
+
+func (w *World) moveTo(to data.Position) {
+	w.MovePointer(screenCoords(w.Player().Position, to))
+	w.PressKey(KeyForceMove)
+}
+
+func (w *World) clickOn(btn game.MouseButton, to data.Position) {
+	x, y := screenCoords(w.Player().Position, to)
+	w.Click(btn, x, y)
+}
+
+func TestForceMoveStopsInFrontOfWalls(t *testing.T) {
+	a := testArea(area.BloodMoor, 1000, 2000, 40, 40)
+	for y := range a.CollisionGrid {
+		a.CollisionGrid[y][20] = game.CollisionTypeNonWalkable
+	}
+	w := newTestWorld(t, a)
+
+	w.moveTo(data.Position{X: 1015, Y: 2010})
+	if pos := w.Player().Position; pos != (data.Position{X: 1015, Y: 2010}) {
+		t.Fatalf("Expected player at 1015,2010, got %v", pos)
+	}
+
+	w.moveTo(data.Position{X: 1025, Y: 2010})
+	if pos := w.Player().Position; pos != (data.Position{X: 1019, Y: 2010}) {
+		t.Fatalf("Expected player stopped at 1019,2010, got %v", pos)
+	}
+}
+
+func TestWalkingAcrossTheBorderChangesArea(t *testing.T) {
+	bloodMoor := testArea(area.BloodMoor, 1000, 2000, 40, 40)
+	bloodMoor.AdjacentLevels = []data.Level{{Area: area.ColdPlains, Position: data.Position{X: 1039, Y: 2010}}}
+	coldPlains := testArea(area.ColdPlains, 1040, 2000, 40, 40)
+	w := newTestWorld(t, bloodMoor, coldPlains)
+
+	w.moveTo(data.Position{X: 1030, Y: 2010})
+	w.moveTo(data.Position{X: 1045, Y: 2010})
+
+	p := w.Player()
+	if p.Area != area.ColdPlains || p.Position != (data.Position{X: 1045, Y: 2010}) {
+		t.Fatalf("Expected player at 1045,2010 in Cold Plains, got %v in %d", p.Position, p.Area)
+	}
+	if d := w.GetData(); d.AreaData.Area != area.ColdPlains || len(d.AdjacentLevels) != 0 {
+		t.Errorf("Expected Cold Plains area data, got %d", d.AreaData.Area)
+	}
+}
+
+func TestSkillsDamageMonstersAroundTheTarget(t *testing.T) {
+	w := newTestWorld(t, testArea(area.BloodMoor, 1000, 2000, 60, 60))
+	w.BindSkill(skill.Teleport, 'F', 0)
+	w.BindSkill(skill.Blizzard, 'G', 60)
+	near := w.SpawnMonster(area.BloodMoor, npc.Zombie, data.MonsterTypeNone, data.Position{X: 1020, Y: 2020}, 100)
+	far := w.SpawnMonster(area.BloodMoor, npc.Zombie, data.MonsterTypeNone, data.Position{X: 1030, Y: 2030}, 100)
+
+	d := w.GetData()
+	kb, found := d.KeyBindings.KeyBindingForSkill(skill.Blizzard)
+	if !found {
+		t.Fatal("Expected a key binding for Blizzard")
+	}
+	w.PressKey(kb.Key1[0])
+	for range 2 {
+		w.clickOn(game.RightButton, data.Position{X: 1021, Y: 2020})
+	}
+
+	d = w.GetData()
+	if d.PlayerUnit.RightSkill != skill.Blizzard {
+		t.Errorf("Expected Blizzard as right skill, got %v", d.PlayerUnit.RightSkill)
+	}
+	if m, found := d.Corpses.FindByID(near); !found || m.Stats[stat.Life] != 0 {
+		t.Errorf("Expected monster in range killed, got %+v", m)
+	}
+	if m, _ := d.Monsters.FindByID(far); m.Stats[stat.Life] != 100 {
+		t.Errorf("Expected monster out of range untouched, life %d", m.Stats[stat.Life])
+	}
+	if enemies := d.Monsters.Enemies(); len(enemies) != 1 || enemies[0].UnitID != far {
+		t.Errorf("Expected only the monster out of range alive, got %v", enemies)
+	}
+
+	w.PressKey('F')
+	w.clickOn(game.RightButton, data.Position{X: 1035, Y: 2025})
+	if pos := w.Player().Position; pos != (data.Position{X: 1035, Y: 2025}) {
+		t.Errorf("Expected teleport to 1035,2025, got %v", pos)
+	}
+}
+
+func TestHoveredItemsAndObjectsCanBeClicked(t *testing.T) {
+	a := testArea(area.BloodMoor, 1000, 2000, 40, 40)
+	a.Objects = []data.Object{{Name: object.Name(5), Position: data.Position{X: 1020, Y: 2015}}}
+	w := newTestWorld(t, a)
+	id := w.DropItem(area.BloodMoor, "Jewel", item.QualityMagic, data.Position{X: 1012, Y: 2012})
+
+	w.MovePointer(screenCoords(w.Player().Position, data.Position{X: 1011, Y: 2011}))
+	d := w.GetData()
+	if !d.HoverData.IsHovered || d.HoverData.UnitID != id {
+		t.Fatalf("Expected item hovered, got %+v", d.HoverData)
+	}
+	if it, found := d.Inventory.FindByID(id); !found || !it.IsHovered || it.Location.LocationType != item.LocationGround {
+		t.Fatalf("Expected hovered item on the ground, got %+v", it)
+	}
+
+	w.clickOn(game.LeftButton, data.Position{X: 1011, Y: 2011})
+	if inv := w.Inventory(); len(inv) != 1 || inv[0].UnitID != id || inv[0].Location.LocationType != item.LocationInventory {
+		t.Fatalf("Expected item picked up, got %+v", inv)
+	}
+
+	chest := w.GetData().Objects[0]
+	if !chest.IsChest() || !chest.Selectable {
+		t.Fatalf("Expected a selectable chest, got %+v", chest)
+	}
+	w.moveTo(data.Position{X: 1018, Y: 2013})
+	w.MovePointer(screenCoords(w.Player().Position, data.Position{X: 1018, Y: 2013}))
+	if !w.GetData().Objects[0].IsHovered {
+		t.Fatal("Expected chest hovered")
+	}
+	w.clickOn(game.LeftButton, data.Position{X: 1018, Y: 2013})
+	if w.GetData().Objects[0].Selectable {
+		t.Error("Expected chest opened")
+	}
+}
+
+func TestWaypointMenuMovesToTheSelectedArea(t *testing.T) {
+	town := testArea(area.RogueEncampment, 1000, 2000, 40, 40)
+	town.Objects = []data.Object{{Name: object.WaypointPortal, Position: data.Position{X: 1020, Y: 2020}}}
+	coldPlains := testArea(area.ColdPlains, 5000, 6000, 40, 40)
+	coldPlains.Objects = []data.Object{{Name: object.Act1WildernessWaypoint, Position: data.Position{X: 5030, Y: 6030}}}
+	w := newTestWorld(t, town, coldPlains)
+
+	w.moveTo(data.Position{X: 1018, Y: 2018})
+	w.clickOn(game.LeftButton, data.Position{X: 1019, Y: 2019})
+	if !w.GetData().OpenMenus.Waypoint {
+		t.Fatal("Expected waypoint menu open")
+	}
+
+	wp := area.WPAddresses[area.ColdPlains]
+	layout := ui.DefaultProfile.D2R
+	w.Click(game.LeftButton, layout.WpTabStart.X+(wp.Tab-1)*layout.WpTabWidth+layout.WpTabWidth/2, layout.WpTabStart.Y)
+	w.Click(game.LeftButton, layout.WpListStart.X, layout.WpListStart.Y+(wp.Row-1)*layout.WpAreaButtonHeight+layout.WpAreaButtonHeight/2)
+
+	d := w.GetData()
+	if d.PlayerUnit.Area != area.ColdPlains || d.OpenMenus.Waypoint {
+		t.Fatalf("Expected player in Cold Plains with the menu closed, got area %d", d.PlayerUnit.Area)
+	}
+	if pos := d.PlayerUnit.Position; pos.X < 5028 || pos.X > 5032 || pos.Y < 6028 || pos.Y > 6032 {
+		t.Errorf("Expected player next to the waypoint, got %v", pos)
+	}
+}
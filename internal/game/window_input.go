@@ -0,0 +1,114 @@
+//go:build windows
+
+package game
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/utils/winproc"
+	"github.com/lxn/win"
+)
+
+const (
+	keyPressMinTime = 40 // ms
+	keyPressMaxTime = 90 // ms
+)
+
+// WindowInput sends the input events as window messages to the game, modifiers are faked through the memory injector
+type WindowInput struct {
+	gr *MemoryReader
+	gi *MemoryInjector
+}
+
+func NewWindowInput(gr *MemoryReader, gi *MemoryInjector) *WindowInput {
+	return &WindowInput{
+		gr: gr,
+		gi: gi,
+	}
+}
+
+func (wi *WindowInput) MovePointer(x, y int) {
+	wi.gr.updateWindowPositionData()
+	x = wi.gr.WindowLeftX + x
+	y = wi.gr.WindowTopY + y
+
+	wi.gi.CursorPos(x, y)
+	lParam := calculateLparam(x, y)
+	win.SendMessage(wi.gr.HWND, win.WM_NCHITTEST, 0, lParam)
+	win.SendMessage(wi.gr.HWND, win.WM_SETCURSOR, 0x000105A8, 0x2010001)
+	win.PostMessage(wi.gr.HWND, win.WM_MOUSEMOVE, 0, lParam)
+}
+
+func (wi *WindowInput) Click(btn MouseButton, x, y int) {
+	wi.MovePointer(x, y)
+	x = wi.gr.WindowLeftX + x
+	y = wi.gr.WindowTopY + y
+
+	lParam := calculateLparam(x, y)
+	buttonDown := uint32(win.WM_LBUTTONDOWN)
+	buttonUp := uint32(win.WM_LBUTTONUP)
+	if btn == RightButton {
+		buttonDown = win.WM_RBUTTONDOWN
+		buttonUp = win.WM_RBUTTONUP
+	}
+
+	win.SendMessage(wi.gr.HWND, buttonDown, 1, lParam)
+	sleepTime := rand.Intn(keyPressMaxTime-keyPressMinTime) + keyPressMinTime
+	time.Sleep(time.Duration(sleepTime) * time.Millisecond)
+	win.SendMessage(wi.gr.HWND, buttonUp, 1, lParam)
+}
+
+func (wi *WindowInput) ClickWithModifier(btn MouseButton, x, y int, modifier ModifierKey) {
+	wi.gi.OverrideGetKeyState(byte(modifier))
+	wi.Click(btn, x, y)
+	wi.gi.RestoreGetKeyState()
+}
+
+func (wi *WindowInput) PressKey(key byte) {
+	win.PostMessage(wi.gr.HWND, win.WM_KEYDOWN, uintptr(key), calculatelParam(key, true))
+	sleepTime := rand.Intn(keyPressMaxTime-keyPressMinTime) + keyPressMinTime
+	time.Sleep(time.Duration(sleepTime) * time.Millisecond)
+	win.PostMessage(wi.gr.HWND, win.WM_KEYUP, uintptr(key), calculatelParam(key, false))
+}
+
+func (wi *WindowInput) PressKeyWithModifier(key byte, modifier ModifierKey) {
+	wi.gi.OverrideGetKeyState(byte(modifier))
+	wi.PressKey(key)
+	wi.gi.RestoreGetKeyState()
+}
+
+func (wi *WindowInput) KeySequence(keys ...byte) {
+	for _, key := range keys {
+		wi.PressKey(key)
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func (wi *WindowInput) KeyDown(key byte) {
+	win.PostMessage(wi.gr.HWND, win.WM_KEYDOWN, uintptr(key), calculatelParam(key, true))
+}
+
+func (wi *WindowInput) KeyUp(key byte) {
+	win.PostMessage(wi.gr.HWND, win.WM_KEYUP, uintptr(key), calculatelParam(key, false))
+}
+
+func calculateLparam(x, y int) uintptr {
+	return uintptr(y<<16 | x)
+}
+
+func calculatelParam(keyCode byte, down bool) uintptr {
+	ret, _, _ := winproc.MapVirtualKey.Call(uintptr(keyCode), 0)
+	scanCode := int(ret)
+	repeatCount := 1
+	extendedKeyFlag := 0
+	contextCode := 0
+	previousKeyState := 0
+	transitionState := 0
+	if !down {
+		transitionState = 1
+	}
+
+	lParam := uintptr((repeatCount & 0xFFFF) | (scanCode << 16) | (extendedKeyFlag << 24) | (contextCode << 29) | (previousKeyState << 30) | (transitionState << 31))
+	return lParam
+}
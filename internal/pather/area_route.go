@@ -0,0 +1,120 @@
+package pather
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+// Estimated costs in tiles, used to compare walking through areas against taking a waypoint
+const (
+	areaTransitionCost = 40
+	waypointCost       = 120
+	returnTownCost     = 80
+)
+
+// AreaRoute is the cheapest way to reach an area, an optional waypoint to take first and the areas to move through
+type AreaRoute struct {
+	UseWayPoint bool
+	WayPoint    area.ID
+	// Areas to move through in order, last one is the destination
+	Areas []area.ID
+}
+
+// PlanAreaRoute returns the cheapest route to the given area, only the discovered waypoints are considered. The
+// waypoint menu only lists the waypoints of the selected act, so the caller keeps the ones seen so far.
+func (pf *PathFinder) PlanAreaRoute(target area.ID, discovered []area.ID) (AreaRoute, error) {
+	return planAreaRoute(pf.data.Areas, pf.data.PlayerUnit.Area, pf.data.PlayerUnit.Position, target, discovered)
+}
+
+type areaRouteNode struct {
+	area       area.ID
+	entry      data.Position
+	entryKnown bool
+	cost       int
+	route      AreaRoute
+}
+
+func planAreaRoute(areas map[area.ID]game.AreaData, current area.ID, position data.Position, target area.ID, discovered []area.ID) (AreaRoute, error) {
+	if current == target {
+		return AreaRoute{}, nil
+	}
+
+	open := []areaRouteNode{{area: current, entry: position, entryKnown: true}}
+
+	// Waypoints are sorted to always get the same route when costs are the same
+	wpAreas := make([]area.ID, 0, len(discovered))
+	for _, wpArea := range discovered {
+		if _, found := area.WPAddresses[wpArea]; found && !slices.Contains(wpAreas, wpArea) {
+			wpAreas = append(wpAreas, wpArea)
+		}
+	}
+	slices.Sort(wpAreas)
+
+	for _, wpArea := range wpAreas {
+		if wpArea == current {
+			continue
+		}
+
+		cost := waypointCost
+		if !current.IsTown() {
+			cost += returnTownCost
+		}
+		entry, found := waypointPosition(areas[wpArea])
+		open = append(open, areaRouteNode{area: wpArea, entry: entry, entryKnown: found, cost: cost, route: AreaRoute{UseWayPoint: true, WayPoint: wpArea}})
+	}
+
+	type visitKey struct {
+		area  area.ID
+		entry data.Position
+	}
+	visited := make(map[visitKey]bool)
+
+	for len(open) > 0 {
+		// Small graph, a linear search for the cheapest node is good enough
+		cheapest := 0
+		for i, n := range open {
+			if n.cost < open[cheapest].cost {
+				cheapest = i
+			}
+		}
+		node := open[cheapest]
+		open = slices.Delete(open, cheapest, cheapest+1)
+
+		if node.area == target {
+			return node.route, nil
+		}
+
+		key := visitKey{area: node.area, entry: node.entry}
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+
+		for _, lvl := range areas[node.area].AdjacentLevels {
+			cost := node.cost + areaTransitionCost
+			if node.entryKnown {
+				cost += DistanceFromPoint(node.entry, lvl.Position)
+			}
+
+			route := AreaRoute{UseWayPoint: node.route.UseWayPoint, WayPoint: node.route.WayPoint}
+			route.Areas = append(slices.Clone(node.route.Areas), lvl.Area)
+			open = append(open, areaRouteNode{area: lvl.Area, entry: lvl.Position, entryKnown: true, cost: cost, route: route})
+		}
+	}
+
+	return AreaRoute{}, fmt.Errorf("no route found to area %s", target.Area().Name)
+}
+
+func waypointPosition(ad game.AreaData) (data.Position, bool) {
+	for _, o := range ad.Objects {
+		if o.IsWaypoint() {
+			return o.Position, true
+		}
+	}
+
+	return data.Position{}, false
+}
@@ -0,0 +1,111 @@
+package pather
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/object"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+// routeAreas builds a straight act 1 chain, each area is 200 tiles wide with its waypoint in the middle and the exits
+// on the borders, positions are shared between areas like the game coordinates
+func routeAreas() map[area.ID]game.AreaData {
+	chain := []area.ID{area.RogueEncampment, area.BloodMoor, area.ColdPlains, area.StonyField, area.DarkWood, area.BlackMarsh}
+
+	areas := make(map[area.ID]game.AreaData, len(chain))
+	for i, id := range chain {
+		ad := game.AreaData{
+			Area:    id,
+			Objects: []data.Object{{Name: object.WaypointPortal, Position: data.Position{X: i*200 + 100, Y: 100}}},
+		}
+		if i > 0 {
+			ad.AdjacentLevels = append(ad.AdjacentLevels, data.Level{Area: chain[i-1], Position: data.Position{X: i * 200, Y: 100}})
+		}
+		if i < len(chain)-1 {
+			ad.AdjacentLevels = append(ad.AdjacentLevels, data.Level{Area: chain[i+1], Position: data.Position{X: (i + 1) * 200, Y: 100}})
+		}
+		areas[id] = ad
+	}
+
+	return areas
+}
+
+func TestPlanAreaRoute(t *testing.T) {
+	tests := map[string]struct {
+		current    area.ID
+		position   data.Position
+		target     area.ID
+		discovered []area.ID
+		want       AreaRoute
+	}{
+		"waypoint from town": {
+			current:    area.RogueEncampment,
+			position:   data.Position{X: 10, Y: 100},
+			target:     area.BlackMarsh,
+			discovered: []area.ID{area.RogueEncampment, area.ColdPlains, area.BlackMarsh},
+			want:       AreaRoute{UseWayPoint: true, WayPoint: area.BlackMarsh},
+		},
+		"closest discovered waypoint then walk": {
+			current:    area.RogueEncampment,
+			position:   data.Position{X: 10, Y: 100},
+			target:     area.BlackMarsh,
+			discovered: []area.ID{area.RogueEncampment, area.ColdPlains},
+			want:       AreaRoute{UseWayPoint: true, WayPoint: area.ColdPlains, Areas: []area.ID{area.StonyField, area.DarkWood, area.BlackMarsh}},
+		},
+		"walk without discovered waypoints": {
+			current:  area.RogueEncampment,
+			position: data.Position{X: 10, Y: 100},
+			target:   area.ColdPlains,
+			want:     AreaRoute{Areas: []area.ID{area.BloodMoor, area.ColdPlains}},
+		},
+		"undiscovered waypoints are ignored": {
+			current:    area.RogueEncampment,
+			position:   data.Position{X: 10, Y: 100},
+			target:     area.ColdPlains,
+			discovered: []area.ID{area.RogueEncampment, area.TowerCellarLevel1},
+			want:       AreaRoute{Areas: []area.ID{area.BloodMoor, area.ColdPlains}},
+		},
+		"walk to the next area instead of going back to town": {
+			current:    area.StonyField,
+			position:   data.Position{X: 700, Y: 100},
+			target:     area.DarkWood,
+			discovered: []area.ID{area.RogueEncampment, area.DarkWood},
+			want:       AreaRoute{Areas: []area.ID{area.DarkWood}},
+		},
+		"waypoint from the wilderness": {
+			current:    area.StonyField,
+			position:   data.Position{X: 700, Y: 100},
+			target:     area.BlackMarsh,
+			discovered: []area.ID{area.RogueEncampment, area.BlackMarsh},
+			want:       AreaRoute{UseWayPoint: true, WayPoint: area.BlackMarsh},
+		},
+		"already there": {
+			current:  area.DarkWood,
+			position: data.Position{X: 900, Y: 100},
+			target:   area.DarkWood,
+			want:     AreaRoute{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			route, err := planAreaRoute(routeAreas(), tc.current, tc.position, tc.target, tc.discovered)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if route.UseWayPoint != tc.want.UseWayPoint || route.WayPoint != tc.want.WayPoint || !slices.Equal(route.Areas, tc.want.Areas) {
+				t.Errorf("expected %+v, got %+v", tc.want, route)
+			}
+		})
+	}
+}
+
+func TestPlanAreaRouteUnreachable(t *testing.T) {
+	discovered := []area.ID{area.RogueEncampment, area.BlackMarsh}
+	if _, err := planAreaRoute(routeAreas(), area.RogueEncampment, data.Position{X: 10, Y: 100}, area.TamoeHighland, discovered); err == nil {
+		t.Error("expected an error for an area outside the known levels")
+	}
+}
@@ -9,14 +9,14 @@ import (
 )
 
 var directions = []data.Position{
-	{0, 1},   // Down
-	{1, 0},   // Right
-	{0, -1},  // Up
-	{-1, 0},  // Left
-	{1, 1},   // Down-Right (Southeast)
-	{-1, 1},  // Down-Left (Southwest)
-	{1, -1},  // Up-Right (Northeast)
-	{-1, -1}, // Up-Left (Northwest)
+	{X: 0, Y: 1},   // Down
+	{X: 1, Y: 0},   // Right
+	{X: 0, Y: -1},  // Up
+	{X: -1, Y: 0},  // Left
+	{X: 1, Y: 1},   // Down-Right (Southeast)
+	{X: -1, Y: 1},  // Down-Left (Southwest)
+	{X: 1, Y: -1},  // Up-Right (Northeast)
+	{X: -1, Y: -1}, // Up-Left (Northwest)
 }
 
 type Node struct {
@@ -69,7 +69,7 @@ func CalculatePath(g *game.Grid, start, goal data.Position) ([]data.Position, in
 		updateNeighbors(g, current, &neighbors)
 
 		for _, neighbor := range neighbors {
-			newCost := costSoFar[current.X][current.Y] + getCost(g.CollisionGrid[neighbor.Y][neighbor.X])
+			newCost := costSoFar[current.X][current.Y] + TileCost(g.CollisionGrid[neighbor.Y][neighbor.X])
 
 			// Handicap for changing direction, this prevents zig-zagging around obstacles
 			//curDirX, curDirY := direction(cameFrom[current.X][current.Y], current.Position)
@@ -106,7 +106,8 @@ func updateNeighbors(grid *game.Grid, node *Node, neighbors *[]data.Position) {
 	}
 }
 
-func getCost(tileType game.CollisionType) int {
+// TileCost returns the cost of moving into a tile of the given type
+func TileCost(tileType game.CollisionType) int {
 	switch tileType {
 	case game.CollisionTypeWalkable:
 		return 1 // Walkable
@@ -116,6 +117,12 @@ func getCost(tileType game.CollisionType) int {
 		return 4 // Soft blocker
 	case game.CollisionTypeLowPriority:
 		return 20
+	case game.CollisionTypeThreatLow:
+		return 24
+	case game.CollisionTypeThreatMedium:
+		return 48
+	case game.CollisionTypeThreatHigh:
+		return 96
 	default:
 		return math.MaxInt32
 	}
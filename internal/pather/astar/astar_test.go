@@ -21,6 +21,45 @@ func BenchmarkAstar(b *testing.B) {
 	}
 }
 
+func BenchmarkJPS(b *testing.B) {
+	grid := loadGrid()
+
+	start := data.Position{X: 336, Y: 701}
+	goal := data.Position{X: 11, Y: 330}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CalculatePathJPS(grid, start, goal)
+	}
+}
+
+// Same grid without the low priority tiles around the walls, this is where JPS is expected to shine
+func BenchmarkAstarUniformCost(b *testing.B) {
+	grid := loadUniformCostGrid()
+
+	start := data.Position{X: 336, Y: 701}
+	goal := data.Position{X: 11, Y: 330}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CalculatePath(grid, start, goal)
+	}
+}
+
+func BenchmarkJPSUniformCost(b *testing.B) {
+	grid := loadUniformCostGrid()
+
+	start := data.Position{X: 336, Y: 701}
+	goal := data.Position{X: 11, Y: 330}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CalculatePathJPS(grid, start, goal)
+	}
+}
+
+// The path avoids the low priority tiles around the walls, so it's the cheapest path (cost 1308, the same as a plain
+// Dijkstra search on this grid) and not the one with fewer steps (525 steps)
 func TestAstar(t *testing.T) {
 	grid := loadGrid()
 
@@ -28,15 +67,80 @@ func TestAstar(t *testing.T) {
 	goal := data.Position{X: 11, Y: 330}
 
 	p, dist, found := CalculatePath(grid, start, goal)
-	if dist != 546 {
-		t.Errorf("Expected distance to be 546, got %d", dist)
+	if !found {
+		t.Fatalf("Expected path to be found")
 	}
-	if len(p) != 546 {
-		t.Errorf("Expected path length to be 546, got %d", len(p))
+	if dist != 625 {
+		t.Errorf("Expected distance to be 625, got %d", dist)
 	}
-	if !found {
-		t.Errorf("Expected path to be found")
+	if len(p) != 625 {
+		t.Errorf("Expected path length to be 625, got %d", len(p))
+	}
+	if cost := pathCost(t, grid, p); cost != 1308 {
+		t.Errorf("Expected path cost to be 1308, got %d", cost)
+	}
+}
+
+func TestJPSSameCostAsAstar(t *testing.T) {
+	for name, grid := range map[string]*game.Grid{"weighted": loadGrid(), "uniform": loadUniformCostGrid()} {
+		for _, tc := range []struct{ start, goal data.Position }{
+			{data.Position{X: 336, Y: 701}, data.Position{X: 11, Y: 330}},
+			{data.Position{X: 11, Y: 330}, data.Position{X: 336, Y: 701}},
+			{data.Position{X: 336, Y: 701}, data.Position{X: 336, Y: 701}},
+		} {
+			p, _, found := CalculatePath(grid, tc.start, tc.goal)
+			jp, _, jFound := CalculatePathJPS(grid, tc.start, tc.goal)
+			if found != jFound {
+				t.Fatalf("%s: expected found to be %v, got %v", name, found, jFound)
+			}
+			if jp[0] != tc.start || jp[len(jp)-1] != tc.goal {
+				t.Errorf("%s: expected path from %v to %v, got %v to %v", name, tc.start, tc.goal, jp[0], jp[len(jp)-1])
+			}
+			if cost, jCost := pathCost(t, grid, p), pathCost(t, grid, jp); cost != jCost {
+				t.Errorf("%s: expected path cost to be %d, got %d", name, cost, jCost)
+			}
+		}
+	}
+}
+
+func TestJPSUnreachable(t *testing.T) {
+	cg := [][]game.CollisionType{
+		{1, 1, 0, 1},
+		{1, 1, 0, 1},
+		{1, 1, 0, 1},
+	}
+	grid := &game.Grid{Width: 4, Height: 3, CollisionGrid: cg}
+
+	if _, _, found := CalculatePathJPS(grid, data.Position{X: 0, Y: 0}, data.Position{X: 3, Y: 2}); found {
+		t.Errorf("Expected path not to be found")
+	}
+}
+
+// pathCost adds the cost of every step and ensures every step moves to an adjacent tile
+func pathCost(t *testing.T, g *game.Grid, path []data.Position) int {
+	cost := 0
+	for i := 1; i < len(path); i++ {
+		dx, dy := abs(path[i].X-path[i-1].X), abs(path[i].Y-path[i-1].Y)
+		if dx > 1 || dy > 1 || dx+dy == 0 {
+			t.Fatalf("Expected adjacent tiles, got %v and %v", path[i-1], path[i])
+		}
+		cost += TileCost(g.CollisionGrid[path[i].Y][path[i].X])
 	}
+
+	return cost
+}
+
+func loadUniformCostGrid() *game.Grid {
+	grid := loadGrid()
+	for y := range grid.CollisionGrid {
+		for x := range grid.CollisionGrid[y] {
+			if grid.CollisionGrid[y][x] == game.CollisionTypeLowPriority {
+				grid.CollisionGrid[y][x] = game.CollisionTypeWalkable
+			}
+		}
+	}
+
+	return grid
 }
 
 func loadGrid() *game.Grid {
@@ -0,0 +1,288 @@
+package astar
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+// Algorithm selects the search strategy used to calculate a path
+type Algorithm int
+
+const (
+	AlgorithmAStar Algorithm = iota
+	AlgorithmJPS
+)
+
+// Jumps are cut after this distance, scanning huge regions sharing the same cost (like the low priority tiles on
+// teleport grids) from every jump point is way slower than pushing some extra nodes to the queue
+const maxJumpDistance = 32
+
+// Calculate runs the given algorithm, both of them return paths with the same cost
+func Calculate(alg Algorithm, g *game.Grid, start, goal data.Position) ([]data.Position, int, bool) {
+	if alg == AlgorithmJPS {
+		return CalculatePathJPS(g, start, goal)
+	}
+
+	return CalculatePath(g, start, goal)
+}
+
+// CalculatePathJPS finds a path using Jump Point Search. Straight and diagonal runs over tiles sharing the same cost
+// are skipped without pushing them to the queue, tiles touching a different cost (walls, low priority, monsters or
+// objects) are always stopped at and fully expanded, so weighted tiles are handled exactly like CalculatePath does.
+func CalculatePathJPS(g *game.Grid, start, goal data.Position) ([]data.Position, int, bool) {
+	if !inBounds(g, start.X, start.Y) || !inBounds(g, goal.X, goal.Y) {
+		return nil, 0, false
+	}
+
+	j := jumper{g: g, goal: goal}
+
+	// Flat slices instead of [][] matrices, only jump points are stored
+	costSoFar := make([]int32, g.Width*g.Height)
+	cameFrom := make([]int32, g.Width*g.Height)
+	for i := range costSoFar {
+		costSoFar[i] = math.MaxInt32
+	}
+
+	pq := make(PriorityQueue, 0)
+	heap.Init(&pq)
+
+	startIdx := start.Y*g.Width + start.X
+	costSoFar[startIdx] = 0
+	cameFrom[startIdx] = -1
+	heap.Push(&pq, &Node{Position: start, Cost: 0, Priority: chebyshev(start, goal)})
+
+	dirs := make([]data.Position, 0, 8)
+
+	for pq.Len() > 0 {
+		current := heap.Pop(&pq).(*Node)
+		currentIdx := current.Y*g.Width + current.X
+
+		// Stale entry, a cheaper one has been already processed
+		if current.Cost > int(costSoFar[currentIdx]) {
+			continue
+		}
+
+		if current.Position == goal {
+			path := j.buildPath(cameFrom, currentIdx)
+			return path, len(path), true
+		}
+
+		j.successorDirections(current.Position, int(cameFrom[currentIdx]), &dirs)
+		for _, d := range dirs {
+			jp, found := j.jump(current.Position, d.X, d.Y)
+			if !found {
+				continue
+			}
+
+			newCost := current.Cost + j.segmentCost(current.Position, jp)
+			jpIdx := jp.Y*g.Width + jp.X
+			if newCost < int(costSoFar[jpIdx]) {
+				costSoFar[jpIdx] = int32(newCost)
+				cameFrom[jpIdx] = int32(currentIdx)
+				heap.Push(&pq, &Node{Position: jp, Cost: newCost, Priority: newCost + chebyshev(jp, goal)})
+			}
+		}
+	}
+
+	return nil, 0, false
+}
+
+type jumper struct {
+	g    *game.Grid
+	goal data.Position
+}
+
+func (j jumper) walkable(x, y int) bool {
+	return inBounds(j.g, x, y) && j.g.CollisionGrid[y][x] != game.CollisionTypeNonWalkable
+}
+
+// uniform returns true when every walkable neighbor has the same cost as the tile itself, pruning rules are only
+// valid on those tiles
+func (j jumper) uniform(x, y int) bool {
+	c := j.g.CollisionGrid[y][x]
+	for _, d := range directions {
+		nx, ny := x+d.X, y+d.Y
+		if j.walkable(nx, ny) && j.g.CollisionGrid[ny][nx] != c {
+			return false
+		}
+	}
+
+	return true
+}
+
+// successorDirections returns the directions to jump from the given node, all of them for the start node and for
+// nodes next to a different cost, natural and forced neighbors for the rest
+func (j jumper) successorDirections(p data.Position, parentIdx int, dirs *[]data.Position) {
+	*dirs = (*dirs)[:0]
+
+	if parentIdx < 0 || !j.uniform(p.X, p.Y) {
+		*dirs = append(*dirs, directions...)
+		return
+	}
+
+	parent := data.Position{X: parentIdx % j.g.Width, Y: parentIdx / j.g.Width}
+	dx, dy := direction(parent, p)
+	dx, dy = sign(dx), sign(dy)
+	x, y := p.X, p.Y
+
+	switch {
+	case dx != 0 && dy != 0:
+		*dirs = append(*dirs, data.Position{X: dx}, data.Position{Y: dy}, data.Position{X: dx, Y: dy})
+		if !j.walkable(x-dx, y) && j.walkable(x-dx, y+dy) {
+			*dirs = append(*dirs, data.Position{X: -dx, Y: dy})
+		}
+		if !j.walkable(x, y-dy) && j.walkable(x+dx, y-dy) {
+			*dirs = append(*dirs, data.Position{X: dx, Y: -dy})
+		}
+	case dx != 0:
+		*dirs = append(*dirs, data.Position{X: dx})
+		if !j.walkable(x, y+1) && j.walkable(x+dx, y+1) {
+			*dirs = append(*dirs, data.Position{X: dx, Y: 1})
+		}
+		if !j.walkable(x, y-1) && j.walkable(x+dx, y-1) {
+			*dirs = append(*dirs, data.Position{X: dx, Y: -1})
+		}
+	default:
+		*dirs = append(*dirs, data.Position{Y: dy})
+		if !j.walkable(x+1, y) && j.walkable(x+1, y+dy) {
+			*dirs = append(*dirs, data.Position{X: 1, Y: dy})
+		}
+		if !j.walkable(x-1, y) && j.walkable(x-1, y+dy) {
+			*dirs = append(*dirs, data.Position{X: -1, Y: dy})
+		}
+	}
+}
+
+// jump moves from p in the given direction until it finds the goal, a tile with forced neighbors, a tile next
+// to a different cost or the max jump distance is reached
+func (j jumper) jump(p data.Position, dx, dy int) (data.Position, bool) {
+	x, y := p.X+dx, p.Y+dy
+	if !j.walkable(x, y) {
+		return data.Position{}, false
+	}
+
+	// Only the first tile needs the full neighborhood check, from there on just the new tiles entering it
+	uniform := j.uniform(x, y)
+	for steps := 1; ; steps++ {
+		if x == j.goal.X && y == j.goal.Y || !uniform || steps >= maxJumpDistance {
+			return data.Position{X: x, Y: y}, true
+		}
+
+		switch {
+		case dx != 0 && dy != 0:
+			if !j.walkable(x-dx, y) && j.walkable(x-dx, y+dy) || !j.walkable(x, y-dy) && j.walkable(x+dx, y-dy) {
+				return data.Position{X: x, Y: y}, true
+			}
+
+			// Diagonal tiles are jump points if any of the straight runs starting from them finds one
+			if _, found := j.jump(data.Position{X: x, Y: y}, dx, 0); found {
+				return data.Position{X: x, Y: y}, true
+			}
+			if _, found := j.jump(data.Position{X: x, Y: y}, 0, dy); found {
+				return data.Position{X: x, Y: y}, true
+			}
+		case dx != 0:
+			if !j.walkable(x, y+1) && j.walkable(x+dx, y+1) || !j.walkable(x, y-1) && j.walkable(x+dx, y-1) {
+				return data.Position{X: x, Y: y}, true
+			}
+		default:
+			if !j.walkable(x+1, y) && j.walkable(x+1, y+dy) || !j.walkable(x-1, y) && j.walkable(x-1, y+dy) {
+				return data.Position{X: x, Y: y}, true
+			}
+		}
+
+		c := j.g.CollisionGrid[y][x]
+		x, y = x+dx, y+dy
+		if !j.walkable(x, y) {
+			return data.Position{}, false
+		}
+		uniform = j.leadingEdgeUniform(x, y, dx, dy, c)
+	}
+}
+
+// leadingEdgeUniform does the same as uniform for a tile reached from an uniform one, only the neighbors that were
+// not already around the previous tile have to be checked
+func (j jumper) leadingEdgeUniform(x, y, dx, dy int, c game.CollisionType) bool {
+	if dx != 0 {
+		for i := -1; i <= 1; i++ {
+			if j.walkable(x+dx, y+i) && j.g.CollisionGrid[y+i][x+dx] != c {
+				return false
+			}
+		}
+	}
+	if dy != 0 {
+		for i := -1; i <= 1; i++ {
+			if j.walkable(x+i, y+dy) && j.g.CollisionGrid[y+dy][x+i] != c {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// segmentCost returns the cost of moving in a straight or diagonal line, the same way CalculatePath adds it per tile
+func (j jumper) segmentCost(from, to data.Position) int {
+	dx, dy := direction(from, to)
+	dx, dy = sign(dx), sign(dy)
+
+	cost := 0
+	for p := from; p != to; {
+		p = data.Position{X: p.X + dx, Y: p.Y + dy}
+		cost += TileCost(j.g.CollisionGrid[p.Y][p.X])
+	}
+
+	return cost
+}
+
+// buildPath walks back the jump points and fills the tiles in between, so the result is tile by tile as CalculatePath
+func (j jumper) buildPath(cameFrom []int32, goalIdx int) []data.Position {
+	var jumpPoints []data.Position
+	for idx := goalIdx; idx >= 0; idx = int(cameFrom[idx]) {
+		jumpPoints = append(jumpPoints, data.Position{X: idx % j.g.Width, Y: idx / j.g.Width})
+	}
+
+	path := []data.Position{jumpPoints[len(jumpPoints)-1]}
+	for i := len(jumpPoints) - 1; i > 0; i-- {
+		from, to := jumpPoints[i], jumpPoints[i-1]
+		dx, dy := direction(from, to)
+		dx, dy = sign(dx), sign(dy)
+		for p := from; p != to; {
+			p = data.Position{X: p.X + dx, Y: p.Y + dy}
+			path = append(path, p)
+		}
+	}
+
+	return path
+}
+
+func inBounds(g *game.Grid, x, y int) bool {
+	return x >= 0 && x < g.Width && y >= 0 && y < g.Height
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+
+	return 0
+}
+
+// chebyshev is the exact distance on an empty grid where every move, diagonal included, costs 1
+func chebyshev(a, b data.Position) int {
+	return max(abs(a.X-b.X), abs(a.Y-b.Y))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
@@ -0,0 +1,321 @@
+package pather
+
+import (
+	"slices"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+const (
+	// Distances between rooms further than this are not searched, they are estimated as this value plus the
+	// straight distance so they are always more expensive than the searched ones
+	explorationMaxSearchDistance = 300
+	// 2-opt passes are stopped after this amount, the plan is good enough way before reaching it
+	explorationMaxImprovements = 10
+)
+
+// ExplorationStep is a room to visit. Seen rooms are skipped because they are closer than the visibility radius to
+// the way to the room, From is the position of the way they are seen from.
+type ExplorationStep struct {
+	Room data.Room
+	Seen []SeenRoom
+}
+
+type SeenRoom struct {
+	Room data.Room
+	From data.Position
+}
+
+// PlanExploration returns the rooms of the current level in the order they should be visited to cover all of them.
+// Distances are real walking distances, rooms not reachable from the player are discarded and rooms seen on the way
+// to other rooms, closer than the visibility radius, are skipped. Same level and position always return the same plan.
+func (pf *PathFinder) PlanExploration(visibilityRadius int) []ExplorationStep {
+	return planExploration(pf.data.AreaData.Grid, pf.data.Rooms, pf.data.PlayerUnit.Position, visibilityRadius)
+}
+
+func planExploration(g *game.Grid, rooms []data.Room, start data.Position, visibilityRadius int) []ExplorationStep {
+	targetRooms, targets := explorationTargets(g, rooms, start)
+	if len(targets) == 0 {
+		return nil
+	}
+
+	// First point is the player position, the rest are the rooms
+	points := append([]data.Position{g.RelativePosition(start)}, targets...)
+	dist := explorationDistances(g, points)
+	order := twoOpt(dist, nearestNeighborOrder(dist))
+
+	tour := coveredTour(g, points, order, visibilityRadius)
+	plan := make([]ExplorationStep, 0, len(tour))
+	for _, ts := range tour {
+		step := ExplorationStep{Room: targetRooms[ts.point-1]}
+		for _, sp := range ts.seen {
+			step.Seen = append(step.Seen, SeenRoom{
+				Room: targetRooms[sp.point-1],
+				From: data.Position{X: sp.from.X + g.OffsetX, Y: sp.from.Y + g.OffsetY},
+			})
+		}
+		plan = append(plan, step)
+	}
+
+	return plan
+}
+
+// explorationTargets returns the walkable tile closest to the center of every room reachable from the start position,
+// positions are relative to the grid
+func explorationTargets(g *game.Grid, rooms []data.Room, start data.Position) ([]data.Room, []data.Position) {
+	targetRooms := make([]data.Room, 0, len(rooms))
+	targets := make([]data.Position, 0, len(rooms))
+
+	for _, r := range rooms {
+		center := r.GetCenter()
+		for radius := 0; radius <= max(r.Width, r.Height)/2; radius++ {
+			target, found := data.Position{}, false
+			for y := -radius; y <= radius && !found; y++ {
+				for x := -radius; x <= radius && !found; x++ {
+					p := data.Position{X: center.X + x, Y: center.Y + y}
+					if max(abs(x), abs(y)) == radius && r.IsInside(p) && g.IsWalkable(p) && g.IsReachable(start, p) {
+						target, found = p, true
+					}
+				}
+			}
+
+			if found {
+				targetRooms = append(targetRooms, r)
+				targets = append(targets, g.RelativePosition(target))
+				break
+			}
+		}
+	}
+
+	return targetRooms, targets
+}
+
+// explorationDistances returns the walking distance in tiles between every pair of points
+func explorationDistances(g *game.Grid, points []data.Position) [][]int {
+	pointsAt := make(map[int][]int, len(points))
+	for i, p := range points {
+		pointsAt[p.Y*g.Width+p.X] = append(pointsAt[p.Y*g.Width+p.X], i)
+	}
+
+	dist := make([][]int, len(points))
+	search := newGridSearch(g)
+	for i, from := range points {
+		dist[i] = make([]int, len(points))
+		for j, to := range points {
+			dist[i][j] = explorationMaxSearchDistance + max(abs(from.X-to.X), abs(from.Y-to.Y))
+		}
+
+		search.run(from, explorationMaxSearchDistance, func(idx, d int) bool {
+			for _, j := range pointsAt[idx] {
+				dist[i][j] = d
+			}
+			return true
+		})
+	}
+
+	return dist
+}
+
+// nearestNeighborOrder returns an order starting at the first point and always moving to the closest pending one
+func nearestNeighborOrder(dist [][]int) []int {
+	order := []int{0}
+	visited := make([]bool, len(dist))
+	visited[0] = true
+
+	for current := 0; len(order) < len(dist); {
+		next := -1
+		for j := range dist {
+			if !visited[j] && (next == -1 || dist[current][j] < dist[current][next]) {
+				next = j
+			}
+		}
+
+		visited[next] = true
+		order = append(order, next)
+		current = next
+	}
+
+	return order
+}
+
+// twoOpt improves an open tour keeping the first point fixed, segments are reversed while that makes the tour shorter
+func twoOpt(dist [][]int, order []int) []int {
+	legCost := func(i, j int) int {
+		if j >= len(order) {
+			return 0
+		}
+		return dist[order[i]][order[j]]
+	}
+
+	for pass := 0; pass < explorationMaxImprovements; pass++ {
+		improved := false
+		for i := 1; i < len(order)-1; i++ {
+			for k := i + 1; k < len(order); k++ {
+				before := dist[order[i-1]][order[i]] + legCost(k, k+1)
+				after := dist[order[i-1]][order[k]]
+				if k+1 < len(order) {
+					after += dist[order[i]][order[k+1]]
+				}
+
+				if after < before {
+					slices.Reverse(order[i : k+1])
+					improved = true
+				}
+			}
+		}
+
+		if !improved {
+			break
+		}
+	}
+
+	return order
+}
+
+type tourStep struct {
+	point int
+	seen  []seenPoint
+}
+
+// seenPoint is a point skipped by the tour, from is the first position it's seen from
+type seenPoint struct {
+	point int
+	from  data.Position
+}
+
+// coveredTour walks the tour and drops the points seen on the way, a point is seen when any tile of the path to the
+// next point, or the point where we stop, is closer than the visibility radius. Dropped points are kept in the step
+// they are seen on the way to, points seen from the start go to the first step.
+func coveredTour(g *game.Grid, points []data.Position, order []int, visibilityRadius int) []tourStep {
+	bucketSize := max(visibilityRadius, 1)
+	buckets := make(map[data.Position][]int)
+	for i, p := range points {
+		key := data.Position{X: p.X / bucketSize, Y: p.Y / bucketSize}
+		buckets[key] = append(buckets[key], i)
+	}
+
+	covered := make([]bool, len(points))
+	var seen []seenPoint
+	markCovered := func(p data.Position, target int) {
+		for by := p.Y/bucketSize - 1; by <= p.Y/bucketSize+1; by++ {
+			for bx := p.X/bucketSize - 1; bx <= p.X/bucketSize+1; bx++ {
+				for _, i := range buckets[data.Position{X: bx, Y: by}] {
+					dx, dy := points[i].X-p.X, points[i].Y-p.Y
+					if !covered[i] && i != target && dx*dx+dy*dy <= visibilityRadius*visibilityRadius {
+						covered[i] = true
+						seen = append(seen, seenPoint{point: i, from: p})
+					}
+				}
+			}
+		}
+	}
+
+	tour := make([]tourStep, 0, len(order))
+	search := newGridSearch(g)
+	current := order[0]
+	covered[current] = true
+	markCovered(points[current], current)
+	for _, next := range order[1:] {
+		if covered[next] {
+			continue
+		}
+
+		for _, p := range search.path(points[current], points[next]) {
+			markCovered(p, next)
+		}
+		covered[next] = true
+		markCovered(points[next], next)
+
+		tour = append(tour, tourStep{point: next, seen: seen})
+		seen = nil
+		current = next
+	}
+
+	// Every point was seen from the start, the first one is visited so the rest are checked from somewhere
+	if len(tour) == 0 && len(seen) > 0 {
+		tour = append(tour, tourStep{point: seen[0].point, seen: seen[1:]})
+	}
+
+	return tour
+}
+
+// gridSearch is a breadth first search over the walkable tiles where every step costs the same, buffers are reused
+// between runs
+type gridSearch struct {
+	g          *game.Grid
+	dist       []int32
+	parent     []int32
+	visited    []uint32
+	generation uint32
+	queue      []int
+}
+
+func newGridSearch(g *game.Grid) *gridSearch {
+	return &gridSearch{
+		g:       g,
+		dist:    make([]int32, g.Width*g.Height),
+		parent:  make([]int32, g.Width*g.Height),
+		visited: make([]uint32, g.Width*g.Height),
+	}
+}
+
+// run visits the tiles closer than maxDistance in distance order until visit returns false
+func (s *gridSearch) run(from data.Position, maxDistance int, visit func(idx, dist int) bool) {
+	s.generation++
+	startIdx := from.Y*s.g.Width + from.X
+	s.visited[startIdx] = s.generation
+	s.dist[startIdx] = 0
+	s.parent[startIdx] = -1
+	s.queue = append(s.queue[:0], startIdx)
+
+	for head := 0; head < len(s.queue); head++ {
+		idx := s.queue[head]
+		d := int(s.dist[idx])
+		if !visit(idx, d) {
+			return
+		}
+		if d >= maxDistance {
+			continue
+		}
+
+		x, y := idx%s.g.Width, idx/s.g.Width
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				nx, ny := x+dx, y+dy
+				if nx < 0 || ny < 0 || nx >= s.g.Width || ny >= s.g.Height || s.g.CollisionGrid[ny][nx] == game.CollisionTypeNonWalkable {
+					continue
+				}
+
+				nIdx := ny*s.g.Width + nx
+				if s.visited[nIdx] != s.generation {
+					s.visited[nIdx] = s.generation
+					s.dist[nIdx] = int32(d + 1)
+					s.parent[nIdx] = int32(idx)
+					s.queue = append(s.queue, nIdx)
+				}
+			}
+		}
+	}
+}
+
+// path returns the tiles from one position to the other, or just the destination if there is no connection
+func (s *gridSearch) path(from, to data.Position) []data.Position {
+	goalIdx := to.Y*s.g.Width + to.X
+	found := false
+	s.run(from, s.g.Width*s.g.Height, func(idx, _ int) bool {
+		found = idx == goalIdx
+		return !found
+	})
+	if !found {
+		return []data.Position{to}
+	}
+
+	var p []data.Position
+	for idx := goalIdx; idx >= 0; idx = int(s.parent[idx]) {
+		p = append(p, data.Position{X: idx % s.g.Width, Y: idx / s.g.Width})
+	}
+	slices.Reverse(p)
+
+	return p
+}
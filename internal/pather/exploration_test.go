@@ -0,0 +1,182 @@
+package pather
+
+import (
+	"encoding/gob"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+// Rooms are the 10x5 blocks of the fixture, the wall in the middle makes the rooms on the other side closer in a
+// straight line than walking around it
+const explorationFixture = `
+S.........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+..........#.........
+....................`
+
+func TestPlanExplorationVisitsAllRooms(t *testing.T) {
+	grid, start, _ := parseGridFixture(explorationFixture)
+	grid.LabelRegions()
+	rooms := fixtureRooms(grid, 10, 5)
+
+	plan := planExploration(grid, rooms, start, 0)
+	if len(plan) != len(rooms) {
+		t.Fatalf("Expected %d rooms, got %d", len(rooms), len(plan))
+	}
+
+	// All the rooms on the left side first, crossing the wall only once at the bottom
+	for i, step := range plan {
+		if onLeft := step.Room.X < 10; onLeft != (i < 4) {
+			t.Errorf("Expected rooms on the left side to be visited first, got %v", plan)
+			break
+		}
+	}
+}
+
+func TestPlanExplorationSkipsCoveredRooms(t *testing.T) {
+	grid, start, _ := parseGridFixture(explorationFixture)
+	grid.LabelRegions()
+	rooms := fixtureRooms(grid, 10, 5)
+
+	plan := planExploration(grid, rooms, start, 6)
+	if len(plan) >= len(rooms) {
+		t.Errorf("Expected less than %d rooms, got %d", len(rooms), len(plan))
+	}
+
+	// Skipped rooms are kept in the step they are seen from, so every room is still checked once
+	checked := make(map[data.Room]int)
+	for _, step := range plan {
+		checked[step.Room]++
+		for _, seen := range step.Seen {
+			checked[seen.Room]++
+			if d := DistanceFromPoint(seen.From, closestRoomTarget(grid, seen.Room)); d > 6 {
+				t.Errorf("Expected room %v to be seen from closer than the visibility radius, got %d from %v", seen.Room.Position, d, seen.From)
+			}
+		}
+	}
+	for _, r := range rooms {
+		if checked[r] != 1 {
+			t.Errorf("Expected room %v to be visited or seen once, got %d", r.Position, checked[r])
+		}
+	}
+}
+
+func TestPlanExplorationEverythingSeenFromStart(t *testing.T) {
+	grid, start, _ := parseGridFixture(explorationFixture)
+	grid.LabelRegions()
+	rooms := fixtureRooms(grid, 10, 5)[:2]
+
+	plan := planExploration(grid, rooms, start, 50)
+	if len(plan) != 1 || len(plan[0].Seen) != 1 {
+		t.Fatalf("Expected one room to visit and the other one seen, got %+v", plan)
+	}
+}
+
+// closestRoomTarget returns the world position the exploration targets in the room
+func closestRoomTarget(g *game.Grid, r data.Room) data.Position {
+	_, targets := explorationTargets(g, []data.Room{r}, r.GetCenter())
+	return data.Position{X: targets[0].X + g.OffsetX, Y: targets[0].Y + g.OffsetY}
+}
+
+func TestPlanExplorationOnMapFixture(t *testing.T) {
+	grid := loadMapFixture(t)
+	grid.LabelRegions()
+	rooms := fixtureRooms(grid, 40, 40)
+	start := data.Position{X: grid.OffsetX + 336, Y: grid.OffsetY + 701}
+
+	plan := planExploration(grid, rooms, start, 0)
+	if !reflect.DeepEqual(plan, planExploration(grid, rooms, start, 0)) {
+		t.Fatalf("Expected the same plan for the same map and position")
+	}
+
+	// Improved order must never be longer than the nearest neighbor one
+	targetRooms, targets := explorationTargets(grid, rooms, start)
+	dist := explorationDistances(grid, append([]data.Position{grid.RelativePosition(start)}, targets...))
+	greedy := nearestNeighborOrder(dist)
+	improved := twoOpt(dist, slices.Clone(greedy))
+	if tourLength(dist, improved) > tourLength(dist, greedy) {
+		t.Errorf("Expected tour length to be at most %d, got %d", tourLength(dist, greedy), tourLength(dist, improved))
+	}
+	if len(plan) != len(targetRooms) {
+		t.Errorf("Expected %d rooms, got %d", len(targetRooms), len(plan))
+	}
+
+	if covered := planExploration(grid, rooms, start, 20); len(covered) >= len(plan) {
+		t.Errorf("Expected less than %d rooms with visibility radius, got %d", len(plan), len(covered))
+	}
+}
+
+func tourLength(dist [][]int, order []int) int {
+	length := 0
+	for i := 1; i < len(order); i++ {
+		length += dist[order[i-1]][order[i]]
+	}
+
+	return length
+}
+
+// fixtureRooms splits the grid in rooms of the given size in world coordinates, blocks without walkable tiles are skipped
+func fixtureRooms(g *game.Grid, width, height int) []data.Room {
+	var rooms []data.Room
+	for y := 0; y < g.Height; y += height {
+		for x := 0; x < g.Width; x += width {
+			r := data.Room{Position: data.Position{X: g.OffsetX + x, Y: g.OffsetY + y}, Width: min(width, g.Width-x), Height: min(height, g.Height-y)}
+			for p := range roomTiles(r) {
+				if g.IsWalkable(p) {
+					rooms = append(rooms, r)
+					break
+				}
+			}
+		}
+	}
+
+	return rooms
+}
+
+func roomTiles(r data.Room) func(func(data.Position) bool) {
+	return func(yield func(data.Position) bool) {
+		for y := r.Y; y < r.Y+r.Height; y++ {
+			for x := r.X; x < r.X+r.Width; x++ {
+				if !yield(data.Position{X: x, Y: y}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func loadMapFixture(t *testing.T) *game.Grid {
+	f, err := os.Open("astar/durance_of_hate_grid.bin")
+	if err != nil {
+		t.Fatalf("Error opening map fixture: %v", err)
+	}
+	defer f.Close()
+
+	grid := &game.Grid{}
+	if err = gob.NewDecoder(f).Decode(grid); err != nil {
+		t.Fatalf("Error decoding map fixture: %v", err)
+	}
+
+	return grid
+}
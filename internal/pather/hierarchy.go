@@ -0,0 +1,127 @@
+package pather
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/hpa"
+)
+
+// Abstract graphs are only worth it for long paths on big grids, flat searches are fast enough for the rest
+const (
+	hierarchyMinGridSize = 4 * hpa.ClusterSize
+	hierarchyMinDistance = 2 * hpa.ClusterSize
+)
+
+// hierarchy holds the abstract graphs, merged grids and bridged grids for the current game, they are keyed by the
+// static grid they were built from, so the ones from a previous game are never used. The context is canceled when the game changes,
+// graphs still being built for it are dropped.
+type hierarchy struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	mu          sync.Mutex
+	graphs      map[*game.Grid]*hpa.Graph
+	building    map[*game.Grid]bool
+	mergedGrids map[[2]area.ID]*game.Grid
+	bridged     map[*game.Grid]*game.Grid
+}
+
+func newHierarchy() *hierarchy {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &hierarchy{
+		ctx:         ctx,
+		cancel:      cancel,
+		graphs:      make(map[*game.Grid]*hpa.Graph),
+		building:    make(map[*game.Grid]bool),
+		mergedGrids: make(map[[2]area.ID]*game.Grid),
+		bridged:     make(map[*game.Grid]*game.Grid),
+	}
+}
+
+// ResetAreaGraphs drops the data from the previous game and stops the graphs still being built for it. Graphs are
+// built in background the first time a long path is requested in an area, paths are calculated with a flat search
+// until the graph is ready.
+func (pf *PathFinder) ResetAreaGraphs() {
+	pf.hierarchy.Swap(newHierarchy()).cancel()
+}
+
+// graphFor returns the abstract graph for the given static grid if ready, the graph is built in background the first
+// time it's requested
+func (pf *PathFinder) graphFor(g *game.Grid) *hpa.Graph {
+	h := pf.hierarchy.Load()
+	if !isHierarchyWorthIt(g) {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if graph, found := h.graphs[g]; found || h.building[g] {
+		return graph
+	}
+
+	h.building[g] = true
+	go h.build(g)
+
+	return nil
+}
+
+func (h *hierarchy) build(g *game.Grid) {
+	graph, err := hpa.Build(h.ctx, g)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.building, g)
+	if err == nil {
+		h.graphs[g] = graph
+	}
+}
+
+// cachedMergedGrid returns the merged grid of both areas, building and caching it if needed
+func (pf *PathFinder) cachedMergedGrid(origin, destination game.AreaData) *game.Grid {
+	h := pf.hierarchy.Load()
+	key := [2]area.ID{origin.Area, destination.Area}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if g, found := h.mergedGrids[key]; found {
+		return g
+	}
+
+	g := MergeAreaGrids(origin, destination)
+	h.mergedGrids[key] = g
+
+	return g
+}
+
+// cachedBridgedGrid returns a copy of the static grid with the platform gaps bridged, see bridgePlatforms
+func (pf *PathFinder) cachedBridgedGrid(g *game.Grid) *game.Grid {
+	h := pf.hierarchy.Load()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if bridged, found := h.bridged[g]; found {
+		return bridged
+	}
+
+	bridged := g.Copy()
+	bridgePlatforms(bridged)
+	h.bridged[g] = bridged
+
+	return bridged
+}
+
+func isHierarchyWorthIt(g *game.Grid) bool {
+	return g.Width >= hierarchyMinGridSize || g.Height >= hierarchyMinGridSize
+}
+
+func isLongPath(from, to data.Position) bool {
+	return max(abs(from.X-to.X), abs(from.Y-to.Y)) >= hierarchyMinDistance
+}
@@ -0,0 +1,26 @@
+package pather
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+func TestResetAreaGraphsCancelsThePreviousGame(t *testing.T) {
+	pf := NewPathFinder(nil, &game.Data{}, nil, &config.CharacterCfg{})
+	g := teleportGrid(hierarchyMinGridSize, 10, -1, -1)
+
+	if graph := pf.graphFor(g); graph != nil {
+		t.Error("expected the graph to be built in background on the first request")
+	}
+	previous := pf.hierarchy.Load()
+	pf.ResetAreaGraphs()
+
+	if previous.ctx.Err() == nil {
+		t.Error("expected the graphs of the previous game to stop building")
+	}
+	if pf.hierarchy.Load().ctx.Err() != nil {
+		t.Error("expected the graphs of the current game to keep building")
+	}
+}
@@ -0,0 +1,167 @@
+package hpa
+
+import (
+	"context"
+	"math"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+const (
+	// ClusterSize is the width and height in tiles of the clusters the grid is split into
+	ClusterSize = 32
+	// Border openings wider than this get an entrance at each end instead of a single one in the middle
+	maxEntranceWidth = 6
+)
+
+var directions = []data.Position{
+	{X: 0, Y: 1}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: -1, Y: 0},
+	{X: 1, Y: 1}, {X: -1, Y: 1}, {X: 1, Y: -1}, {X: -1, Y: -1},
+}
+
+// Graph is the abstract graph of a grid: entrances between neighbor clusters are the nodes, edges are the steps
+// crossing a border and the precalculated costs between entrances of the same cluster
+type Graph struct {
+	width, height        int
+	clustersX, clustersY int
+	nodes                []node
+	clusterNodes         [][]int32
+	nodeAt               map[data.Position]int32
+}
+
+type node struct {
+	pos     data.Position
+	cluster int
+	edges   []edge
+}
+
+type edge struct {
+	to   int32
+	cost int32
+}
+
+type bounds struct {
+	x0, y0, x1, y1 int
+}
+
+// Build creates the abstract graph for the given grid, costs are based on the static tiles so dynamic obstacles
+// stamped later on the grid are only taken into account when refining the path. Building big grids takes a while, it
+// stops with the context error once the context is done.
+func Build(ctx context.Context, g *game.Grid) (*Graph, error) {
+	gr := &Graph{
+		width:     g.Width,
+		height:    g.Height,
+		clustersX: (g.Width + ClusterSize - 1) / ClusterSize,
+		clustersY: (g.Height + ClusterSize - 1) / ClusterSize,
+		nodeAt:    make(map[data.Position]int32),
+	}
+	gr.clusterNodes = make([][]int32, gr.clustersX*gr.clustersY)
+
+	for cy := 0; cy < gr.clustersY; cy++ {
+		for cx := 0; cx < gr.clustersX; cx++ {
+			b := gr.clusterBounds(cy*gr.clustersX + cx)
+			if b.x1 < g.Width {
+				gr.addEntrances(g, b.x1-1, b.y0, 0, 1, b.y1-b.y0, 1, 0)
+			}
+			if b.y1 < g.Height {
+				gr.addEntrances(g, b.x0, b.y1-1, 1, 0, b.x1-b.x0, 0, 1)
+			}
+		}
+	}
+
+	dist := make([]int32, ClusterSize*ClusterSize)
+	pq := make(tileQueue, 0, ClusterSize*ClusterSize)
+	for cluster, nodes := range gr.clusterNodes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		b := gr.clusterBounds(cluster)
+		for _, from := range nodes {
+			clusterDistances(g, b, gr.nodes[from].pos, dist, &pq)
+			for _, to := range nodes {
+				if from == to {
+					continue
+				}
+				if d := dist[b.index(gr.nodes[to].pos)]; d != math.MaxInt32 {
+					gr.nodes[from].edges = append(gr.nodes[from].edges, edge{to: to, cost: d})
+				}
+			}
+		}
+	}
+
+	return gr, nil
+}
+
+// addEntrances scans a cluster border starting at x, y moving by stepX, stepY, the other side of the border is at
+// crossX, crossY. Openings are split by tile cost, so crossing through the cheap middle of a corridor is not forced to
+// go through the low priority tiles next to the walls, every piece gets one entrance or two if it's too wide.
+func (gr *Graph) addEntrances(g *game.Grid, x, y, stepX, stepY, length, crossX, crossY int) {
+	crossingCost := func(i int) int {
+		a := g.CollisionGrid[y+i*stepY][x+i*stepX]
+		b := g.CollisionGrid[y+i*stepY+crossY][x+i*stepX+crossX]
+		if a == game.CollisionTypeNonWalkable || b == game.CollisionTypeNonWalkable {
+			return 0
+		}
+
+		return astar.TileCost(a) + astar.TileCost(b)
+	}
+
+	for i := 0; i < length; i++ {
+		cost := crossingCost(i)
+		if cost == 0 {
+			continue
+		}
+
+		start := i
+		for i+1 < length && crossingCost(i+1) == cost {
+			i++
+		}
+
+		entrances := []int{(start + i) / 2}
+		if i-start+1 > maxEntranceWidth {
+			entrances = []int{start, i}
+		}
+		for _, e := range entrances {
+			a := data.Position{X: x + e*stepX, Y: y + e*stepY}
+			b := data.Position{X: a.X + crossX, Y: a.Y + crossY}
+			na, nb := gr.nodeFor(a), gr.nodeFor(b)
+			gr.nodes[na].edges = append(gr.nodes[na].edges, edge{to: nb, cost: int32(astar.TileCost(g.CollisionGrid[b.Y][b.X]))})
+			gr.nodes[nb].edges = append(gr.nodes[nb].edges, edge{to: na, cost: int32(astar.TileCost(g.CollisionGrid[a.Y][a.X]))})
+		}
+	}
+}
+
+func (gr *Graph) nodeFor(p data.Position) int32 {
+	if id, found := gr.nodeAt[p]; found {
+		return id
+	}
+
+	id := int32(len(gr.nodes))
+	cluster := gr.clusterOf(p)
+	gr.nodes = append(gr.nodes, node{pos: p, cluster: cluster})
+	gr.clusterNodes[cluster] = append(gr.clusterNodes[cluster], id)
+	gr.nodeAt[p] = id
+
+	return id
+}
+
+func (gr *Graph) clusterOf(p data.Position) int {
+	return p.Y/ClusterSize*gr.clustersX + p.X/ClusterSize
+}
+
+func (gr *Graph) clusterBounds(cluster int) bounds {
+	x0, y0 := cluster%gr.clustersX*ClusterSize, cluster/gr.clustersX*ClusterSize
+
+	return bounds{x0: x0, y0: y0, x1: min(gr.width, x0+ClusterSize), y1: min(gr.height, y0+ClusterSize)}
+}
+
+func (b bounds) index(p data.Position) int {
+	return (p.Y-b.y0)*(b.x1-b.x0) + p.X - b.x0
+}
+
+func (b bounds) contains(x, y int) bool {
+	return x >= b.x0 && x < b.x1 && y >= b.y0 && y < b.y1
+}
@@ -0,0 +1,126 @@
+package hpa
+
+import (
+	"context"
+	"encoding/gob"
+	"os"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+func BenchmarkBuild(b *testing.B) {
+	grid := loadGrid()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Build(context.Background(), grid)
+	}
+}
+
+func BenchmarkFindPath(b *testing.B) {
+	grid := loadGrid()
+	gr, _ := Build(context.Background(), grid)
+
+	start := data.Position{X: 336, Y: 701}
+	goal := data.Position{X: 11, Y: 330}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gr.FindPath(grid, start, goal, astar.AlgorithmAStar)
+	}
+}
+
+func TestFindPathCloseToOptimal(t *testing.T) {
+	grid := loadGrid()
+	gr, _ := Build(context.Background(), grid)
+
+	for _, tc := range []struct{ start, goal data.Position }{
+		{data.Position{X: 336, Y: 701}, data.Position{X: 11, Y: 330}},
+		{data.Position{X: 11, Y: 330}, data.Position{X: 336, Y: 701}},
+		{data.Position{X: 336, Y: 701}, data.Position{X: 340, Y: 690}},
+	} {
+		optimal, _, found := astar.CalculatePath(grid, tc.start, tc.goal)
+		if !found {
+			t.Fatalf("Expected path from %v to %v to be found", tc.start, tc.goal)
+		}
+
+		p, dist, found := gr.FindPath(grid, tc.start, tc.goal, astar.AlgorithmAStar)
+		if !found {
+			t.Fatalf("Expected hierarchical path from %v to %v to be found", tc.start, tc.goal)
+		}
+		if p[0] != tc.start || p[len(p)-1] != tc.goal {
+			t.Errorf("Expected path from %v to %v, got %v to %v", tc.start, tc.goal, p[0], p[len(p)-1])
+		}
+		if dist != len(p) {
+			t.Errorf("Expected distance to be %d, got %d", len(p), dist)
+		}
+
+		// Entrances are fixed tiles on the cluster borders, paths are not optimal but they should be close
+		optimalCost, cost := pathCost(t, grid, optimal), pathCost(t, grid, p)
+		if float64(cost) > float64(optimalCost)*1.2 {
+			t.Errorf("Expected path cost to be at most 20%% over %d, got %d", optimalCost, cost)
+		}
+	}
+}
+
+func TestFindPathUnreachable(t *testing.T) {
+	cg := make([][]game.CollisionType, 40)
+	for y := range cg {
+		cg[y] = make([]game.CollisionType, 80)
+		for x := range cg[y] {
+			if x != 50 {
+				cg[y][x] = game.CollisionTypeWalkable
+			}
+		}
+	}
+	grid := &game.Grid{Width: 80, Height: 40, CollisionGrid: cg}
+
+	gr, _ := Build(context.Background(), grid)
+	if _, _, found := gr.FindPath(grid, data.Position{X: 0, Y: 0}, data.Position{X: 79, Y: 39}, astar.AlgorithmAStar); found {
+		t.Errorf("Expected path not to be found")
+	}
+}
+
+func TestBuildCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if gr, err := Build(ctx, loadGrid()); err == nil || gr != nil {
+		t.Errorf("Expected the build to stop when the context is canceled, got %v", err)
+	}
+}
+
+// pathCost adds the cost of every step and ensures every step moves to an adjacent walkable tile
+func pathCost(t *testing.T, g *game.Grid, path []data.Position) int {
+	cost := 0
+	for i := 1; i < len(path); i++ {
+		dx, dy := abs(path[i].X-path[i-1].X), abs(path[i].Y-path[i-1].Y)
+		if dx > 1 || dy > 1 || dx+dy == 0 {
+			t.Fatalf("Expected adjacent tiles, got %v and %v", path[i-1], path[i])
+		}
+		if !walkable(g, path[i].X, path[i].Y) {
+			t.Fatalf("Expected walkable tile at %v", path[i])
+		}
+		cost += astar.TileCost(g.CollisionGrid[path[i].Y][path[i].X])
+	}
+
+	return cost
+}
+
+func loadGrid() *game.Grid {
+	f, err := os.Open("../astar/durance_of_hate_grid.bin")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	grid := &game.Grid{}
+	if err = gob.NewDecoder(f).Decode(grid); err != nil {
+		panic(err)
+	}
+
+	return grid
+}
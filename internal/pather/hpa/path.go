@@ -0,0 +1,234 @@
+package hpa
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+// FindPath searches the abstract graph and refines only the clusters used by the result, tile by tile with the given
+// algorithm. The grid must have the same size as the one used to build the graph, usually the same grid with the
+// dynamic obstacles on top. Returned path is relative to the grid and the distance is the path length, same as astar.
+func (gr *Graph) FindPath(g *game.Grid, start, goal data.Position, alg astar.Algorithm) ([]data.Position, int, bool) {
+	if g.Width != gr.width || g.Height != gr.height || !walkable(g, start.X, start.Y) || !walkable(g, goal.X, goal.Y) {
+		return nil, 0, false
+	}
+
+	abstractPath, found := gr.abstractPath(g, start, goal)
+	if !found {
+		return nil, 0, false
+	}
+
+	path := []data.Position{start}
+	for i := 1; i < len(abstractPath); i++ {
+		from, to := abstractPath[i-1], abstractPath[i]
+		if from == to {
+			continue
+		}
+
+		// Border crossings and neighbor tiles don't need any search
+		if max(abs(from.X-to.X), abs(from.Y-to.Y)) <= 1 {
+			path = append(path, to)
+			continue
+		}
+
+		b := gr.clusterBounds(gr.clusterOf(from))
+		segment, _, found := astar.Calculate(alg, subGrid(g, b), data.Position{X: from.X - b.x0, Y: from.Y - b.y0}, data.Position{X: to.X - b.x0, Y: to.Y - b.y0})
+		if !found {
+			return nil, 0, false
+		}
+		for _, p := range segment[1:] {
+			path = append(path, data.Position{X: p.X + b.x0, Y: p.Y + b.y0})
+		}
+	}
+
+	return path, len(path), true
+}
+
+// abstractPath returns the list of entrances to go through, start and goal are connected to the entrances of their
+// clusters using the current grid
+func (gr *Graph) abstractPath(g *game.Grid, start, goal data.Position) ([]data.Position, bool) {
+	startID, goalID := int32(len(gr.nodes)), int32(len(gr.nodes)+1)
+	startCluster, goalCluster := gr.clusterOf(start), gr.clusterOf(goal)
+
+	dist := make([]int32, ClusterSize*ClusterSize)
+	pq := make(tileQueue, 0, ClusterSize*ClusterSize)
+
+	startBounds := gr.clusterBounds(startCluster)
+	clusterDistances(g, startBounds, start, dist, &pq)
+	startCosts := make([]int32, len(gr.clusterNodes[startCluster]))
+	for i, n := range gr.clusterNodes[startCluster] {
+		startCosts[i] = dist[startBounds.index(gr.nodes[n].pos)]
+	}
+	direct := int32(math.MaxInt32)
+	if startCluster == goalCluster {
+		direct = dist[startBounds.index(goal)]
+	}
+
+	// Costs are added when entering a tile, so the distances from the goal are shifted to get the ones to the goal
+	goalBounds := gr.clusterBounds(goalCluster)
+	clusterDistances(g, goalBounds, goal, dist, &pq)
+	goalCosts := make(map[int32]int32, len(gr.clusterNodes[goalCluster]))
+	for _, n := range gr.clusterNodes[goalCluster] {
+		p := gr.nodes[n].pos
+		if d := dist[goalBounds.index(p)]; d != math.MaxInt32 {
+			goalCosts[n] = d - int32(astar.TileCost(g.CollisionGrid[p.Y][p.X])) + int32(astar.TileCost(g.CollisionGrid[goal.Y][goal.X]))
+		}
+	}
+
+	position := func(id int32) data.Position {
+		switch id {
+		case startID:
+			return start
+		case goalID:
+			return goal
+		}
+		return gr.nodes[id].pos
+	}
+
+	costSoFar := make([]int32, len(gr.nodes)+2)
+	cameFrom := make([]int32, len(gr.nodes)+2)
+	for i := range costSoFar {
+		costSoFar[i] = math.MaxInt32
+	}
+	costSoFar[startID] = 0
+	cameFrom[startID] = -1
+
+	pq = pq[:0]
+	heap.Push(&pq, queueItem{idx: startID, priority: chebyshev(start, goal)})
+	relax := func(from, to, cost int32) {
+		if cost < costSoFar[to] {
+			costSoFar[to] = cost
+			cameFrom[to] = from
+			heap.Push(&pq, queueItem{idx: to, priority: cost + chebyshev(position(to), goal)})
+		}
+	}
+
+	for pq.Len() > 0 {
+		current := heap.Pop(&pq).(queueItem)
+		if current.priority > costSoFar[current.idx]+chebyshev(position(current.idx), goal) {
+			continue
+		}
+
+		if current.idx == goalID {
+			var positions []data.Position
+			for id := goalID; id >= 0; id = cameFrom[id] {
+				positions = append([]data.Position{position(id)}, positions...)
+			}
+			return positions, true
+		}
+
+		cost := costSoFar[current.idx]
+		if current.idx == startID {
+			for i, n := range gr.clusterNodes[startCluster] {
+				if startCosts[i] != math.MaxInt32 {
+					relax(startID, n, startCosts[i])
+				}
+			}
+			if direct != math.MaxInt32 {
+				relax(startID, goalID, direct)
+			}
+			continue
+		}
+
+		for _, e := range gr.nodes[current.idx].edges {
+			relax(current.idx, e.to, cost+e.cost)
+		}
+		if goalCost, found := goalCosts[current.idx]; found {
+			relax(current.idx, goalID, cost+goalCost)
+		}
+	}
+
+	return nil, false
+}
+
+// clusterDistances fills dist with the cost to reach every tile inside the bounds from the given position
+func clusterDistances(g *game.Grid, b bounds, from data.Position, dist []int32, pq *tileQueue) {
+	width := b.x1 - b.x0
+	dist = dist[:width*(b.y1-b.y0)]
+	for i := range dist {
+		dist[i] = math.MaxInt32
+	}
+
+	*pq = (*pq)[:0]
+	dist[b.index(from)] = 0
+	heap.Push(pq, queueItem{idx: int32(b.index(from)), priority: 0})
+
+	for pq.Len() > 0 {
+		current := heap.Pop(pq).(queueItem)
+		if current.priority > dist[current.idx] {
+			continue
+		}
+
+		x, y := int(current.idx)%width+b.x0, int(current.idx)/width+b.y0
+		for _, d := range directions {
+			nx, ny := x+d.X, y+d.Y
+			if !b.contains(nx, ny) || !walkable(g, nx, ny) {
+				continue
+			}
+
+			nIdx := (ny-b.y0)*width + nx - b.x0
+			if cost := current.priority + int32(astar.TileCost(g.CollisionGrid[ny][nx])); cost < dist[nIdx] {
+				dist[nIdx] = cost
+				heap.Push(pq, queueItem{idx: int32(nIdx), priority: cost})
+			}
+		}
+	}
+}
+
+// subGrid returns the tiles inside the bounds as a grid, rows are shared with the given grid so nothing is copied
+func subGrid(g *game.Grid, b bounds) *game.Grid {
+	rows := make([][]game.CollisionType, b.y1-b.y0)
+	for y := range rows {
+		rows[y] = g.CollisionGrid[b.y0+y][b.x0:b.x1]
+	}
+
+	return &game.Grid{
+		OffsetX:       g.OffsetX + b.x0,
+		OffsetY:       g.OffsetY + b.y0,
+		Width:         b.x1 - b.x0,
+		Height:        b.y1 - b.y0,
+		CollisionGrid: rows,
+	}
+}
+
+func walkable(g *game.Grid, x, y int) bool {
+	return x >= 0 && x < g.Width && y >= 0 && y < g.Height && g.CollisionGrid[y][x] != game.CollisionTypeNonWalkable
+}
+
+func chebyshev(a, b data.Position) int32 {
+	return int32(max(abs(a.X-b.X), abs(a.Y-b.Y)))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+type queueItem struct {
+	idx      int32
+	priority int32
+}
+
+// tileQueue is a min heap storing the items by value, way less allocations than astar.PriorityQueue
+type tileQueue []queueItem
+
+func (q tileQueue) Len() int           { return len(q) }
+func (q tileQueue) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q tileQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *tileQueue) Push(x interface{}) {
+	*q = append(*q, x.(queueItem))
+}
+func (q *tileQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return item
+}
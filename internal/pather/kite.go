@@ -0,0 +1,146 @@
+package pather
+
+import (
+	"math"
+	"slices"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+const (
+	// Tiles further than this from the player, in walking steps, are not considered
+	kiteSearchRadius = 12
+	// Free tiles are counted this far around the candidate, open spaces leave room to keep moving away
+	kiteEscapeRadius = 3
+)
+
+// Scores given to every candidate tile, the highest score wins
+const (
+	kiteMonsterDistanceScore = 10  // per tile between the candidate and the closest monster, up to twice the danger radius
+	kiteInDangerPenalty      = 100 // candidate closer to a monster than the danger radius
+	kiteLineOfSightScore     = 30  // candidate has a clear shot to the target
+	kiteEscapeScore          = 20  // scaled by the share of free tiles around the candidate
+	kiteWalkPenalty          = 2   // per walking step from the player
+)
+
+type safePositionQuery struct {
+	player       data.Position
+	target       data.Position
+	monsters     []data.Position
+	dangerRadius int
+	minDistance  int
+	maxDistance  int
+}
+
+// FindSafePosition returns the tile around the player where a ranged character should stand to keep attacking the
+// target: away from the monsters, with a clear shot to the target, reachable without walking through the monsters
+// and with free space around to keep kiting. It returns false when no tile is better than the current one.
+func (pf *PathFinder) FindSafePosition(target data.Position, monsters []data.Monster, dangerRadius, minDistance, maxDistance int) (data.Position, bool) {
+	positions := make([]data.Position, 0, len(monsters))
+	for _, m := range monsters {
+		positions = append(positions, m.Position)
+	}
+
+	return findSafePosition(pf.data.AreaData.Grid, pf.lineOfSightGrid(), safePositionQuery{
+		player:       pf.data.PlayerUnit.Position,
+		target:       target,
+		monsters:     positions,
+		dangerRadius: dangerRadius,
+		minDistance:  minDistance,
+		maxDistance:  maxDistance,
+	})
+}
+
+// findSafePosition walks the tiles around the player in distance order, positions are absolute
+func findSafePosition(g, losGrid *game.Grid, q safePositionQuery) (data.Position, bool) {
+	if !g.IsWalkable(q.player) {
+		return data.Position{}, false
+	}
+
+	// Small window around the player, the search never leaves it
+	size := kiteSearchRadius*2 + 1
+	origin := data.Position{X: q.player.X - kiteSearchRadius, Y: q.player.Y - kiteSearchRadius}
+	dist := make([]int, size*size)
+	for i := range dist {
+		dist[i] = -1
+	}
+
+	best, bestScore := q.player, math.MinInt
+	startIdx := kiteSearchRadius*size + kiteSearchRadius
+	dist[startIdx] = 0
+	queue := []int{startIdx}
+	for head := 0; head < len(queue); head++ {
+		idx := queue[head]
+		pos := data.Position{X: origin.X + idx%size, Y: origin.Y + idx/size}
+
+		if score, valid := scoreSafePosition(g, losGrid, q, pos, dist[idx]); valid && score > bestScore {
+			best, bestScore = pos, score
+		}
+
+		if dist[idx] >= kiteSearchRadius {
+			continue
+		}
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				next := data.Position{X: pos.X + dx, Y: pos.Y + dy}
+				nIdx := (next.Y-origin.Y)*size + next.X - origin.X
+				if next.X < origin.X || next.Y < origin.Y || next.X >= origin.X+size || next.Y >= origin.Y+size || dist[nIdx] >= 0 {
+					continue
+				}
+				// Monsters block the way, the path can not go through them
+				if !g.IsWalkable(next) || slices.Contains(q.monsters, next) {
+					continue
+				}
+
+				dist[nIdx] = dist[idx] + 1
+				queue = append(queue, nIdx)
+			}
+		}
+	}
+
+	if bestScore == math.MinInt || best == q.player {
+		return data.Position{}, false
+	}
+
+	return best, true
+}
+
+// scoreSafePosition returns false for the tiles the target can not be attacked from
+func scoreSafePosition(g, losGrid *game.Grid, q safePositionQuery, pos data.Position, walkDistance int) (int, bool) {
+	distanceToTarget := DistanceFromPoint(pos, q.target)
+	if distanceToTarget < q.minDistance || distanceToTarget > q.maxDistance {
+		return 0, false
+	}
+
+	closest := closestMonsterDistance(pos, q.monsters)
+	score := min(closest, q.dangerRadius*2)*kiteMonsterDistanceScore - walkDistance*kiteWalkPenalty
+	if closest < q.dangerRadius {
+		score -= kiteInDangerPenalty
+	}
+	if losGrid.LineOfSight(pos, q.target, game.LineOfSightMissile) {
+		score += kiteLineOfSightScore
+	}
+
+	free, total := 0, 0
+	for y := -kiteEscapeRadius; y <= kiteEscapeRadius; y++ {
+		for x := -kiteEscapeRadius; x <= kiteEscapeRadius; x++ {
+			total++
+			if g.IsWalkable(data.Position{X: pos.X + x, Y: pos.Y + y}) {
+				free++
+			}
+		}
+	}
+	score += kiteEscapeScore * free / total
+
+	return score, true
+}
+
+func closestMonsterDistance(pos data.Position, monsters []data.Position) int {
+	closest := math.MaxInt
+	for _, m := range monsters {
+		closest = min(closest, DistanceFromPoint(pos, m))
+	}
+
+	return closest
+}
@@ -0,0 +1,168 @@
+package pather
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+)
+
+// monstersInFixture returns the position of every M in the fixture, parseGridFixture leaves them walkable
+func monstersInFixture(fixture string) []data.Position {
+	var monsters []data.Position
+	for y, row := range strings.Split(strings.TrimSpace(fixture), "\n") {
+		for x, c := range row {
+			if c == 'M' {
+				monsters = append(monsters, data.Position{X: x, Y: y})
+			}
+		}
+	}
+
+	return monsters
+}
+
+func TestFindSafePosition(t *testing.T) {
+	tests := map[string]struct {
+		// S is the player, G the target and M the monsters
+		grid         string
+		dangerRadius int
+		minDistance  int
+		maxDistance  int
+		found        bool
+		check        func(t *testing.T, q safePositionQuery, pos data.Position)
+	}{
+		"move away from the pack": {
+			grid: `
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..........MMM.................
+..........MSM.................
+..........M...................
+..............................
+..............................
+..............................
+.........................G....
+..............................
+..............................`,
+			dangerRadius: 5,
+			maxDistance:  20,
+			found:        true,
+			check: func(t *testing.T, q safePositionQuery, pos data.Position) {
+				if d := closestMonsterDistance(pos, q.monsters); d < q.dangerRadius {
+					t.Errorf("Expected a position outside the danger radius, got %v at %d tiles from a monster", pos, d)
+				}
+			},
+		},
+		"keep line of sight to the target": {
+			grid: `
+..............................
+..............................
+..............................
+...........#######............
+...........#.....#............
+...........#.....#............
+...........#.....#............
+...............S..............
+...............M..............
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+...............G..............`,
+			dangerRadius: 4,
+			maxDistance:  20,
+			found:        true,
+			check: func(t *testing.T, q safePositionQuery, pos data.Position) {
+				if pos.Y < 7 {
+					t.Errorf("Expected a position outside the walled room with line of sight to the target, got %v", pos)
+				}
+			},
+		},
+		"target out of range": {
+			grid: `
+..............................
+..........MMM.................
+..........MSM.................
+..........M...................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+..............................
+.............................G`,
+			dangerRadius: 5,
+			maxDistance:  5,
+			found:        false,
+		},
+		"monsters block the only exit": {
+			grid: `
+###########..........
+#.......SMM..........
+###########..........
+.....................
+.............G.......`,
+			dangerRadius: 5,
+			maxDistance:  30,
+			found:        true,
+			check: func(t *testing.T, q safePositionQuery, pos data.Position) {
+				if pos.Y != 1 || pos.X >= 8 {
+					t.Errorf("Expected a position inside the corridor, monsters can not be walked through, got %v", pos)
+				}
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			grid, player, target := parseGridFixture(tt.grid)
+			q := safePositionQuery{
+				player:       player,
+				target:       target,
+				monsters:     monstersInFixture(tt.grid),
+				dangerRadius: tt.dangerRadius,
+				minDistance:  tt.minDistance,
+				maxDistance:  tt.maxDistance,
+			}
+
+			pos, found := findSafePosition(grid, grid, q)
+			if found != tt.found {
+				t.Fatalf("Expected found %v, got %v (%v)", tt.found, found, pos)
+			}
+			if !found {
+				return
+			}
+
+			if !grid.IsWalkable(pos) {
+				t.Errorf("Expected a walkable position, got %v", pos)
+			}
+			if d := DistanceFromPoint(pos, target); d < tt.minDistance || d > tt.maxDistance {
+				t.Errorf("Expected the target in range, got %d tiles", d)
+			}
+			tt.check(t, q, pos)
+		})
+	}
+}
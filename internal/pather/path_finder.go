@@ -3,6 +3,8 @@ package pather
 import (
 	"fmt"
 	"math"
+	"sync/atomic"
+	"time"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
 	"github.com/hectorgimenez/d2go/pkg/data/area"
@@ -12,63 +14,124 @@ import (
 )
 
 type PathFinder struct {
-	gr   *game.MemoryReader
+	gr   game.GameReader
 	data *game.Data
 	hid  *game.HID
-	cfg  *config.CharacterCfg
+	cfg  atomic.Pointer[config.CharacterCfg]
+
+	hierarchy atomic.Pointer[hierarchy]
+	recorder  pathRecorder
 }
 
-func NewPathFinder(gr *game.MemoryReader, data *game.Data, hid *game.HID, cfg *config.CharacterCfg) *PathFinder {
-	return &PathFinder{
+func NewPathFinder(gr game.GameReader, data *game.Data, hid *game.HID, cfg *config.CharacterCfg) *PathFinder {
+	pf := &PathFinder{
 		gr:   gr,
 		data: data,
 		hid:  hid,
-		cfg:  cfg,
 	}
+	pf.cfg.Store(cfg)
+	pf.hierarchy.Store(newHierarchy())
+
+	return pf
+}
+
+// SetCharacterConfig replaces the config read by the path finder, used when the config is reloaded
+func (pf *PathFinder) SetCharacterConfig(cfg *config.CharacterCfg) {
+	pf.cfg.Store(cfg)
 }
 
-func (pf *PathFinder) GetPath(to data.Position) (Path, int, bool) {
+type PathOpts struct {
+	algorithm *astar.Algorithm
+	smooth    bool
+}
+
+type PathOption func(*PathOpts)
+
+// WithAlgorithm overrides the default A* search, the hierarchical search is skipped. Jump Point Search is only faster
+// on open grids without low priority wall margins, check the astar benchmarks before using it.
+func WithAlgorithm(alg astar.Algorithm) PathOption {
+	return func(opts *PathOpts) {
+		opts.algorithm = &alg
+	}
+}
+
+// WithSmoothing string-pulls the path into straight segments, useful to reduce the amount of movements when walking
+func WithSmoothing() PathOption {
+	return func(opts *PathOpts) {
+		opts.smooth = true
+	}
+}
+
+func (pf *PathFinder) GetPath(to data.Position, options ...PathOption) (Path, int, bool) {
 	// First try direct path
-	if path, distance, found := pf.GetPathFrom(pf.data.PlayerUnit.Position, to); found {
+	if path, distance, found := pf.GetPathFrom(pf.data.PlayerUnit.Position, to, options...); found {
 		return path, distance, true
 	}
 
 	// If direct path fails, try to find nearby walkable position
 	if walkableTo, found := pf.findNearbyWalkablePosition(to); found {
-		return pf.GetPathFrom(pf.data.PlayerUnit.Position, walkableTo)
+		return pf.GetPathFrom(pf.data.PlayerUnit.Position, walkableTo, options...)
 	}
 
 	return nil, 0, false
 }
 
-func (pf *PathFinder) GetPathFrom(from, to data.Position) (Path, int, bool) {
-	a := pf.data.AreaData
+func (pf *PathFinder) GetPathFrom(from, to data.Position, options ...PathOption) (Path, int, bool) {
+	q := &PathQuery{Time: time.Now(), Area: pf.data.AreaData.Area, From: from, To: to}
+	path, distance, found, grid := pf.calculatePath(q, options...)
+	q.Duration = time.Since(q.Time)
+	q.Found, q.Distance = found, distance
 
-	// We don't want to modify the original grid
-	grid := a.Grid.Copy()
+	// Snapshot is taken once the duration is measured, it's not part of the search
+	if grid != nil {
+		q.overlay = encodeOverlay(q.grid, grid)
+	}
+	pf.recorder.record(q)
 
-	// Special handling for Arcane Sanctuary (to allow pathing with platforms)
-	if pf.data.PlayerUnit.Area == area.ArcaneSanctuary && pf.data.CanTeleport() {
-		// Make all non-walkable tiles into low priority tiles for teleport pathing
-		for y := 0; y < len(grid.CollisionGrid); y++ {
-			for x := 0; x < len(grid.CollisionGrid[y]); x++ {
-				if grid.CollisionGrid[y][x] == game.CollisionTypeNonWalkable {
-					grid.CollisionGrid[y][x] = game.CollisionTypeLowPriority
-				}
-			}
-		}
+	return path, distance, found
+}
+
+// calculatePath runs the search described by the query, the static grid and the raw path are stored in it. The grid
+// with the dynamic obstacles is returned so the caller can snapshot it, it's nil if the search didn't run.
+func (pf *PathFinder) calculatePath(q *PathQuery, options ...PathOption) (Path, int, bool, *game.Grid) {
+	opts := &PathOpts{}
+	for _, o := range options {
+		o(opts)
 	}
-	// Lut Gholein map is a bit bugged, we should close this fake path to avoid pathing issues
-	if a.Area == area.LutGholein {
-		a.CollisionGrid[13][210] = game.CollisionTypeNonWalkable
+
+	a := pf.data.AreaData
+	from, to := q.From, q.To
+
+	// Arcane Sanctuary platforms are only connected teleporting, gaps between them are allowed at a higher cost
+	platforms := a.Area == area.ArcaneSanctuary && pf.data.CanTeleport()
+
+	// Different regions can not be connected, no need to search the whole region to find it out
+	if !platforms && a.IsInside(to) && !a.IsReachable(from, to) {
+		q.Reason = "destination is not reachable from the origin region"
+		return nil, 0, false, nil
 	}
 
+	staticGrid := a.Grid
 	if !a.IsInside(to) {
 		expandedGrid, err := pf.mergeGrids(to)
 		if err != nil {
-			return nil, 0, false
+			q.Reason = err.Error()
+			return nil, 0, false, nil
 		}
-		grid = expandedGrid
+		staticGrid = expandedGrid
+	}
+	if platforms {
+		staticGrid = pf.cachedBridgedGrid(staticGrid)
+	}
+	q.grid = staticGrid
+
+	// Dynamic obstacles go to an overlay, cached map data is shared and must not be modified
+	grid := staticGrid.Overlay()
+
+	// Lut Gholein map is a bit bugged, we should close this fake path to avoid pathing issues
+	if a.Area == area.LutGholein {
+		fakePath := grid.RelativePosition(data.Position{X: a.OffsetX + 210, Y: a.OffsetY + 13})
+		grid.Set(fakePath.X, fakePath.Y, game.CollisionTypeNonWalkable)
 	}
 
 	from = grid.RelativePosition(from)
@@ -80,7 +143,7 @@ func (pf *PathFinder) GetPathFrom(from, to data.Position) (Path, int, bool) {
 			continue
 		}
 		relativePos := grid.RelativePosition(o.Position)
-		grid.CollisionGrid[relativePos.Y][relativePos.X] = game.CollisionTypeObject
+		grid.Set(relativePos.X, relativePos.Y, game.CollisionTypeObject)
 		for i := -2; i <= 2; i++ {
 			for j := -2; j <= 2; j++ {
 				if i == 0 && j == 0 {
@@ -90,8 +153,7 @@ func (pf *PathFinder) GetPathFrom(from, to data.Position) (Path, int, bool) {
 					continue
 				}
 				if grid.CollisionGrid[relativePos.Y+i][relativePos.X+j] == game.CollisionTypeWalkable {
-					grid.CollisionGrid[relativePos.Y+i][relativePos.X+j] = game.CollisionTypeLowPriority
-
+					grid.Set(relativePos.X+j, relativePos.Y+i, game.CollisionTypeLowPriority)
 				}
 			}
 		}
@@ -103,53 +165,90 @@ func (pf *PathFinder) GetPathFrom(from, to data.Position) (Path, int, bool) {
 			continue
 		}
 		relativePos := grid.RelativePosition(m.Position)
-		grid.CollisionGrid[relativePos.Y][relativePos.X] = game.CollisionTypeMonster
+		grid.Set(relativePos.X, relativePos.Y, game.CollisionTypeMonster)
 	}
 
-	path, distance, found := astar.CalculatePath(grid, from, to)
+	// Area around dangerous monsters is more expensive, so walking paths go around them when possible
+	if settings, enabled := pf.threatSettings(); enabled {
+		stampThreats(grid, pf.data.Monsters.Enemies(), settings)
+	}
 
-	if config.Koolo.Debug.RenderMap {
-		pf.renderMap(grid, from, to, path)
+	alg := astar.AlgorithmAStar
+	if opts.algorithm != nil {
+		alg = *opts.algorithm
 	}
 
-	return path, distance, found
+	var path []data.Position
+	distance, found := 0, false
+	// Bridged grids are only used teleporting in a single area, not worth an abstract graph
+	if opts.algorithm == nil && !platforms && isLongPath(from, to) {
+		if graph := pf.graphFor(staticGrid); graph != nil {
+			path, distance, found = graph.FindPath(grid, from, to, alg)
+		}
+	}
+	// Abstract graph is not ready yet or the refinement failed because of the dynamic obstacles
+	if !found {
+		path, distance, found = astar.Calculate(alg, grid, from, to)
+	}
+	q.path, q.Cost = path, pathTileCost(grid, path)
+	if found && opts.smooth {
+		path = smoothPath(grid, path)
+		distance = len(path)
+	}
+
+	return path, distance, found, grid
+}
+
+// bridgePlatforms turns the non walkable tiles into low priority ones, used to path between platforms when teleporting.
+// It changes every row, it's only called on a copy of the static grid cached for the whole game.
+func bridgePlatforms(grid *game.Grid) {
+	for y := range grid.CollisionGrid {
+		for x, t := range grid.CollisionGrid[y] {
+			if t == game.CollisionTypeNonWalkable {
+				grid.Set(x, y, game.CollisionTypeLowPriority)
+			}
+		}
+	}
 }
 
+// mergeGrids returns the static grid containing the current area and the adjacent one where the position is, it's
+// shared between calls so it must not be modified
 func (pf *PathFinder) mergeGrids(to data.Position) (*game.Grid, error) {
 	for _, a := range pf.data.AreaData.AdjacentLevels {
 		destination := pf.data.Areas[a.Area]
 		if destination.IsInside(to) {
-			origin := pf.data.AreaData
-
-			endX1 := origin.OffsetX + len(origin.Grid.CollisionGrid[0])
-			endY1 := origin.OffsetY + len(origin.Grid.CollisionGrid)
-			endX2 := destination.OffsetX + len(destination.Grid.CollisionGrid[0])
-			endY2 := destination.OffsetY + len(destination.Grid.CollisionGrid)
-
-			minX := min(origin.OffsetX, destination.OffsetX)
-			minY := min(origin.OffsetY, destination.OffsetY)
-			maxX := max(endX1, endX2)
-			maxY := max(endY1, endY2)
+			return pf.cachedMergedGrid(pf.data.AreaData, destination), nil
+		}
+	}
 
-			width := maxX - minX
-			height := maxY - minY
+	return nil, fmt.Errorf("destination grid not found")
+}
 
-			resultGrid := make([][]game.CollisionType, height)
-			for i := range resultGrid {
-				resultGrid[i] = make([]game.CollisionType, width)
-			}
+// MergeAreaGrids returns a new grid big enough to contain both areas, tiles not covered by any of them are non walkable
+func MergeAreaGrids(origin, destination game.AreaData) *game.Grid {
+	endX1 := origin.OffsetX + len(origin.Grid.CollisionGrid[0])
+	endY1 := origin.OffsetY + len(origin.Grid.CollisionGrid)
+	endX2 := destination.OffsetX + len(destination.Grid.CollisionGrid[0])
+	endY2 := destination.OffsetY + len(destination.Grid.CollisionGrid)
 
-			// Let's copy both grids into the result grid
-			copyGrid(resultGrid, origin.CollisionGrid, origin.OffsetX-minX, origin.OffsetY-minY)
-			copyGrid(resultGrid, destination.CollisionGrid, destination.OffsetX-minX, destination.OffsetY-minY)
+	minX := min(origin.OffsetX, destination.OffsetX)
+	minY := min(origin.OffsetY, destination.OffsetY)
+	maxX := max(endX1, endX2)
+	maxY := max(endY1, endY2)
 
-			grid := game.NewGrid(resultGrid, minX, minY)
+	width := maxX - minX
+	height := maxY - minY
 
-			return grid, nil
-		}
+	resultGrid := make([][]game.CollisionType, height)
+	for i := range resultGrid {
+		resultGrid[i] = make([]game.CollisionType, width)
 	}
 
-	return nil, fmt.Errorf("destination grid not found")
+	// Let's copy both grids into the result grid
+	copyGrid(resultGrid, origin.CollisionGrid, origin.OffsetX-minX, origin.OffsetY-minY)
+	copyGrid(resultGrid, destination.CollisionGrid, destination.OffsetX-minX, destination.OffsetY-minY)
+
+	return game.NewGrid(resultGrid, minX, minY)
 }
 
 func copyGrid(dest [][]game.CollisionType, src [][]game.CollisionType, offsetX, offsetY int) {
@@ -184,10 +283,13 @@ func (pf *PathFinder) GetClosestWalkablePathFrom(from, dest data.Position) (Path
 					cgY := dest.Y - pf.data.AreaOrigin.Y + j
 					cgX := dest.X - pf.data.AreaOrigin.X + i
 					if cgX > 0 && cgY > 0 && a.Height > cgY && a.Width > cgX && a.CollisionGrid[cgY][cgX] == game.CollisionTypeWalkable {
-						return pf.GetPathFrom(from, data.Position{
-							X: dest.X + i,
-							Y: dest.Y + j,
-						})
+						candidate := data.Position{X: dest.X + i, Y: dest.Y + j}
+						// Tiles on a different region would be close to the destination but never reached
+						if !a.IsReachable(from, candidate) {
+							continue
+						}
+
+						return pf.GetPathFrom(from, candidate)
 					}
 				}
 			}
@@ -207,7 +309,7 @@ func (pf *PathFinder) findNearbyWalkablePosition(target data.Position) (data.Pos
 					continue
 				}
 				pos := data.Position{X: target.X + x, Y: target.Y + y}
-				if pf.data.AreaData.IsWalkable(pos) {
+				if pf.data.AreaData.IsReachable(pf.data.PlayerUnit.Position, pos) {
 					return pos, true
 				}
 			}
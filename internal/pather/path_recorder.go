@@ -0,0 +1,168 @@
+package pather
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+// Amount of path queries kept by the recorder, older ones are overwritten
+const pathRecorderSize = 64
+
+// PathQuery is a path search kept by the path recorder, so failed movements can be checked after they happened.
+// Positions are in world coordinates.
+type PathQuery struct {
+	ID       uint64        `json:"id"`
+	Time     time.Time     `json:"time"`
+	Area     area.ID       `json:"area"`
+	From     data.Position `json:"from"`
+	To       data.Position `json:"to"`
+	Found    bool          `json:"found"`
+	Reason   string        `json:"reason,omitempty"`
+	Distance int           `json:"distance"`
+	Cost     int           `json:"cost"`
+	Duration time.Duration `json:"duration"`
+
+	// grid is the static grid the search ran on, it's shared with the path finder and never modified
+	grid *game.Grid
+	// overlay holds the dynamic tiles that differ from grid, see encodeOverlay
+	overlay []byte
+	// path is relative to grid
+	path Path
+}
+
+type pathRecorder struct {
+	mu      sync.Mutex
+	queries [pathRecorderSize]*PathQuery
+	lastID  uint64
+}
+
+func (r *pathRecorder) record(q *PathQuery) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	q.ID = r.lastID
+	r.queries[q.ID%pathRecorderSize] = q
+}
+
+// PathQueries returns the recorded path queries, newest first
+func (pf *PathFinder) PathQueries() []PathQuery {
+	r := &pf.recorder
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	queries := make([]PathQuery, 0, pathRecorderSize)
+	for id := r.lastID; id > 0 && r.lastID-id < pathRecorderSize; id-- {
+		queries = append(queries, *r.queries[id%pathRecorderSize])
+	}
+
+	return queries
+}
+
+// RenderPathQuery draws the grid as it was when the query ran, with the dynamic obstacles and the path found
+func (pf *PathFinder) RenderPathQuery(id uint64) (*image.RGBA, error) {
+	r := &pf.recorder
+	r.mu.Lock()
+	q := r.queries[id%pathRecorderSize]
+	r.mu.Unlock()
+
+	if q == nil || q.ID != id {
+		return nil, fmt.Errorf("path query %d not found", id)
+	}
+	if q.grid == nil {
+		return nil, errors.New("path query has no grid, the search was discarded before running")
+	}
+
+	grid, err := decodeOverlay(q.grid, q.overlay)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding path query overlay: %w", err)
+	}
+
+	return RenderMap(grid, MapRender{
+		Path:  q.path,
+		Route: true,
+		From:  grid.RelativePosition(q.From),
+		To:    grid.RelativePosition(q.To),
+	}), nil
+}
+
+// pathTileCost sums the cost of every tile entered by the path, the starting one is free
+func pathTileCost(grid *game.Grid, path Path) int {
+	cost := 0
+	for i := 1; i < len(path); i++ {
+		cost += astar.TileCost(grid.CollisionGrid[path[i].Y][path[i].X])
+	}
+
+	return cost
+}
+
+// encodeOverlay stores the tiles of the overlay different from its static grid as (x, y, type) varints, deflated.
+// Only the rows copied by the overlay are compared.
+func encodeOverlay(static, overlay *game.Grid) []byte {
+	var raw []byte
+	for _, y := range overlay.OverlayRows() {
+		for x, t := range overlay.CollisionGrid[y] {
+			if t != static.CollisionGrid[y][x] {
+				raw = binary.AppendUvarint(raw, uint64(x))
+				raw = binary.AppendUvarint(raw, uint64(y))
+				raw = append(raw, byte(t))
+			}
+		}
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestSpeed)
+	w.Write(raw)
+	w.Close()
+
+	return buf.Bytes()
+}
+
+func decodeOverlay(static *game.Grid, overlay []byte) (*game.Grid, error) {
+	grid := static.Overlay()
+	if len(overlay) == 0 {
+		return grid, nil
+	}
+
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(overlay)))
+	if err != nil {
+		return nil, err
+	}
+
+	br := bytes.NewReader(raw)
+	for br.Len() > 0 {
+		x, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		y, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		t, err := br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if int(x) >= grid.Width || int(y) >= grid.Height {
+			return nil, fmt.Errorf("tile %d,%d out of bounds", x, y)
+		}
+		grid.Set(int(x), int(y), game.CollisionType(t))
+	}
+
+	return grid, nil
+}
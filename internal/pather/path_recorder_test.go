@@ -0,0 +1,61 @@
+package pather
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+func TestPathRecorderKeepsNewestQueries(t *testing.T) {
+	pf := &PathFinder{}
+	for range pathRecorderSize + 10 {
+		pf.recorder.record(&PathQuery{})
+	}
+
+	queries := pf.PathQueries()
+	if len(queries) != pathRecorderSize {
+		t.Fatalf("Expected %d queries, got %d", pathRecorderSize, len(queries))
+	}
+	if queries[0].ID != pathRecorderSize+10 || queries[len(queries)-1].ID != 11 {
+		t.Errorf("Expected queries from %d to 11, got from %d to %d", pathRecorderSize+10, queries[0].ID, queries[len(queries)-1].ID)
+	}
+
+	if _, err := pf.RenderPathQuery(1); err == nil {
+		t.Errorf("Expected error for an overwritten query")
+	}
+}
+
+func TestPathRecorderOverlaySnapshot(t *testing.T) {
+	static, start, goal := parseGridFixture(`
+S....
+.#...
+....G`)
+	overlay := static.Overlay()
+	overlay.Set(2, 0, game.CollisionTypeMonster)
+	overlay.Set(3, 2, game.CollisionTypeObject)
+
+	decoded, err := decodeOverlay(static, encodeOverlay(static, overlay))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for y := range overlay.CollisionGrid {
+		for x := range overlay.CollisionGrid[y] {
+			if decoded.CollisionGrid[y][x] != overlay.CollisionGrid[y][x] {
+				t.Errorf("Expected tile %d,%d to be %d, got %d", x, y, overlay.CollisionGrid[y][x], decoded.CollisionGrid[y][x])
+			}
+		}
+	}
+	if static.CollisionGrid[0][2] != game.CollisionTypeWalkable {
+		t.Errorf("Expected static grid to be untouched")
+	}
+
+	pf := &PathFinder{}
+	pf.recorder.record(&PathQuery{From: start, To: goal, grid: static, overlay: encodeOverlay(static, overlay), path: Path{start, goal}})
+	img, err := pf.RenderPathQuery(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != static.Width || b.Dy() != static.Height {
+		t.Errorf("Expected %dx%d image, got %v", static.Width, static.Height, b)
+	}
+}
@@ -1,57 +1,95 @@
 package pather
 
 import (
-	"fmt"
 	"image"
 	"image/color"
-	"image/draw"
-	"image/png"
-	"os"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
 	"github.com/hectorgimenez/koolo/internal/game"
 )
 
-func (pf *PathFinder) renderMap(grid *game.Grid, from, to data.Position, path Path) {
-	img := image.NewRGBA(image.Rect(0, 0, grid.Width, grid.Height))
-	draw.Draw(img, img.Bounds(), img, image.Point{}, draw.Over)
+// MapRender is what gets drawn on top of the collision grid. Path, From and To are relative to the grid, as returned
+// by the path finder, everything else uses world positions like AreaData. From and To are only drawn when Route is set,
+// a path was requested even if none was found.
+type MapRender struct {
+	Rooms    []data.Room
+	Exits    []data.Level
+	NPCs     data.NPCs
+	Objects  []data.Object
+	Path     Path
+	Route    bool
+	From, To data.Position
+}
 
-	pathLocs := map[string]bool{}
-	for _, p := range path {
-		pathLocs[fmt.Sprintf("%d,%d", p.X, p.Y)] = true
-	}
+var (
+	colorPath   = color.RGBA{R: 36, G: 255, B: 0, A: 255}    // Green
+	colorRoom   = color.RGBA{R: 204, G: 204, A: 255}         // Dark yellow
+	colorExit   = color.RGBA{G: 206, B: 209, A: 255}         // Turquoise
+	colorNPC    = color.RGBA{R: 255, G: 105, B: 180, A: 255} // Pink
+	colorObject = color.RGBA{R: 139, G: 69, B: 19, A: 255}   // Brown
+	colorFrom   = color.RGBA{R: 158, G: 0, B: 0, A: 255}     // Garnet
+	colorTo     = color.RGBA{R: 0, G: 0, B: 255, A: 255}     // Blue
+)
+
+// RenderMap draws the grid with one pixel per tile
+func RenderMap(grid *game.Grid, m MapRender) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, grid.Width, grid.Height))
 
 	for x := 0; x < grid.Width; x++ {
 		for y := 0; y < grid.Height; y++ {
-			if pathLocs[fmt.Sprintf("%d,%d", x, y)] {
-				img.Set(x, y, color.RGBA{R: 36, G: 255, B: 0, A: 255})
-			} else {
-				switch grid.CollisionGrid[y][x] {
-				case game.CollisionTypeNonWalkable:
-					img.Set(x, y, color.Black)
-				case game.CollisionTypeWalkable:
-					img.Set(x, y, color.White)
-				case game.CollisionTypeLowPriority:
-					img.Set(x, y, color.RGBA{R: 200, G: 200, B: 200, A: 255}) // Gray
-				case game.CollisionTypeMonster:
-					img.Set(x, y, color.RGBA{R: 255, A: 255}) // Red
-				case game.CollisionTypeObject:
-					img.Set(x, y, color.RGBA{R: 160, G: 32, B: 240, A: 255}) // Purple
-				}
+			switch grid.CollisionGrid[y][x] {
+			case game.CollisionTypeNonWalkable:
+				img.Set(x, y, color.Black)
+			case game.CollisionTypeWalkable:
+				img.Set(x, y, color.White)
+			case game.CollisionTypeLowPriority:
+				img.Set(x, y, color.RGBA{R: 200, G: 200, B: 200, A: 255}) // Gray
+			case game.CollisionTypeMonster:
+				img.Set(x, y, color.RGBA{R: 255, A: 255}) // Red
+			case game.CollisionTypeObject:
+				img.Set(x, y, color.RGBA{R: 160, G: 32, B: 240, A: 255}) // Purple
+			case game.CollisionTypeThreatLow, game.CollisionTypeThreatMedium, game.CollisionTypeThreatHigh:
+				img.Set(x, y, color.RGBA{R: 255, G: 165, A: 255}) // Orange
 			}
 		}
 	}
 
-	for _, r := range pf.data.Rooms {
+	for _, p := range m.Path {
+		img.Set(p.X, p.Y, colorPath)
+	}
+
+	for _, r := range m.Rooms {
 		pos := grid.RelativePosition(r.GetCenter())
-		img.Set(pos.X, pos.Y, color.RGBA{R: 204, G: 204, A: 255}) // Dark yellow
+		img.Set(pos.X, pos.Y, colorRoom)
 	}
 
-	img.Set(from.X, from.Y, color.RGBA{R: 158, G: 0, B: 0, A: 255}) // Garnet
+	for _, o := range m.Objects {
+		setMarker(img, grid.RelativePosition(o.Position), colorObject)
+	}
 
-	img.Set(to.X, to.Y, color.RGBA{R: 0, G: 0, B: 255, A: 255}) // Blue
+	for _, n := range m.NPCs {
+		for _, p := range n.Positions {
+			setMarker(img, grid.RelativePosition(p), colorNPC)
+		}
+	}
+
+	for _, e := range m.Exits {
+		setMarker(img, grid.RelativePosition(e.Position), colorExit)
+	}
+
+	if m.Route {
+		setMarker(img, m.From, colorFrom)
+		setMarker(img, m.To, colorTo)
+	}
 
-	outFile, _ := os.Create("cg.png")
-	defer outFile.Close()
-	png.Encode(outFile, img)
+	return img
+}
+
+// setMarker draws a 3x3 square, single pixels are hard to find on big maps
+func setMarker(img *image.RGBA, p data.Position, c color.Color) {
+	for y := p.Y - 1; y <= p.Y+1; y++ {
+		for x := p.X - 1; x <= p.X+1; x++ {
+			img.Set(x, y, c)
+		}
+	}
 }
@@ -0,0 +1,28 @@
+package pather
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+)
+
+func TestRenderMapRoute(t *testing.T) {
+	g := teleportGrid(10, 10, -1, -1)
+
+	img := RenderMap(g, MapRender{})
+	if img.RGBAAt(0, 0) == colorFrom || img.RGBAAt(0, 0) == colorTo {
+		t.Error("expected no route markers when no path was requested")
+	}
+
+	img = RenderMap(g, MapRender{Route: true, From: data.Position{X: 2, Y: 2}, To: data.Position{X: 7, Y: 7}})
+	for _, p := range []data.Position{{X: 1, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 3}} {
+		if img.RGBAAt(p.X, p.Y) != colorFrom {
+			t.Errorf("expected the origin marker at %v", p)
+		}
+	}
+	for _, p := range []data.Position{{X: 6, Y: 6}, {X: 7, Y: 7}, {X: 8, Y: 8}} {
+		if img.RGBAAt(p.X, p.Y) != colorTo {
+			t.Errorf("expected the destination marker at %v", p)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package pather
+
+import (
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+// smoothLookahead is how far along the path smoothPath looks for the end of a segment, trying every remaining point
+// makes smoothing long paths cubic
+const smoothLookahead = 64
+
+// smoothPath string-pulls the path into the fewest straight segments, every segment stays walkable and never goes
+// through tiles more expensive than the ones it replaces. Segments are filled tile by tile, so positions can still
+// be used as distance the same way as the original path.
+func smoothPath(g *game.Grid, p Path) Path {
+	if len(p) < 3 {
+		return p
+	}
+
+	smoothed := Path{p[0]}
+	highest := make([]int, smoothLookahead+1)
+	for anchor := 0; anchor < len(p)-1; {
+		// Most expensive tile of the original path up to every candidate, segments can't go through anything worse
+		last := min(len(p)-1, anchor+smoothLookahead)
+		highest[0] = astar.TileCost(g.CollisionGrid[p[anchor].Y][p[anchor].X])
+		for i := anchor + 1; i <= last; i++ {
+			highest[i-anchor] = max(highest[i-anchor-1], astar.TileCost(g.CollisionGrid[p[i].Y][p[i].X]))
+		}
+
+		// Farthest point we can reach in a straight line, the next one is always reachable
+		next := anchor + 1
+		for candidate := last; candidate > anchor+1; candidate-- {
+			if isStraightWalkable(g, p[anchor], p[candidate], highest[candidate-anchor]) {
+				next = candidate
+				break
+			}
+		}
+
+		smoothed = append(smoothed, line(p[anchor], p[next])[1:]...)
+		anchor = next
+	}
+
+	return smoothed
+}
+
+// isStraightWalkable checks every tile in the line, all of them must be walkable and not more expensive than maxCost
+func isStraightWalkable(g *game.Grid, from, to data.Position, maxCost int) bool {
+	for _, pos := range line(from, to) {
+		if pos.X < 0 || pos.Y < 0 || pos.X >= g.Width || pos.Y >= g.Height {
+			return false
+		}
+
+		tile := g.CollisionGrid[pos.Y][pos.X]
+		if tile == game.CollisionTypeNonWalkable || astar.TileCost(tile) > maxCost {
+			return false
+		}
+	}
+
+	return true
+}
+
+// line returns all the tiles between both positions (both included) using Bresenham's algorithm
+func line(from, to data.Position) []data.Position {
+	dx := abs(to.X - from.X)
+	dy := -abs(to.Y - from.Y)
+	sx, sy := 1, 1
+	if from.X > to.X {
+		sx = -1
+	}
+	if from.Y > to.Y {
+		sy = -1
+	}
+
+	positions := make([]data.Position, 0, max(dx, -dy)+1)
+	err := dx + dy
+	x, y := from.X, from.Y
+	for {
+		positions = append(positions, data.Position{X: x, Y: y})
+		if x == to.X && y == to.Y {
+			return positions
+		}
+
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
@@ -0,0 +1,191 @@
+package pather
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+// Grid fixtures: '#' non walkable, '.' walkable, 'o' low priority, 'S' start, 'G' goal
+var smoothFixtures = map[string]struct {
+	grid        string
+	maxSegments int
+}{
+	"open room": {
+		grid: `
+S.........
+..........
+..........
+..........
+.........G`,
+		maxSegments: 1,
+	},
+	"corner": {
+		grid: `
+S.....####
+......####
+......####
+######....
+######...G`,
+		maxSegments: 3,
+	},
+	"corridor": {
+		grid: `
+S.#.......
+..#.......
+..#..###..
+.....#G...
+.....###..`,
+		maxSegments: 4,
+	},
+	"low priority shortcut": {
+		grid: `
+S.........
+.oooooooo.
+.oooooooo.
+.oooooooo.
+.........G`,
+		maxSegments: 3,
+	},
+}
+
+func TestSmoothPath(t *testing.T) {
+	for name, fixture := range smoothFixtures {
+		t.Run(name, func(t *testing.T) {
+			grid, start, goal := parseGridFixture(fixture.grid)
+			p, _, found := astar.CalculatePath(grid, start, goal)
+			if !found {
+				t.Fatalf("Expected path to be found")
+			}
+			originalCost := pathCost(t, grid, p)
+
+			smoothed := smoothPath(grid, p)
+			if smoothed.From() != start || smoothed.To() != goal {
+				t.Errorf("Expected path from %v to %v, got %v to %v", start, goal, smoothed.From(), smoothed.To())
+			}
+			if cost := pathCost(t, grid, smoothed); cost > originalCost {
+				t.Errorf("Expected smoothed path cost to be at most %d, got %d", originalCost, cost)
+			}
+			if segments := countSegments(smoothed); segments > fixture.maxSegments {
+				t.Errorf("Expected at most %d segments, got %d", fixture.maxSegments, segments)
+			}
+		})
+	}
+}
+
+func TestSmoothPathShortPaths(t *testing.T) {
+	grid, start, _ := parseGridFixture(smoothFixtures["open room"].grid)
+	p := Path{start, {X: start.X + 1, Y: start.Y}}
+
+	if smoothed := smoothPath(grid, p); len(smoothed) != 2 {
+		t.Errorf("Expected path length to be 2, got %d", len(smoothed))
+	}
+}
+
+func TestSmoothPathLongPaths(t *testing.T) {
+	grid := teleportGrid(500, 50, -1, -1)
+	start, goal := data.Position{X: 0, Y: 0}, data.Position{X: 499, Y: 49}
+	p, _, found := astar.CalculatePath(grid, start, goal)
+	if !found {
+		t.Fatalf("Expected path to be found")
+	}
+
+	smoothed := smoothPath(grid, p)
+	if smoothed.From() != start || smoothed.To() != goal {
+		t.Errorf("Expected path from %v to %v, got %v to %v", start, goal, smoothed.From(), smoothed.To())
+	}
+	if cost := pathCost(t, grid, smoothed); cost > pathCost(t, grid, p) {
+		t.Errorf("Expected smoothed path cost to be at most the original one, got %d", cost)
+	}
+	// Segments are cut at the lookahead
+	if segments, limit := countSegments(smoothed), len(p)/smoothLookahead+1; segments > limit {
+		t.Errorf("Expected at most %d segments, got %d", limit, segments)
+	}
+}
+
+func BenchmarkSmoothPath(b *testing.B) {
+	grid := teleportGrid(1000, 1000, -1, -1)
+	p, _, _ := astar.CalculatePath(grid, data.Position{X: 0, Y: 0}, data.Position{X: 999, Y: 500})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		smoothPath(grid, p)
+	}
+}
+
+// pathCost adds the cost of every step and ensures every step moves to an adjacent walkable tile
+func pathCost(t *testing.T, g *game.Grid, p Path) int {
+	cost := 0
+	for i := 1; i < len(p); i++ {
+		dx, dy := abs(p[i].X-p[i-1].X), abs(p[i].Y-p[i-1].Y)
+		if dx > 1 || dy > 1 || dx+dy == 0 {
+			t.Fatalf("Expected adjacent tiles, got %v and %v", p[i-1], p[i])
+		}
+		if g.CollisionGrid[p[i].Y][p[i].X] == game.CollisionTypeNonWalkable {
+			t.Fatalf("Expected walkable tile at %v", p[i])
+		}
+		cost += astar.TileCost(g.CollisionGrid[p[i].Y][p[i].X])
+	}
+
+	return cost
+}
+
+// countSegments returns the fewest straight lines needed to draw the path
+func countSegments(p Path) int {
+	segments := 0
+	for anchor := 0; anchor < len(p)-1; segments++ {
+		next := anchor + 1
+		for candidate := len(p) - 1; candidate > anchor; candidate-- {
+			if samePositions(line(p[anchor], p[candidate]), p[anchor:candidate+1]) {
+				next = candidate
+				break
+			}
+		}
+		anchor = next
+	}
+
+	return segments
+}
+
+func samePositions(a []data.Position, b Path) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseGridFixture(fixture string) (*game.Grid, data.Position, data.Position) {
+	var start, goal data.Position
+	rows := strings.Split(strings.TrimSpace(fixture), "\n")
+	cg := make([][]game.CollisionType, len(rows))
+	for y, row := range rows {
+		cg[y] = make([]game.CollisionType, len(row))
+		for x, c := range row {
+			switch c {
+			case '#':
+				cg[y][x] = game.CollisionTypeNonWalkable
+			case 'o':
+				cg[y][x] = game.CollisionTypeLowPriority
+			case 'S':
+				start = data.Position{X: x, Y: y}
+				cg[y][x] = game.CollisionTypeWalkable
+			case 'G':
+				goal = data.Position{X: x, Y: y}
+				cg[y][x] = game.CollisionTypeWalkable
+			default:
+				cg[y][x] = game.CollisionTypeWalkable
+			}
+		}
+	}
+
+	return &game.Grid{Width: len(cg[0]), Height: len(cg), CollisionGrid: cg}, start, goal
+}
@@ -0,0 +1,203 @@
+package pather
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+const (
+	// teleportMaxDistance is the max distance in tiles for a single hop, the screen area limits it even more
+	teleportMaxDistance = 30
+	// teleportCellSize is the size of the cells the grid is split into, one landing tile is picked per cell
+	teleportCellSize = 3
+)
+
+// GetTeleportPath returns the list of hops to reach the destination, first position is the player position and the
+// rest are the landing tiles. Hops only require the landing tile to be walkable, there is no need of a walkable
+// connection between them. Returned distance is the sum of all the hops length.
+func (pf *PathFinder) GetTeleportPath(to data.Position) (Path, int, bool) {
+	grid := pf.data.AreaData.Grid
+	if !pf.data.AreaData.IsInside(to) {
+		expandedGrid, err := pf.mergeGrids(to)
+		if err != nil {
+			return nil, 0, false
+		}
+		grid = expandedGrid
+	}
+
+	from := grid.RelativePosition(pf.data.PlayerUnit.Position)
+	goal, found := closestWalkableTile(grid, grid.RelativePosition(to))
+	if !found {
+		return nil, 0, false
+	}
+
+	return planTeleportHops(grid, from, goal, teleportMaxDistance, pf.isHopOnScreen)
+}
+
+// TeleportThroughPath teleports to the first hop of a path returned by GetTeleportPath
+func (pf *PathFinder) TeleportThroughPath(p Path) {
+	if len(p) < 2 {
+		return
+	}
+
+	screenX, screenY := pf.gameCoordsToScreenCords(p.From().X, p.From().Y, p[1].X, p[1].Y)
+	pf.MoveCharacter(screenX, screenY)
+}
+
+// isHopOnScreen checks if the tile at the given offset from the player can be clicked, same limits as MoveThroughPath
+func (pf *PathFinder) isHopOnScreen(dx, dy int) bool {
+	screenX, screenY := pf.gameCoordsToScreenCords(0, 0, dx, dy)
+	gameAreaSizeX, gameAreaSizeY := pf.gr.GameAreaSize()
+
+	return screenX >= 0 && screenY >= 0 && screenX <= gameAreaSizeX && screenY <= int(float32(gameAreaSizeY)/1.21)
+}
+
+// planTeleportHops searches the path with the fewest hops, every node is a landing tile picked per cell and edges
+// connect any pair of tiles within maxDistance that are also on screen
+func planTeleportHops(g *game.Grid, from, to data.Position, maxDistance int, onScreen func(dx, dy int) bool) (Path, int, bool) {
+	cellsX := (g.Width + teleportCellSize - 1) / teleportCellSize
+	cellsY := (g.Height + teleportCellSize - 1) / teleportCellSize
+
+	// Landing tile for each cell, lazily calculated, start and goal cells land on the exact tiles
+	landings := make([]data.Position, cellsX*cellsY)
+	landingFound := make([]int8, cellsX*cellsY) // 0 = not calculated, 1 = found, -1 = no walkable tiles
+	hops := make([]int, cellsX*cellsY)
+	cameFrom := make([]int, cellsX*cellsY)
+	for i := range hops {
+		hops[i] = math.MaxInt32
+	}
+
+	cellOf := func(p data.Position) int {
+		return p.Y/teleportCellSize*cellsX + p.X/teleportCellSize
+	}
+	landing := func(cell int) (data.Position, bool) {
+		if landingFound[cell] == 0 {
+			landingFound[cell] = -1
+			if p, found := bestLandingTile(g, cell%cellsX, cell/cellsX); found {
+				landings[cell] = p
+				landingFound[cell] = 1
+			}
+		}
+
+		return landings[cell], landingFound[cell] == 1
+	}
+	canHop := func(a, b data.Position) bool {
+		return DistanceFromPoint(a, b) <= maxDistance && onScreen(b.X-a.X, b.Y-a.Y)
+	}
+	priority := func(p data.Position, hopCount int) int {
+		// Fewer hops first, same amount of hops are sorted by distance to the goal
+		remaining := math.Sqrt(math.Pow(float64(to.X-p.X), 2) + math.Pow(float64(to.Y-p.Y), 2))
+		return (hopCount+int(math.Ceil(remaining/float64(maxDistance))))*g.Width*g.Height + int(remaining)
+	}
+
+	startCell, goalCell := cellOf(from), cellOf(to)
+	if startCell == goalCell {
+		return Path{from, to}, DistanceFromPoint(from, to), true
+	}
+
+	landings[startCell], landingFound[startCell] = from, 1
+	landings[goalCell], landingFound[goalCell] = to, 1
+	hops[startCell] = 0
+	cameFrom[startCell] = -1
+
+	pq := make(astar.PriorityQueue, 0)
+	heap.Init(&pq)
+	heap.Push(&pq, &astar.Node{Position: from, Cost: 0, Priority: priority(from, 0)})
+
+	cellRange := maxDistance/teleportCellSize + 1
+	for pq.Len() > 0 {
+		current := heap.Pop(&pq).(*astar.Node)
+		currentCell := cellOf(current.Position)
+		if current.Cost > hops[currentCell] {
+			continue
+		}
+
+		if currentCell == goalCell {
+			return buildTeleportPath(landings, cameFrom, goalCell)
+		}
+
+		cx, cy := currentCell%cellsX, currentCell/cellsX
+		for y := max(0, cy-cellRange); y <= min(cellsY-1, cy+cellRange); y++ {
+			for x := max(0, cx-cellRange); x <= min(cellsX-1, cx+cellRange); x++ {
+				cell := y*cellsX + x
+				if hops[cell] <= current.Cost+1 {
+					continue
+				}
+
+				p, found := landing(cell)
+				if !found || !canHop(current.Position, p) {
+					continue
+				}
+
+				hops[cell] = current.Cost + 1
+				cameFrom[cell] = currentCell
+				heap.Push(&pq, &astar.Node{Position: p, Cost: current.Cost + 1, Priority: priority(p, current.Cost+1)})
+			}
+		}
+	}
+
+	return nil, 0, false
+}
+
+func buildTeleportPath(landings []data.Position, cameFrom []int, goalCell int) (Path, int, bool) {
+	var path Path
+	for cell := goalCell; cell >= 0; cell = cameFrom[cell] {
+		path = append(Path{landings[cell]}, path...)
+	}
+
+	distance := 0
+	for i := 1; i < len(path); i++ {
+		distance += DistanceFromPoint(path[i-1], path[i])
+	}
+
+	return path, distance, true
+}
+
+// bestLandingTile returns the tile closest to the cell center, tiles away from walls are preferred because the
+// character can land a bit off the clicked tile
+func bestLandingTile(g *game.Grid, cellX, cellY int) (data.Position, bool) {
+	center := data.Position{X: cellX*teleportCellSize + teleportCellSize/2, Y: cellY*teleportCellSize + teleportCellSize/2}
+
+	best := data.Position{}
+	bestScore := math.MaxInt
+	for y := cellY * teleportCellSize; y < min(g.Height, (cellY+1)*teleportCellSize); y++ {
+		for x := cellX * teleportCellSize; x < min(g.Width, (cellX+1)*teleportCellSize); x++ {
+			score := 0
+			switch g.CollisionGrid[y][x] {
+			case game.CollisionTypeNonWalkable:
+				continue
+			case game.CollisionTypeWalkable:
+			default:
+				score += teleportCellSize * teleportCellSize
+			}
+			score += (x-center.X)*(x-center.X) + (y-center.Y)*(y-center.Y)
+
+			if score < bestScore {
+				best = data.Position{X: x, Y: y}
+				bestScore = score
+			}
+		}
+	}
+
+	return best, bestScore != math.MaxInt
+}
+
+// closestWalkableTile returns the given tile if walkable, or the closest walkable one around it
+func closestWalkableTile(g *game.Grid, p data.Position) (data.Position, bool) {
+	for radius := 0; radius <= 3; radius++ {
+		for x := -radius; x <= radius; x++ {
+			for y := -radius; y <= radius; y++ {
+				candidate := data.Position{X: p.X + x, Y: p.Y + y}
+				if candidate.X >= 0 && candidate.Y >= 0 && candidate.X < g.Width && candidate.Y < g.Height && g.CollisionGrid[candidate.Y][candidate.X] != game.CollisionTypeNonWalkable {
+					return candidate, true
+				}
+			}
+		}
+	}
+
+	return data.Position{}, false
+}
@@ -0,0 +1,164 @@
+package pather
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/skill"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+// teleportGrid returns a walkable grid of the given size, tiles inside the given columns are non walkable
+func teleportGrid(width, height int, wallFrom, wallTo int) *game.Grid {
+	cg := make([][]game.CollisionType, height)
+	for y := range cg {
+		cg[y] = make([]game.CollisionType, width)
+		for x := range cg[y] {
+			cg[y][x] = game.CollisionTypeWalkable
+			if x >= wallFrom && x <= wallTo {
+				cg[y][x] = game.CollisionTypeNonWalkable
+			}
+		}
+	}
+
+	return game.NewGrid(cg, 0, 0)
+}
+
+func alwaysOnScreen(int, int) bool { return true }
+
+func checkHops(t *testing.T, path Path, distance int, from, to data.Position, maxDistance int) {
+	t.Helper()
+
+	if path[0] != from || path[len(path)-1] != to {
+		t.Errorf("expected the path to go from %v to %v, got %v", from, to, path)
+	}
+	total := 0
+	for i := 1; i < len(path); i++ {
+		hop := DistanceFromPoint(path[i-1], path[i])
+		if hop > maxDistance {
+			t.Errorf("hop %d from %v to %v is longer than %d", i, path[i-1], path[i], maxDistance)
+		}
+		total += hop
+	}
+	if total != distance {
+		t.Errorf("expected distance %d to be the sum of the hops %d", distance, total)
+	}
+}
+
+func TestPlanTeleportHops(t *testing.T) {
+	tests := map[string]struct {
+		onScreen func(dx, dy int) bool
+		hops     int
+	}{
+		"max distance": {onScreen: alwaysOnScreen, hops: 3},
+		"limited by the screen": {
+			onScreen: func(dx, dy int) bool { return abs(dx) <= 15 && abs(dy) <= 15 },
+			hops:     6,
+		},
+	}
+
+	g := teleportGrid(100, 12, -1, -1)
+	from, to := data.Position{X: 1, Y: 6}, data.Position{X: 91, Y: 6}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path, distance, found := planTeleportHops(g, from, to, 30, tc.onScreen)
+			if !found {
+				t.Fatal("expected a teleport path")
+			}
+			if len(path)-1 != tc.hops {
+				t.Errorf("expected %d hops, got %d: %v", tc.hops, len(path)-1, path)
+			}
+			checkHops(t, path, distance, from, to, 30)
+		})
+	}
+}
+
+func TestPlanTeleportHopsUnreachable(t *testing.T) {
+	// Wall thicker than a hop
+	g := teleportGrid(100, 12, 30, 69)
+	if path, _, found := planTeleportHops(g, data.Position{X: 1, Y: 6}, data.Position{X: 91, Y: 6}, 30, alwaysOnScreen); found {
+		t.Errorf("expected no teleport path, got %v", path)
+	}
+
+	g = teleportGrid(100, 12, -1, -1)
+	if path, _, found := planTeleportHops(g, data.Position{X: 1, Y: 6}, data.Position{X: 91, Y: 6}, 30, func(int, int) bool { return false }); found {
+		t.Errorf("expected no teleport path when no hop is on screen, got %v", path)
+	}
+}
+
+func TestBestLandingTile(t *testing.T) {
+	g := teleportGrid(9, 3, 3, 5)
+	if p, found := bestLandingTile(g, 1, 0); found {
+		t.Errorf("expected no landing tile in a non walkable cell, got %v", p)
+	}
+
+	// Tiles close to the wall are low priority, the walkable one is preferred even if further from the cell center
+	p, found := bestLandingTile(g, 0, 0)
+	if !found || p != (data.Position{X: 0, Y: 1}) {
+		t.Errorf("expected the walkable tile away from the wall, got %v", p)
+	}
+}
+
+func TestClosestWalkableTile(t *testing.T) {
+	g := teleportGrid(20, 5, 5, 15)
+
+	if p, found := closestWalkableTile(g, data.Position{X: 2, Y: 2}); !found || p != (data.Position{X: 2, Y: 2}) {
+		t.Errorf("expected the walkable tile itself, got %v", p)
+	}
+	if p, found := closestWalkableTile(g, data.Position{X: 6, Y: 2}); !found || p.X != 4 {
+		t.Errorf("expected the closest tile out of the wall, got %v", p)
+	}
+	if p, found := closestWalkableTile(g, data.Position{X: 10, Y: 2}); found {
+		t.Errorf("expected no walkable tile close enough, got %v", p)
+	}
+}
+
+// Arcane Sanctuary like layout, two platforms split by a gap that can only be crossed teleporting
+func TestArcaneSanctuaryPlatforms(t *testing.T) {
+	g := teleportGrid(60, 20, 20, 31)
+	g.LabelRegions()
+	from, to := data.Position{X: 5, Y: 10}, data.Position{X: 50, Y: 10}
+	if g.IsReachable(from, to) {
+		t.Fatal("expected the platforms to be disjoint regions")
+	}
+
+	path, distance, found := planTeleportHops(g, from, to, teleportMaxDistance, alwaysOnScreen)
+	if !found {
+		t.Fatal("expected a teleport path over the gap")
+	}
+	checkHops(t, path, distance, from, to, teleportMaxDistance)
+	for _, p := range path {
+		if !g.IsWalkable(p) {
+			t.Errorf("expected every landing tile to be walkable, got %v", p)
+		}
+	}
+
+	pf := NewPathFinder(nil, &game.Data{AreaData: game.AreaData{Area: area.ArcaneSanctuary, Grid: g}}, nil, &config.CharacterCfg{})
+	pf.data.PlayerUnit.Area = area.ArcaneSanctuary
+	pf.data.KeyBindings.Skills[0].SkillID = skill.Teleport
+	if _, _, found = pf.GetPathFrom(from, to); found {
+		t.Error("expected no walking path between the platforms")
+	}
+
+	pf.data.CharacterCfg.Character.UseTeleport = true
+	if _, _, found = pf.GetPathFrom(from, to); !found {
+		t.Error("expected a path between the platforms when teleporting")
+	}
+	if _, _, found = pf.GetPathFrom(to, from); !found {
+		t.Error("expected a path back between the platforms")
+	}
+	if g.CollisionGrid[10][25] != game.CollisionTypeNonWalkable {
+		t.Error("expected the area grid to keep the gap")
+	}
+
+	// The bridged grid is built once, queries only copy the rows they change
+	queries := pf.PathQueries()
+	if queries[0].grid != queries[1].grid || queries[0].grid != pf.cachedBridgedGrid(g) {
+		t.Error("expected the queries to share the cached bridged grid")
+	}
+	if len(queries[0].overlay) > 32 {
+		t.Errorf("expected no overlay changes without monsters, got %d bytes", len(queries[0].overlay))
+	}
+}
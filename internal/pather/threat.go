@@ -0,0 +1,124 @@
+package pather
+
+import (
+	"strings"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/state"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+// Accumulated threat needed for a tile to be considered part of each threat band
+const (
+	threatLowAt    = 2
+	threatMediumAt = 6
+	threatHighAt   = 12
+)
+
+// Offensive auras, monsters standing next to an aura holder get the state too
+var threatAuras = []state.State{
+	state.Conviction, state.Fanaticism, state.Might, state.Concentration, state.Blessedaim,
+	state.Holyfire, state.Holyshock, state.Holywind,
+}
+
+type threatSettings struct {
+	radius int
+	weight int
+}
+
+// classThreatSettings returns the default settings for the class. Melee characters have to get close to the
+// monsters anyway, ranged and leveling characters can not afford walking through a pack.
+func classThreatSettings(class string) threatSettings {
+	switch strings.ToLower(class) {
+	case "berserker", "mosaic", "hammerdin", "foh":
+		return threatSettings{radius: 4, weight: 1}
+	case "sorceress_leveling", "sorceress_leveling_lightning", "paladin":
+		return threatSettings{radius: 10, weight: 4}
+	}
+
+	return threatSettings{radius: 8, weight: 3}
+}
+
+func (pf *PathFinder) threatSettings() (threatSettings, bool) {
+	cfg := pf.cfg.Load()
+	if cfg == nil || !cfg.Character.ThreatAvoidance.Enabled {
+		return threatSettings{}, false
+	}
+
+	settings := classThreatSettings(cfg.Character.Class)
+	if cfg.Character.ThreatAvoidance.Radius > 0 {
+		settings.radius = cfg.Character.ThreatAvoidance.Radius
+	}
+	if cfg.Character.ThreatAvoidance.Weight > 0 {
+		settings.weight = cfg.Character.ThreatAvoidance.Weight
+	}
+
+	return settings, true
+}
+
+// monsterThreat scores how dangerous a monster is. Enchantments (lightning enchanted, cursed...) are not exposed by
+// the game reader, champions and uniques are the only ones carrying them so they get the highest base scores.
+func monsterThreat(m data.Monster) int {
+	threat := 1
+	switch m.Type {
+	case data.MonsterTypeMinion:
+		threat = 2
+	case data.MonsterTypeChampion:
+		threat = 3
+	case data.MonsterTypeUnique, data.MonsterTypeSuperUnique:
+		threat = 4
+	}
+
+	for _, aura := range threatAuras {
+		if m.States.HasState(aura) {
+			threat += 2
+		}
+	}
+
+	if m.IsSealBoss() {
+		threat += 4
+	}
+
+	return threat
+}
+
+// stampThreats adds the threat around the monsters to the grid, threat decreases linearly with the distance and
+// overlapping areas are added up. Tiles are only changed when the threat band is more expensive than the tile itself.
+func stampThreats(g *game.Grid, monsters []data.Monster, settings threatSettings) {
+	threat := make(map[data.Position]int)
+	for _, m := range monsters {
+		score := monsterThreat(m) * settings.weight
+		center := g.RelativePosition(m.Position)
+		for y := -settings.radius; y <= settings.radius; y++ {
+			for x := -settings.radius; x <= settings.radius; x++ {
+				dist := max(abs(x), abs(y))
+				threat[data.Position{X: center.X + x, Y: center.Y + y}] += score * (settings.radius + 1 - dist) / (settings.radius + 1)
+			}
+		}
+	}
+
+	for p, t := range threat {
+		if p.X < 0 || p.Y < 0 || p.X >= g.Width || p.Y >= g.Height || g.CollisionGrid[p.Y][p.X] == game.CollisionTypeNonWalkable {
+			continue
+		}
+
+		band, found := threatBand(t)
+		if found && astar.TileCost(band) > astar.TileCost(g.CollisionGrid[p.Y][p.X]) {
+			g.Set(p.X, p.Y, band)
+		}
+	}
+}
+
+func threatBand(threat int) (game.CollisionType, bool) {
+	switch {
+	case threat >= threatHighAt:
+		return game.CollisionTypeThreatHigh, true
+	case threat >= threatMediumAt:
+		return game.CollisionTypeThreatMedium, true
+	case threat >= threatLowAt:
+		return game.CollisionTypeThreatLow, true
+	}
+
+	return game.CollisionTypeWalkable, false
+}
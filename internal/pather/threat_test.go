@@ -0,0 +1,202 @@
+package pather
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/state"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+func TestThreatSettings(t *testing.T) {
+	tests := map[string]struct {
+		class   string
+		radius  int
+		weight  int
+		enabled bool
+		want    threatSettings
+	}{
+		"disabled":       {class: "sorceress"},
+		"ranged default": {class: "sorceress", enabled: true, want: threatSettings{radius: 8, weight: 3}},
+		"melee":          {class: "Hammerdin", enabled: true, want: threatSettings{radius: 4, weight: 1}},
+		"leveling":       {class: "paladin", enabled: true, want: threatSettings{radius: 10, weight: 4}},
+		"config radius":  {class: "berserker", radius: 6, enabled: true, want: threatSettings{radius: 6, weight: 1}},
+		"config weight":  {class: "sorceress", weight: 5, enabled: true, want: threatSettings{radius: 8, weight: 5}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.CharacterCfg{}
+			cfg.Character.Class = tc.class
+			cfg.Character.ThreatAvoidance.Enabled = tc.enabled
+			cfg.Character.ThreatAvoidance.Radius = tc.radius
+			cfg.Character.ThreatAvoidance.Weight = tc.weight
+
+			settings, enabled := NewPathFinder(nil, &game.Data{}, nil, cfg).threatSettings()
+			if enabled != tc.enabled || settings != tc.want {
+				t.Errorf("expected %+v (enabled %t), got %+v (enabled %t)", tc.want, tc.enabled, settings, enabled)
+			}
+		})
+	}
+}
+
+func TestMonsterThreat(t *testing.T) {
+	tests := map[string]struct {
+		monster data.Monster
+		want    int
+	}{
+		"normal":       {monster: data.Monster{Type: data.MonsterTypeNone}, want: 1},
+		"minion":       {monster: data.Monster{Type: data.MonsterTypeMinion}, want: 2},
+		"champion":     {monster: data.Monster{Type: data.MonsterTypeChampion}, want: 3},
+		"unique":       {monster: data.Monster{Type: data.MonsterTypeUnique}, want: 4},
+		"aura":         {monster: data.Monster{Type: data.MonsterTypeNone, States: state.States{state.Fanaticism}}, want: 3},
+		"unique auras": {monster: data.Monster{Type: data.MonsterTypeUnique, States: state.States{state.Conviction, state.Might}}, want: 8},
+		"not an aura":  {monster: data.Monster{Type: data.MonsterTypeNone, States: state.States{state.Frozenarmor}}, want: 1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := monsterThreat(tc.monster); got != tc.want {
+				t.Errorf("expected threat %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+// threatAt returns the tile types at increasing distances from the center, stamped by a single monster
+func threatAt(m data.Monster, settings threatSettings, distances int) []game.CollisionType {
+	g := teleportGrid(41, 41, -1, -1)
+	m.Position = data.Position{X: 20, Y: 20}
+	stampThreats(g, []data.Monster{m}, settings)
+
+	tiles := make([]game.CollisionType, distances)
+	for d := range tiles {
+		tiles[d] = g.CollisionGrid[20][20+d]
+	}
+
+	return tiles
+}
+
+func TestStampThreatsFalloff(t *testing.T) {
+	champion := data.Monster{Type: data.MonsterTypeChampion}
+	const (
+		walkable = game.CollisionTypeWalkable
+		low      = game.CollisionTypeThreatLow
+		medium   = game.CollisionTypeThreatMedium
+		high     = game.CollisionTypeThreatHigh
+	)
+
+	tests := map[string]struct {
+		settings threatSettings
+		want     []game.CollisionType
+	}{
+		// Champion score 9: 9, 7, 5, 3, 1 from the center to the border
+		"radius 4":          {settings: threatSettings{radius: 4, weight: 3}, want: []game.CollisionType{medium, medium, low, low, walkable, walkable}},
+		"bigger radius":     {settings: threatSettings{radius: 8, weight: 3}, want: []game.CollisionType{medium, medium, medium, medium, low, low, low, low, walkable, walkable}},
+		"bigger weight":     {settings: threatSettings{radius: 4, weight: 4}, want: []game.CollisionType{high, medium, medium, low, low, walkable}},
+		"harmless monsters": {settings: threatSettings{radius: 4, weight: 0}, want: []game.CollisionType{walkable, walkable}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := threatAt(champion, tc.settings, len(tc.want))
+			for d := range tc.want {
+				if got[d] != tc.want[d] {
+					t.Errorf("expected %v from the center to the border, got %v", tc.want, got)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestStampThreatsOverlap(t *testing.T) {
+	g := teleportGrid(30, 10, 20, 20)
+	settings := threatSettings{radius: 4, weight: 1}
+	monsters := []data.Monster{
+		{Type: data.MonsterTypeChampion, Position: data.Position{X: 8, Y: 5}},
+		{Type: data.MonsterTypeChampion, Position: data.Position{X: 12, Y: 5}},
+		{Type: data.MonsterTypeUnique, Position: data.Position{X: 22, Y: 5}},
+	}
+	stampThreats(g, monsters, settings)
+
+	// Each champion alone is only worth 1 two tiles away, together they reach the low band in the middle
+	if g.CollisionGrid[5][10] != game.CollisionTypeThreatLow {
+		t.Errorf("expected overlapping threats to add up, got %v", g.CollisionGrid[5][10])
+	}
+	if g.CollisionGrid[5][20] != game.CollisionTypeNonWalkable {
+		t.Error("expected non walkable tiles to stay non walkable")
+	}
+	// Tiles close to the wall are low priority, threat bands are more expensive so they replace them
+	if g.CollisionGrid[5][21] != game.CollisionTypeThreatLow {
+		t.Errorf("expected the threat band to replace the low priority tile, got %v", g.CollisionGrid[5][21])
+	}
+}
+
+func TestThreatsBendPaths(t *testing.T) {
+	from, to := data.Position{X: 0, Y: 15}, data.Position{X: 60, Y: 15}
+	monster := data.Monster{Type: data.MonsterTypeUnique, Position: data.Position{X: 30, Y: 15}}
+	closest := func(path []data.Position) int {
+		closest := DistanceFromPoint(path[0], monster.Position)
+		for _, p := range path {
+			closest = min(closest, DistanceFromPoint(p, monster.Position))
+		}
+		return closest
+	}
+
+	g := teleportGrid(61, 31, -1, -1)
+	path, _, found := astar.CalculatePath(g, from, to)
+	if !found {
+		t.Fatal("expected a path")
+	}
+	if d := closest(path); d > 1 {
+		t.Fatalf("expected the straight path to go next to the monster, closest distance %d", d)
+	}
+
+	settings := classThreatSettings("sorceress")
+	stampThreats(g, []data.Monster{monster}, settings)
+	path, _, found = astar.CalculatePath(g, from, to)
+	if !found {
+		t.Fatal("expected a path around the threat")
+	}
+	for _, p := range path {
+		if g.CollisionGrid[p.Y][p.X] == game.CollisionTypeThreatHigh {
+			t.Errorf("expected the path to avoid the high threat tiles, got %v", p)
+		}
+	}
+	if d := closest(path); d < settings.radius/2 {
+		t.Errorf("expected the path to bend away from the monster, closest distance %d", d)
+	}
+}
+
+func TestThreatBands(t *testing.T) {
+	tests := []struct {
+		threat int
+		want   game.CollisionType
+		found  bool
+	}{
+		{threat: threatLowAt - 1, want: game.CollisionTypeWalkable},
+		{threat: threatLowAt, want: game.CollisionTypeThreatLow, found: true},
+		{threat: threatMediumAt, want: game.CollisionTypeThreatMedium, found: true},
+		{threat: threatHighAt, want: game.CollisionTypeThreatHigh, found: true},
+	}
+
+	previousCost := astar.TileCost(game.CollisionTypeLowPriority)
+	for _, tc := range tests {
+		band, found := threatBand(tc.threat)
+		if band != tc.want || found != tc.found {
+			t.Errorf("threat %d: expected %v (found %t), got %v (found %t)", tc.threat, tc.want, tc.found, band, found)
+		}
+		if !found {
+			continue
+		}
+
+		// Every band is more expensive than the previous one and than the walkable tiles close to walls
+		if cost := astar.TileCost(band); cost <= previousCost {
+			t.Errorf("expected band %v to cost more than %d, got %d", band, previousCost, cost)
+		}
+		previousCost = astar.TileCost(band)
+	}
+}
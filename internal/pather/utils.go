@@ -12,8 +12,9 @@ import (
 )
 
 func (pf *PathFinder) RandomMovement() {
-	midGameX := pf.gr.GameAreaSizeX / 2
-	midGameY := pf.gr.GameAreaSizeY / 2
+	gameAreaSizeX, gameAreaSizeY := pf.gr.GameAreaSize()
+	midGameX := gameAreaSizeX / 2
+	midGameY := gameAreaSizeY / 2
 	x := midGameX + rand.Intn(midGameX) - (midGameX / 2)
 	y := midGameY + rand.Intn(midGameY) - (midGameY / 2)
 	pf.hid.MovePointer(x, y)
@@ -25,58 +26,12 @@ func (pf *PathFinder) DistanceFromMe(p data.Position) int {
 	return DistanceFromPoint(pf.data.PlayerUnit.Position, p)
 }
 
-func (pf *PathFinder) OptimizeRoomsTraverseOrder() []data.Room {
-	distanceMatrix := make(map[data.Room]map[data.Room]int)
-
-	for _, room1 := range pf.data.Rooms {
-		distanceMatrix[room1] = make(map[data.Room]int)
-		for _, room2 := range pf.data.Rooms {
-			if room1 != room2 {
-				distance := DistanceFromPoint(room1.GetCenter(), room2.GetCenter())
-				distanceMatrix[room1][room2] = distance
-			} else {
-				distanceMatrix[room1][room2] = 0
-			}
-		}
-	}
-
-	currentRoom := data.Room{}
-	for _, r := range pf.data.Rooms {
-		if r.IsInside(pf.data.PlayerUnit.Position) {
-			currentRoom = r
-		}
-	}
-
-	visited := make(map[data.Room]bool)
-	order := []data.Room{currentRoom}
-	visited[currentRoom] = true
-
-	for len(order) < len(pf.data.Rooms) {
-		nextRoom := data.Room{}
-		minDistance := math.MaxInt
-
-		// Find the nearest unvisited room
-		for _, room := range pf.data.Rooms {
-			if !visited[room] && distanceMatrix[currentRoom][room] < minDistance {
-				nextRoom = room
-				minDistance = distanceMatrix[currentRoom][room]
-			}
-		}
-
-		// Add the next room to the order of visit
-		order = append(order, nextRoom)
-		visited[nextRoom] = true
-		currentRoom = nextRoom
-	}
-
-	return order
-}
-
 func (pf *PathFinder) MoveThroughPath(p Path, walkDuration time.Duration) {
 	// Calculate the max distance we can walk in the given duration
 	maxDistance := int(float64(25) * walkDuration.Seconds())
 
 	// Let's try to calculate how close to the window border we can go
+	gameAreaSizeX, gameAreaSizeY := pf.gr.GameAreaSize()
 	screenCords := data.Position{}
 	for distance, pos := range p {
 		screenX, screenY := pf.gameCoordsToScreenCords(p.From().X, p.From().Y, pos.X, pos.Y)
@@ -87,12 +42,12 @@ func (pf *PathFinder) MoveThroughPath(p Path, walkDuration time.Duration) {
 		}
 
 		// Prevent mouse overlap the HUD
-		if screenY > int(float32(pf.gr.GameAreaSizeY)/1.21) {
+		if screenY > int(float32(gameAreaSizeY)/1.21) {
 			break
 		}
 
 		// We are getting out of the window, let's stop
-		if screenX < 0 || screenY < 0 || screenX > pf.gr.GameAreaSizeX || screenY > pf.gr.GameAreaSizeY {
+		if screenX < 0 || screenY < 0 || screenX > gameAreaSizeX || screenY > gameAreaSizeY {
 			break
 		}
 		screenCords = data.Position{X: screenX, Y: screenY}
@@ -122,8 +77,9 @@ func (pf *PathFinder) gameCoordsToScreenCords(playerX, playerY, destinationX, de
 
 	// Transform cartesian movement (World) to isometric (screen)
 	// Helpful documentation: https://clintbellanger.net/articles/isometric_math/
-	screenX := int((float32(diffX-diffY) * 19.8) + float32(pf.gr.GameAreaSizeX/2))
-	screenY := int((float32(diffX+diffY) * 9.9) + float32(pf.gr.GameAreaSizeY/2))
+	gameAreaSizeX, gameAreaSizeY := pf.gr.GameAreaSize()
+	screenX := int((float32(diffX-diffY) * 19.8) + float32(gameAreaSizeX/2))
+	screenY := int((float32(diffX+diffY) * 9.9) + float32(gameAreaSizeY/2))
 
 	return screenX, screenY
 }
@@ -144,72 +100,62 @@ func DistanceFromPoint(from data.Position, to data.Position) int {
 	return int(math.Sqrt(first + second))
 }
 
+// LineOfSight checks if a missile can reach the destination
 func (pf *PathFinder) LineOfSight(origin data.Position, destination data.Position) bool {
-	// Pre-calculate door collision boxes
-	var doorBoxes []struct {
-		minX, maxX, minY, maxY int
-	}
+	return pf.lineOfSightGrid().LineOfSight(origin, destination, game.LineOfSightMissile)
+}
+
+// lineOfSightGrid returns the current area grid with the closed doors as walls, doors are not part of the map data
+func (pf *PathFinder) lineOfSightGrid() *game.Grid {
+	grid := pf.data.AreaData.Grid
 	for _, obj := range pf.data.Objects {
-		if obj.IsDoor() && obj.Selectable {
-			desc := obj.Desc()
-			halfSizeX := desc.SizeX / 2
-			halfSizeY := desc.SizeY / 2
-			doorX := obj.Position.X + desc.Xoffset
-			doorY := obj.Position.Y + desc.Yoffset
-
-			doorBoxes = append(doorBoxes, struct {
-				minX, maxX, minY, maxY int
-			}{
-				minX: doorX - halfSizeX,
-				maxX: doorX + halfSizeX,
-				minY: doorY - halfSizeY,
-				maxY: doorY + halfSizeY,
-			})
+		if !obj.IsDoor() || !obj.Selectable {
+			continue
 		}
-	}
 
-	dx := int(math.Abs(float64(destination.X - origin.X)))
-	dy := int(math.Abs(float64(destination.Y - origin.Y)))
-	sx, sy := 1, 1
+		if grid == pf.data.AreaData.Grid {
+			grid = grid.Overlay()
+		}
 
-	if origin.X > destination.X {
-		sx = -1
-	}
-	if origin.Y > destination.Y {
-		sy = -1
+		desc := obj.Desc()
+		center := grid.RelativePosition(data.Position{X: obj.Position.X + desc.Xoffset, Y: obj.Position.Y + desc.Yoffset})
+		for y := center.Y - desc.SizeY/2; y <= center.Y+desc.SizeY/2; y++ {
+			for x := center.X - desc.SizeX/2; x <= center.X+desc.SizeX/2; x++ {
+				if x >= 0 && y >= 0 && x < grid.Width && y < grid.Height {
+					grid.Set(x, y, game.CollisionTypeNonWalkable)
+				}
+			}
+		}
 	}
 
-	err := dx - dy
-	x, y := origin.X, origin.Y
+	return grid
+}
 
-	for {
-		if !pf.data.AreaData.Grid.IsWalkable(data.Position{X: x, Y: y}) {
-			return false
-		}
-		if x == destination.X && y == destination.Y {
-			break
-		}
+// FindClearShotPosition returns the reachable position closest to the player with a clear shot to the target, at a
+// distance between minDistance and maxDistance from it
+func (pf *PathFinder) FindClearShotPosition(target data.Position, minDistance, maxDistance int) (data.Position, bool) {
+	grid := pf.lineOfSightGrid()
+	best, bestDistance := data.Position{}, math.MaxInt
+	for y := -maxDistance; y <= maxDistance; y++ {
+		for x := -maxDistance; x <= maxDistance; x++ {
+			candidate := data.Position{X: target.X + x, Y: target.Y + y}
+			distanceToTarget := DistanceFromPoint(target, candidate)
+			if distanceToTarget < minDistance || distanceToTarget > maxDistance {
+				continue
+			}
 
-		// Check pre-calculated door boxes
-		for _, box := range doorBoxes {
-			if x >= box.minX && x <= box.maxX &&
-				y >= box.minY && y <= box.maxY {
-				return false
+			distance := pf.DistanceFromMe(candidate)
+			if distance >= bestDistance || !pf.data.AreaData.IsReachable(pf.data.PlayerUnit.Position, candidate) {
+				continue
 			}
-		}
 
-		e2 := 2 * err
-		if e2 > -dy {
-			err -= dy
-			x += sx
-		}
-		if e2 < dx {
-			err += dx
-			y += sy
+			if grid.LineOfSight(candidate, target, game.LineOfSightMissile) {
+				best, bestDistance = candidate, distance
+			}
 		}
 	}
 
-	return true
+	return best, bestDistance != math.MaxInt
 }
 
 // BeyondPosition calculates a new position that is a specified distance beyond the target position when viewed from the start position
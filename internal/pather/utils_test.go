@@ -0,0 +1,62 @@
+package pather
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/object"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+func clearShotFinder(g *game.Grid, player data.Position, objects ...data.Object) *PathFinder {
+	g.LabelRegions()
+	d := &game.Data{AreaData: game.AreaData{Area: area.BloodMoor, Grid: g}}
+	d.PlayerUnit.Position = player
+	d.Objects = objects
+
+	return NewPathFinder(nil, d, nil, &config.CharacterCfg{})
+}
+
+func TestFindClearShotPosition(t *testing.T) {
+	player, target := data.Position{X: 5, Y: 5}, data.Position{X: 25, Y: 5}
+
+	// Wall between the player and the target with a gap at the bottom, the shot has to be taken from the other side
+	g := teleportGrid(30, 15, 15, 15)
+	for y := 11; y < g.Height; y++ {
+		g.CollisionGrid[y][15] = game.CollisionTypeWalkable
+	}
+	p, found := clearShotFinder(g, player).FindClearShotPosition(target, 3, 10)
+	if !found || p.X != 16 || DistanceFromPoint(player, p) != 11 {
+		t.Errorf("expected the closest position behind the wall, got %v (found %t)", p, found)
+	}
+
+	// Open room, the closest position is at the max distance from the target
+	p, found = clearShotFinder(teleportGrid(30, 15, -1, -1), player).FindClearShotPosition(target, 3, 10)
+	if !found || DistanceFromPoint(target, p) != 10 || DistanceFromPoint(player, p) != 10 {
+		t.Errorf("expected the closest position in range, got %v (found %t)", p, found)
+	}
+}
+
+func TestFindClearShotPositionBlocked(t *testing.T) {
+	player, target := data.Position{X: 5, Y: 5}, data.Position{X: 25, Y: 5}
+
+	// Positions behind the wall have a clear shot but can't be reached
+	if p, found := clearShotFinder(teleportGrid(30, 15, 15, 15), player).FindClearShotPosition(target, 3, 10); found {
+		t.Errorf("expected no reachable position, got %v", p)
+	}
+
+	// Closed doors block missiles even if they are not part of the map data, the door is drawn 4 tiles below its position
+	door := data.Object{Name: object.DoorWoodenLeft, Selectable: true, Position: data.Position{X: 20, Y: 1}}
+	g := teleportGrid(30, 15, 20, 20)
+	for y := 4; y <= 6; y++ {
+		g.CollisionGrid[y][20] = game.CollisionTypeWalkable
+	}
+	if p, found := clearShotFinder(g, player).FindClearShotPosition(target, 3, 10); !found || p.X >= 20 {
+		t.Errorf("expected a shot through the doorway, got %v (found %t)", p, found)
+	}
+	if p, found := clearShotFinder(g, player, door).FindClearShotPosition(target, 3, 10); !found || p.X < 20 {
+		t.Errorf("expected the closed door to block the shots from behind it, got %v (found %t)", p, found)
+	}
+}
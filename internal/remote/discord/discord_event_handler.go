@@ -14,7 +14,7 @@ func (b *Bot) Handle(_ context.Context, e event.Event) error {
 	if b.shouldPublish(e) {
 
 		switch e.(type) {
-		case event.GameCreatedEvent, event.GameFinishedEvent, event.RunStartedEvent, event.RunFinishedEvent:
+		case event.GameCreatedEvent, event.GameFinishedEvent, event.RunStartedEvent, event.RunFinishedEvent, event.ClientHangEvent:
 			_, err := b.discordSession.ChannelMessageSend(b.channelID, e.Message())
 			return err
 		default:
@@ -55,6 +55,8 @@ func (b *Bot) shouldPublish(e event.Event) bool {
 		return config.Koolo.Discord.EnableNewRunMessages
 	case event.RunFinishedEvent:
 		return config.Koolo.Discord.EnableRunFinishMessages
+	case event.ClientHangEvent:
+		return true
 	default:
 		break
 	}
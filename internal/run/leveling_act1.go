@@ -12,7 +12,6 @@ import (
 	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/lxn/win"
 )
 
 func (a Leveling) act1() error {
@@ -91,7 +90,7 @@ func (a Leveling) denOfEvil() error {
 	action.ClearCurrentLevel(false, data.MonsterAnyFilter())
 	action.ReturnTown()
 	action.InteractNPC(npc.Akara)
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	return nil
 }
@@ -154,7 +153,7 @@ func (a Leveling) deckardCain() error {
 	action.ItemPickup(0)
 	action.ReturnTown()
 	action.InteractNPC(npc.Akara)
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	//Reuse Tristram Run actions
 	err = Tristram{}.Run()
@@ -213,18 +212,15 @@ func (a Leveling) andariel() error {
 		if x > 3 {
 			a.ctx.HID.Click(game.LeftButton, pos.X, pos.Y)
 			utils.Sleep(300)
-			if a.ctx.Data.LegacyGraphics {
-				a.ctx.HID.Click(game.LeftButton, ui.MercAvatarPositionXClassic, ui.MercAvatarPositionYClassic)
-			} else {
-				a.ctx.HID.Click(game.LeftButton, ui.MercAvatarPositionX, ui.MercAvatarPositionY)
-			}
+			mercAvatar := ui.CurrentLayout().MercAvatar
+			a.ctx.HID.Click(game.LeftButton, mercAvatar.X, mercAvatar.Y)
 		} else {
 			a.ctx.HID.Click(game.RightButton, pos.X, pos.Y)
 		}
 		x++
 	}
 
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	action.UsePortalInTown()
 	action.Buff()
@@ -235,7 +231,7 @@ func (a Leveling) andariel() error {
 	a.ctx.Char.KillAndariel()
 	action.ReturnTown()
 	action.InteractNPC(npc.Warriv)
-	a.ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_RETURN)
+	a.ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyEnter)
 
 	return nil
 }
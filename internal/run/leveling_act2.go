@@ -12,7 +12,6 @@ import (
 	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/lxn/win"
 )
 
 func (a Leveling) act2() error {
@@ -275,7 +274,7 @@ func (a Leveling) summoner() error {
 		return err
 	}
 
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	return nil
 }
@@ -302,7 +301,7 @@ func (a Leveling) prepareStaff() error {
 			screenPos := ui.GetScreenCoordsForItem(horadricStaff)
 			a.ctx.HID.ClickWithModifier(game.LeftButton, screenPos.X, screenPos.Y, game.CtrlKey)
 			utils.Sleep(300)
-			a.ctx.HID.PressKey(win.VK_ESCAPE)
+			a.ctx.HID.PressKey(game.KeyEscape)
 
 			return nil
 		}
@@ -386,9 +385,10 @@ func (a Leveling) duriel(staffAlreadyUsed bool, d game.Data) error {
 
 		a.ctx.HID.Click(game.LeftButton, screenPos.X, screenPos.Y)
 		utils.Sleep(300)
-		a.ctx.HID.Click(game.LeftButton, ui.AnvilCenterX, ui.AnvilCenterY)
+		layout := ui.CurrentLayout()
+		a.ctx.HID.Click(game.LeftButton, layout.AnvilCenter.X, layout.AnvilCenter.Y)
 		utils.Sleep(500)
-		a.ctx.HID.Click(game.LeftButton, ui.AnvilBtnX, ui.AnvilBtnY)
+		a.ctx.HID.Click(game.LeftButton, layout.AnvilButton.X, layout.AnvilButton.Y)
 		utils.Sleep(20000)
 	}
 
@@ -420,18 +420,15 @@ func (a Leveling) duriel(staffAlreadyUsed bool, d game.Data) error {
 		if x > 3 {
 			a.ctx.HID.Click(game.LeftButton, pos.X, pos.Y)
 			utils.Sleep(300)
-			if d.LegacyGraphics {
-				a.ctx.HID.Click(game.LeftButton, ui.MercAvatarPositionXClassic, ui.MercAvatarPositionYClassic)
-			} else {
-				a.ctx.HID.Click(game.LeftButton, ui.MercAvatarPositionX, ui.MercAvatarPositionY)
-			}
+			mercAvatar := ui.CurrentLayout().MercAvatar
+			a.ctx.HID.Click(game.LeftButton, mercAvatar.X, mercAvatar.Y)
 		} else {
 			a.ctx.HID.Click(game.RightButton, pos.X, pos.Y)
 		}
 		x++
 	}
 
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	action.UsePortalInTown()
 	action.Buff()
@@ -451,7 +448,7 @@ func (a Leveling) duriel(staffAlreadyUsed bool, d game.Data) error {
 	})
 
 	action.InteractNPC(npc.Tyrael)
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	action.ReturnTown()
 	action.MoveToCoords(data.Position{
@@ -460,14 +457,14 @@ func (a Leveling) duriel(staffAlreadyUsed bool, d game.Data) error {
 	})
 
 	action.InteractNPC(npc.Jerhyn)
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	action.MoveToCoords(data.Position{
 		X: 5195,
 		Y: 5060,
 	})
 	action.InteractNPC(npc.Meshif)
-	a.ctx.HID.KeySequence(win.VK_HOME, win.VK_DOWN, win.VK_RETURN)
+	a.ctx.HID.KeySequence(game.KeyHome, game.KeyDown, game.KeyEnter)
 
 	return nil
 }
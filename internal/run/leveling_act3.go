@@ -9,7 +9,6 @@ import (
 	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/lxn/win"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
 	"github.com/hectorgimenez/d2go/pkg/data/area"
@@ -316,7 +315,7 @@ func (a Leveling) openMephistoStairs() error {
 	screenPos := ui.GetScreenCoordsForItem(khalimsWill)
 	a.ctx.HID.ClickWithModifier(game.LeftButton, screenPos.X, screenPos.Y, game.ShiftKey)
 	utils.Sleep(300)
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	// Interact with the Compelling Orb to open the stairs
 	compellingorb, found := a.ctx.Data.Objects.FindOne(object.CompellingOrb)
@@ -15,7 +15,6 @@ import (
 	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/lxn/win"
 )
 
 func (a Leveling) act5() error {
@@ -140,13 +139,13 @@ func (a Leveling) anya() error {
 		return err
 	}
 
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 	a.ctx.HID.PressKeyBinding(a.ctx.Data.KeyBindings.Inventory)
 	itm, _ := a.ctx.Data.Inventory.Find("ScrollOfResistance")
 	screenPos := ui.GetScreenCoordsForItem(itm)
 	utils.Sleep(200)
 	a.ctx.HID.Click(game.RightButton, screenPos.X, screenPos.Y)
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	return nil
 }
@@ -15,7 +15,6 @@ import (
 	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/lxn/win"
 )
 
 type Quests struct {
@@ -103,7 +102,7 @@ func (a Quests) clearDenQuest() error {
 		return err
 	}
 
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	return nil
 }
@@ -165,7 +164,7 @@ func (a Quests) rescueCainQuest() error {
 		return err
 	}
 
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	//Reuse Tristram Run actions
 	err = Tristram{}.Run()
@@ -233,7 +232,7 @@ func (a Quests) retrieveHammerQuest() error {
 		return err
 	}
 
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	return nil
 }
@@ -311,13 +310,13 @@ func (a Quests) killRadamentQuest() error {
 		return err
 	}
 
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 	a.ctx.HID.PressKeyBinding(a.ctx.Data.KeyBindings.Inventory)
 	itm, _ := a.ctx.Data.Inventory.Find("BookofSkill")
 	screenPos := ui.GetScreenCoordsForItem(itm)
 	utils.Sleep(200)
 	a.ctx.HID.Click(game.RightButton, screenPos.X, screenPos.Y)
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	return nil
 }
@@ -444,7 +443,7 @@ func (a Quests) retrieveBookQuest() error {
 		return err
 	}
 
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	return nil
 }
@@ -536,7 +535,7 @@ func (a Quests) killShenkQuest() error {
 		return err
 	}
 
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	return nil
 }
@@ -622,13 +621,13 @@ func (a Quests) rescueAnyaQuest() error {
 		return err
 	}
 
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 	a.ctx.HID.PressKeyBinding(a.ctx.Data.KeyBindings.Inventory)
 	itm, _ := a.ctx.Data.Inventory.Find("ScrollOfResistance")
 	screenPos := ui.GetScreenCoordsForItem(itm)
 	utils.Sleep(200)
 	a.ctx.HID.Click(game.RightButton, screenPos.X, screenPos.Y)
-	a.ctx.HID.PressKey(win.VK_ESCAPE)
+	a.ctx.HID.PressKey(game.KeyEscape)
 
 	return nil
 }
@@ -667,7 +666,7 @@ func (a Quests) killAncientsQuest() error {
 	utils.Sleep(1000)
 	a.ctx.HID.Click(game.LeftButton, 720, 260)
 	utils.Sleep(1000)
-	a.ctx.HID.PressKey(win.VK_RETURN)
+	a.ctx.HID.PressKey(game.KeyEnter)
 	utils.Sleep(2000)
 
 	action.ClearAreaAroundPlayer(50, data.MonsterEliteFilter())
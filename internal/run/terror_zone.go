@@ -81,7 +81,7 @@ func (tz TerrorZone) Run() error {
 
 		for k, tzArea := range tzAreaGroup {
 			if k == 0 {
-				err := action.WayPoint(tzArea)
+				err := action.MoveToAreaRoute(tzArea)
 				if err != nil {
 					return err
 				}
@@ -94,7 +94,7 @@ func (tz TerrorZone) Run() error {
 			if slices.Contains(availableTzs, tzArea) {
 				action.ClearCurrentLevel(tz.ctx.CharacterCfg.Game.TerrorZone.OpenChests, tz.customTZEnemyFilter())
 			} else {
-				tz.ctx.Logger.Debug("Skipping area %v", tzArea.Area().Name)
+				tz.ctx.Logger.Debug("Skipping area " + tzArea.Area().Name)
 			}
 		}
 	}
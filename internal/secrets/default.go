@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// PassphraseEnv is read at startup to unlock the store when no key file is configured
+const PassphraseEnv = "KOOLO_SECRETS_PASSPHRASE"
+
+var (
+	mu           sync.RWMutex
+	current      *Store
+	unlocked     = make(chan struct{})
+	unlockedOnce sync.Once
+)
+
+// Unlock opens the store used to resolve config references, it replaces the store unlocked before
+func Unlock(path string, passphrase []byte) error {
+	s, err := Open(path, passphrase)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	current = s
+	mu.Unlock()
+	unlockedOnce.Do(func() { close(unlocked) })
+
+	return nil
+}
+
+// UnlockWithKeyFile unlocks the store with the content of a key file, surrounding whitespace is ignored so files
+// edited by hand keep working
+func UnlockWithKeyFile(path, keyFile string) error {
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("error reading secrets key file: %w", err)
+	}
+
+	return Unlock(path, bytes.TrimSpace(key))
+}
+
+// Unlocked is closed the first time the store is unlocked, for the values that can't be resolved at startup
+func Unlocked() <-chan struct{} {
+	return unlocked
+}
+
+// Current returns the unlocked store, ErrLocked until Unlock succeeds
+func Current() (*Store, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if current == nil {
+		return nil, ErrLocked
+	}
+
+	return current, nil
+}
+
+// Resolve returns the secret value for "secret://{name}" references, any other value is returned as it is
+func Resolve(value string) (string, error) {
+	name, isRef, err := ParseRef(value)
+	if err != nil || !isRef {
+		return value, err
+	}
+
+	s, err := Current()
+	if err != nil {
+		return "", fmt.Errorf("can't read %s: %w", value, err)
+	}
+
+	return s.Get(name)
+}
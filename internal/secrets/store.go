@@ -0,0 +1,312 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// RefPrefix marks config values that are read from the secrets store, "secret://account1" is replaced by the value
+// of the account1 secret when the config is used
+const RefPrefix = "secret://"
+
+const (
+	fileVersion = 1
+	keyLength   = 32
+	saltLength  = 16
+)
+
+var (
+	ErrLocked        = errors.New("secrets store is locked")
+	ErrNotFound      = errors.New("secret not found")
+	ErrBadPassphrase = errors.New("wrong passphrase or corrupted secrets file")
+
+	validName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// kdfParams are the argon2id parameters used to derive the key from the passphrase, they are saved in the file so
+// they can be raised later without breaking existing stores
+type kdfParams struct {
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+var defaultKDF = kdfParams{Time: 1, Memory: 64 * 1024, Threads: 4}
+
+// storeFile is the JSON document written to disk, only the KDF parameters and the salt are readable without the key
+type storeFile struct {
+	Version int       `json:"version"`
+	KDF     kdfParams `json:"kdf"`
+	Salt    []byte    `json:"salt"`
+	Nonce   []byte    `json:"nonce"`
+	Data    []byte    `json:"data"`
+}
+
+// Store keeps named secrets encrypted at rest with AES-256-GCM, the key is derived from a master passphrase or the
+// content of a key file. Values are only kept decrypted in memory.
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	kdf     kdfParams
+	salt    []byte
+	key     []byte
+	secrets map[string]string
+}
+
+// Open decrypts the store at path, a new empty store is created if the file doesn't exist yet
+func Open(path string, passphrase []byte) (*Store, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("the secrets passphrase can't be empty")
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		s := &Store{path: path, kdf: defaultKDF, secrets: make(map[string]string)}
+		if err = s.setPassphrase(passphrase); err != nil {
+			return nil, err
+		}
+		return s, s.save()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f := storeFile{}
+	if err = json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("error reading secrets file %s: %w", path, err)
+	}
+	if f.Version != fileVersion {
+		return nil, fmt.Errorf("unsupported secrets file version %d", f.Version)
+	}
+	if f.KDF.Time == 0 || f.KDF.Memory == 0 || f.KDF.Threads == 0 {
+		return nil, fmt.Errorf("invalid key derivation parameters in secrets file %s", path)
+	}
+
+	s := &Store{path: path, kdf: f.KDF, salt: f.Salt, key: deriveKey(passphrase, f.Salt, f.KDF)}
+	plain, err := s.decrypt(f.Nonce, f.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(plain, &s.secrets); err != nil {
+		return nil, fmt.Errorf("error reading secrets: %w", err)
+	}
+	if s.secrets == nil {
+		s.secrets = make(map[string]string)
+	}
+
+	return s, nil
+}
+
+// Get returns the value of the named secret
+func (s *Store) Get(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, found := s.secrets[name]
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	return value, nil
+}
+
+// Set adds or replaces the named secret and saves the store
+func (s *Store) Set(name, value string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q, only letters, digits, '.', '_' and '-' are allowed", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.secrets[name]
+	s.secrets[name] = value
+	if err := s.save(); err != nil {
+		if existed {
+			s.secrets[name] = previous
+		} else {
+			delete(s.secrets, name)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes the named secret and saves the store
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, found := s.secrets[name]
+	if !found {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	delete(s.secrets, name)
+	if err := s.save(); err != nil {
+		s.secrets[name] = value
+		return err
+	}
+
+	return nil
+}
+
+// Names returns the sorted names of the stored secrets, values are never listed
+func (s *Store) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.secrets))
+	for name := range s.secrets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Rotate re-encrypts every secret with a key derived from the new passphrase and a new salt, the current passphrase
+// must match the one the store was unlocked with
+func (s *Store) Rotate(current, passphrase []byte) error {
+	if len(passphrase) == 0 {
+		return errors.New("the secrets passphrase can't be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if subtle.ConstantTimeCompare(deriveKey(current, s.salt, s.kdf), s.key) != 1 {
+		return ErrBadPassphrase
+	}
+
+	kdf, salt, key := s.kdf, s.salt, s.key
+	if err := s.setPassphrase(passphrase); err != nil {
+		return err
+	}
+	if err := s.save(); err != nil {
+		s.kdf, s.salt, s.key = kdf, salt, key
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store) setPassphrase(passphrase []byte) error {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	s.kdf = defaultKDF
+	s.salt = salt
+	s.key = deriveKey(passphrase, salt, s.kdf)
+
+	return nil
+}
+
+// save writes the store with a new nonce, the file is replaced through a temporary file so a failed write never
+// leaves a truncated store behind
+func (s *Store) save() error {
+	plain, err := json.Marshal(s.secrets)
+	if err != nil {
+		return err
+	}
+
+	aead, err := s.aead()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(storeFile{
+		Version: fileVersion,
+		KDF:     s.kdf,
+		Salt:    s.salt,
+		Nonce:   nonce,
+		Data:    aead.Seal(nil, nonce, plain, additionalData()),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err = os.WriteFile(tmp, b, 0600); err != nil {
+		return fmt.Errorf("error writing secrets file: %w", err)
+	}
+
+	return os.Rename(tmp, s.path)
+}
+
+func (s *Store) decrypt(nonce, data []byte) ([]byte, error) {
+	aead, err := s.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrBadPassphrase
+	}
+
+	plain, err := aead.Open(nil, nonce, data, additionalData())
+	if err != nil {
+		return nil, ErrBadPassphrase
+	}
+
+	return plain, nil
+}
+
+func (s *Store) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// additionalData binds the encrypted data to the file format version
+func additionalData() []byte {
+	return []byte(fmt.Sprintf("koolo-secrets-v%d", fileVersion))
+}
+
+func deriveKey(passphrase, salt []byte, p kdfParams) []byte {
+	return argon2.IDKey(passphrase, salt, p.Time, p.Memory, p.Threads, keyLength)
+}
+
+// Ref returns the config reference to the named secret
+func Ref(name string) string {
+	return RefPrefix + name
+}
+
+// ParseRef returns the secret name of a config reference, ok is false for plain values
+func ParseRef(value string) (name string, ok bool, err error) {
+	if !strings.HasPrefix(value, RefPrefix) {
+		return "", false, nil
+	}
+
+	name = strings.TrimPrefix(value, RefPrefix)
+	if !validName.MatchString(name) {
+		return "", true, fmt.Errorf("invalid secret reference %q", value)
+	}
+
+	return name, true, nil
+}
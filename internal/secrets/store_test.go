@@ -0,0 +1,120 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+
+	s, err := Open(path, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Set("account1", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Set("discord", "token"); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Set("bad name", "value"); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "hunter2") || strings.Contains(string(b), "account1") {
+		t.Errorf("secrets file is not encrypted:\n%s", b)
+	}
+
+	if _, err = Open(path, []byte("wrong")); !errors.Is(err, ErrBadPassphrase) {
+		t.Errorf("expected ErrBadPassphrase, got %v", err)
+	}
+
+	reopened, err := Open(path, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := reopened.Names(); !slices.Equal(names, []string{"account1", "discord"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+	if value, err := reopened.Get("account1"); err != nil || value != "hunter2" {
+		t.Errorf("expected hunter2, got %q (%v)", value, err)
+	}
+	if _, err = reopened.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStoreRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+
+	s, err := Open(path, []byte("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Set("account1", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Rotate([]byte("wrong"), []byte("new")); !errors.Is(err, ErrBadPassphrase) {
+		t.Errorf("expected a wrong current passphrase to fail, got %v", err)
+	}
+	if err = s.Rotate([]byte("old"), []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Open(path, []byte("old")); !errors.Is(err, ErrBadPassphrase) {
+		t.Errorf("expected the old passphrase to fail, got %v", err)
+	}
+	rotated, err := Open(path, []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := rotated.Get("account1"); value != "hunter2" {
+		t.Errorf("expected the secret to survive the rotation, got %q", value)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Cleanup(func() { current = nil })
+	current = nil
+
+	if value, err := Resolve("plain"); err != nil || value != "plain" {
+		t.Errorf("expected plain values untouched, got %q (%v)", value, err)
+	}
+	if _, err := Resolve("secret://account1"); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected ErrLocked, got %v", err)
+	}
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "secrets.key")
+	if err := os.WriteFile(keyFile, []byte("key file content\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnlockWithKeyFile(filepath.Join(dir, "secrets.json"), keyFile); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-Unlocked():
+	default:
+		t.Error("expected the values waiting for the store to be notified")
+	}
+	s, _ := Current()
+	if err := s.Set("account1", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, err := Resolve(Ref("account1")); err != nil || value != "hunter2" {
+		t.Errorf("expected hunter2, got %q (%v)", value, err)
+	}
+	if _, err := Resolve("secret://"); err == nil {
+		t.Error("expected an error for an empty reference")
+	}
+}
@@ -7,6 +7,9 @@ const searchInput = document.getElementById('search-input');
 const searchPrevBtn = document.getElementById('search-prev-btn');
 const searchNextBtn = document.getElementById('search-next-btn');
 const searchResults = document.getElementById('search-results');
+const pathQuerySelect = document.getElementById('path-query-select');
+const pathQueryDetails = document.getElementById('path-query-details');
+const pathQueryImage = document.getElementById('path-query-image');
 
 let refreshInterval = 1000; // Default to 1 second
 let refreshIntervalId;
@@ -146,8 +149,7 @@ function updateExpandAllButton() {
 }
 
 function fetchDebugData() {
-    const urlParams = new URLSearchParams(window.location.search);
-    const characterName = urlParams.get('characterName') || 'nullref';
+    const characterName = getCharacterName();
     fetch(`/debug-data?characterName=${characterName}`)
         .then(response => response.json())
         .then(data => {
@@ -165,12 +167,61 @@ function fetchDebugData() {
         });
 }
 
+function getCharacterName() {
+    const urlParams = new URLSearchParams(window.location.search);
+    return urlParams.get('characterName') || 'nullref';
+}
+
+function formatPathQuery(query) {
+    const result = query.found ? `found, distance ${query.distance}, cost ${query.cost}` : `not found${query.reason ? ': ' + query.reason : ''}`;
+    return `#${query.id} area ${query.area} (${query.from.X},${query.from.Y}) -> (${query.to.X},${query.to.Y}) ${result} in ${(query.duration / 1e6).toFixed(1)}ms`;
+}
+
+let pathQueries = [];
+
+function fetchPathQueries() {
+    fetch(`/debug-paths?characterName=${getCharacterName()}`)
+        .then(response => response.ok ? response.json() : [])
+        .then(queries => {
+            pathQueries = queries || [];
+            const selected = pathQuerySelect.value;
+            pathQuerySelect.innerHTML = '';
+            for (const query of pathQueries) {
+                const option = document.createElement('option');
+                option.value = query.id;
+                option.textContent = `#${query.id} ${query.found ? '' : '(failed) '}(${query.to.X},${query.to.Y})`;
+                pathQuerySelect.appendChild(option);
+            }
+            if (selected && pathQueries.some(query => String(query.id) === selected)) {
+                pathQuerySelect.value = selected;
+            }
+            showPathQuery();
+        })
+        .catch(error => console.error('Error fetching path queries:', error));
+}
+
+function showPathQuery() {
+    const query = pathQueries.find(query => String(query.id) === pathQuerySelect.value);
+    if (!query) {
+        pathQueryDetails.textContent = 'No path queries recorded';
+        pathQueryImage.removeAttribute('src');
+        return;
+    }
+
+    pathQueryDetails.textContent = formatPathQuery(query);
+    // Queries never change once recorded, the image is only requested when the selection does
+    const src = `/debug-paths?characterName=${getCharacterName()}&id=${query.id}`;
+    if (pathQueryImage.getAttribute('src') !== src) {
+        pathQueryImage.src = src;
+    }
+}
+
 function setRefreshInterval() {
     const newInterval = parseInt(refreshIntervalInput.value, 10) * 1000;
     if (newInterval && newInterval > 0) {
         refreshInterval = newInterval;
         clearInterval(refreshIntervalId);
-        refreshIntervalId = setInterval(fetchDebugData, refreshInterval);
+        refreshIntervalId = setInterval(refreshAll, refreshInterval);
     }
 }
 
@@ -316,7 +367,13 @@ function createCopyDataButton() {
     });
 }
 
+function refreshAll() {
+    fetchDebugData();
+    fetchPathQueries();
+}
+
 // Event Listeners
+pathQuerySelect.addEventListener('change', showPathQuery);
 setIntervalBtn.addEventListener('click', setRefreshInterval);
 expandAllBtn.addEventListener('click', toggleExpandAll);
 searchInput.addEventListener('input', () => performSearch());
@@ -325,5 +382,5 @@ searchPrevBtn.addEventListener('click', goToPreviousSearchResult);
 
 // Initialize
 createCopyDataButton();
-fetchDebugData();
-refreshIntervalId = setInterval(fetchDebugData, refreshInterval);
\ No newline at end of file
+refreshAll();
+refreshIntervalId = setInterval(refreshAll, refreshInterval);
\ No newline at end of file
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"image/png"
 	"io/fs"
 	"log/slog"
 	"net/http"
@@ -15,9 +16,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
-	"unsafe"
 
 	"github.com/gorilla/websocket"
 	"github.com/hectorgimenez/d2go/pkg/data"
@@ -28,10 +27,8 @@ import (
 	"github.com/hectorgimenez/koolo/internal/config"
 	ctx "github.com/hectorgimenez/koolo/internal/context"
 	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
-	"github.com/hectorgimenez/koolo/internal/utils/winproc"
-	"github.com/lxn/win"
-	"golang.org/x/sys/windows"
 )
 
 type HttpServer struct {
@@ -240,18 +237,7 @@ func (s *HttpServer) attachProcess(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Find the main window handle (HWND) for the process
-	var hwnd win.HWND
-	enumWindowsCallback := func(h win.HWND, param uintptr) uintptr {
-		var processID uint32
-		win.GetWindowThreadProcessId(h, &processID)
-		if processID == uint32(pid) {
-			hwnd = h
-			return 0 // Stop enumeration
-		}
-		return 1 // Continue enumeration
-	}
-
-	windows.EnumWindows(syscall.NewCallback(enumWindowsCallback), nil)
+	hwnd := findProcessWindow(uint32(pid))
 
 	if hwnd == 0 {
 		s.logger.Error("Failed to find window handle for process", "pid", pid)
@@ -259,88 +245,12 @@ func (s *HttpServer) attachProcess(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Call manager.Start with the correct arguments, including the HWND
-	go s.manager.Start(characterName, true, uint32(pid), uint32(hwnd))
+	go s.manager.Start(characterName, true, uint32(pid), hwnd)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
 
-// Add this helper function
-func getRunningProcesses() ([]Process, error) {
-	var processes []Process
-
-	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
-	if err != nil {
-		return nil, err
-	}
-	defer windows.CloseHandle(snapshot)
-
-	var entry windows.ProcessEntry32
-	entry.Size = uint32(unsafe.Sizeof(entry))
-
-	err = windows.Process32First(snapshot, &entry)
-	if err != nil {
-		return nil, err
-	}
-
-	for {
-		windowTitle, _ := getWindowTitle(entry.ProcessID)
-
-		if strings.ToLower(syscall.UTF16ToString(entry.ExeFile[:])) == "d2r.exe" {
-			processes = append(processes, Process{
-				WindowTitle: windowTitle,
-				ProcessName: syscall.UTF16ToString(entry.ExeFile[:]),
-				PID:         entry.ProcessID,
-			})
-		}
-
-		err = windows.Process32Next(snapshot, &entry)
-		if err != nil {
-			if err == windows.ERROR_NO_MORE_FILES {
-				break
-			}
-			return nil, err
-		}
-	}
-
-	return processes, nil
-}
-
-func getWindowTitle(pid uint32) (string, error) {
-	var windowTitle string
-	var hwnd windows.HWND
-
-	cb := syscall.NewCallback(func(h win.HWND, param uintptr) uintptr {
-		var currentPID uint32
-		_ = win.GetWindowThreadProcessId(h, &currentPID)
-
-		if currentPID == pid {
-			hwnd = windows.HWND(h)
-			return 0 // stop enumeration
-		}
-		return 1 // continue enumeration
-	})
-
-	// Enumerate all windows
-	windows.EnumWindows(cb, nil)
-
-	if hwnd == 0 {
-		return "", fmt.Errorf("no window found for process ID %d", pid)
-	}
-
-	// Get window title
-	var title [256]uint16
-	_, _, _ = winproc.GetWindowText.Call(
-		uintptr(hwnd),
-		uintptr(unsafe.Pointer(&title[0])),
-		uintptr(len(title)),
-	)
-
-	windowTitle = syscall.UTF16ToString(title[:])
-	return windowTitle, nil
-
-}
-
 func qualityClass(quality string) string {
 	switch quality {
 	case "LowQuality":
@@ -416,12 +326,18 @@ func (s *HttpServer) Listen(port int) error {
 	http.HandleFunc("/togglePause", s.togglePause)
 	http.HandleFunc("/debug", s.debugHandler)
 	http.HandleFunc("/debug-data", s.debugData)
+	http.HandleFunc("/debug-paths", s.debugPaths)
 	http.HandleFunc("/drops", s.drops)
 	http.HandleFunc("/process-list", s.getProcessList)
 	http.HandleFunc("/attach-process", s.attachProcess)
 	http.HandleFunc("/ws", s.wsServer.HandleWebSocket)    // Web socket
 	http.HandleFunc("/initial-data", s.initialData)       // Web socket data
 	http.HandleFunc("/api/reload-config", s.reloadConfig) // New handler
+	http.HandleFunc("/api/secrets", s.secretsStatus)
+	http.HandleFunc("/api/secrets/unlock", s.unlockSecrets)
+	http.HandleFunc("/api/secrets/set", s.setSecret)
+	http.HandleFunc("/api/secrets/delete", s.deleteSecret)
+	http.HandleFunc("/api/secrets/rotate", s.rotateSecrets)
 
 	assets, _ := fs.Sub(assetsFS, "assets")
 	http.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.FS(assets))))
@@ -497,6 +413,47 @@ func (s *HttpServer) debugData(w http.ResponseWriter, r *http.Request) {
 	w.Write(jsonData)
 }
 
+// debugPaths returns the path queries recorded by the path finder, or the rendered grid of one of them when an id is given
+func (s *HttpServer) debugPaths(w http.ResponseWriter, r *http.Request) {
+	characterName := r.URL.Query().Get("characterName")
+	if characterName == "" {
+		http.Error(w, "Character name is required", http.StatusBadRequest)
+		return
+	}
+
+	context := s.manager.GetContext(characterName)
+	if context == nil || context.PathFinder == nil {
+		http.Error(w, "Character is not running", http.StatusNotFound)
+		return
+	}
+
+	if r.URL.Query().Has("id") {
+		id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid path query id", http.StatusBadRequest)
+			return
+		}
+
+		img, err := context.PathFinder.RenderPathQuery(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		png.Encode(w, img)
+		return
+	}
+
+	jsonData, err := json.Marshal(context.PathFinder.PathQueries())
+	if err != nil {
+		http.Error(w, "Failed to serialize path queries", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
+
 func (s *HttpServer) debugHandler(w http.ResponseWriter, r *http.Request) {
 	s.templates.ExecuteTemplate(w, "debug.gohtml", nil)
 }
@@ -600,6 +557,25 @@ func (s *HttpServer) drops(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// editableConfig returns a copy of cfg the settings form can be written to, the slices the form changes in place are
+// copied too so the loaded config never sees the values before they are validated
+func editableConfig(cfg *config.CharacterCfg) *config.CharacterCfg {
+	c := *cfg
+
+	c.Scheduler.Days = make([]config.Day, 7)
+	for day := range c.Scheduler.Days {
+		c.Scheduler.Days[day] = config.Day{DayOfWeek: day}
+	}
+	copy(c.Scheduler.Days, cfg.Scheduler.Days)
+
+	c.Inventory.InventoryLock = make([][]int, len(cfg.Inventory.InventoryLock))
+	for y, row := range cfg.Inventory.InventoryLock {
+		c.Inventory.InventoryLock[y] = slices.Clone(row)
+	}
+
+	return &c
+}
+
 func validateSchedulerData(cfg *config.CharacterCfg) error {
 	for day := 0; day < 7; day++ {
 
@@ -700,20 +676,13 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// The form is written to a copy, the loaded config is only replaced once the new one is valid and saved
 		supervisorName := r.Form.Get("name")
-		cfg, found := config.Characters[supervisorName]
+		current, found := config.Characters[supervisorName]
 		if !found {
-			err = config.CreateFromTemplate(supervisorName)
-			if err != nil {
-				s.templates.ExecuteTemplate(w, "character_settings.gohtml", CharacterSettings{
-					ErrorMessage: err.Error(),
-					Supervisor:   supervisorName,
-				})
-
-				return
-			}
-			cfg = config.Characters["template"]
+			current = config.Characters["template"]
 		}
+		cfg := editableConfig(current)
 
 		cfg.MaxGameLength, _ = strconv.Atoi(r.Form.Get("maxGameLength"))
 		cfg.CharacterName = r.Form.Get("characterName")
@@ -722,6 +691,7 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 		cfg.ClassicMode = r.Form.Has("classic_mode")
 		cfg.CloseMiniPanel = r.Form.Has("close_mini_panel")
 		cfg.HidePortraits = r.Form.Has("hide_portraits")
+		cfg.LayoutProfile = r.Form.Get("layoutProfile")
 
 		// Bnet config
 		cfg.Username = r.Form.Get("username")
@@ -772,10 +742,7 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 		// Validate scheduler data
 		err := validateSchedulerData(cfg)
 		if err != nil {
-			s.templates.ExecuteTemplate(w, "character_settings.gohtml", CharacterSettings{
-				ErrorMessage: err.Error(),
-				// ... (other fields)
-			})
+			s.renderCharacterSettings(w, supervisorName, cfg, err.Error(), nil)
 			return
 		}
 
@@ -794,6 +761,11 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 		cfg.Character.Class = r.Form.Get("characterClass")
 		cfg.Character.StashToShared = r.Form.Has("characterStashToShared")
 		cfg.Character.UseTeleport = r.Form.Has("characterUseTeleport")
+		cfg.Character.ThreatAvoidance.Enabled = r.Form.Has("threatAvoidanceEnabled")
+		cfg.Character.ThreatAvoidance.Radius, _ = strconv.Atoi(r.Form.Get("threatAvoidanceRadius"))
+		cfg.Character.ThreatAvoidance.Weight, _ = strconv.Atoi(r.Form.Get("threatAvoidanceWeight"))
+		cfg.Character.Kiting.Enabled = r.Form.Has("kitingEnabled")
+		cfg.Character.Kiting.DangerRadius, _ = strconv.Atoi(r.Form.Get("kitingDangerRadius"))
 
 		// Berserker Barb specific options
 		if cfg.Character.Class == "berserker" {
@@ -981,7 +953,22 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 		cfg.BackToTown.MercDied = r.Form.Has("mercDied")
 		cfg.BackToTown.EquipmentBroken = r.Form.Has("equipmentBroken")
 
-		config.SaveSupervisorConfig(supervisorName, cfg)
+		// Configs with errors are not saved, the form is shown again with the issues next to each field
+		if issues := cfg.ValidateFields(); issues.HasErrors() {
+			s.renderCharacterSettings(w, supervisorName, cfg, "The configuration has errors and was not saved, check the highlighted fields", issues)
+			return
+		}
+
+		if !found {
+			if err = config.CreateFromTemplate(supervisorName); err != nil {
+				s.renderCharacterSettings(w, supervisorName, cfg, err.Error(), nil)
+				return
+			}
+		}
+		if err = config.SaveSupervisorConfig(supervisorName, cfg); err != nil {
+			s.renderCharacterSettings(w, supervisorName, cfg, err.Error(), nil)
+			return
+		}
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
 	}
@@ -992,6 +979,10 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 		cfg = config.Characters[supervisor]
 	}
 
+	s.renderCharacterSettings(w, supervisor, cfg, "", cfg.ValidateFields())
+}
+
+func (s *HttpServer) renderCharacterSettings(w http.ResponseWriter, supervisor string, cfg *config.CharacterCfg, errorMessage string, issues config.ValidationIssues) {
 	enabledRuns := make([]string, 0)
 	// Let's iterate cfg.Game.Runs to preserve current order
 	for _, run := range cfg.Game.Runs {
@@ -1022,12 +1013,15 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 	dayNames := []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
 
 	s.templates.ExecuteTemplate(w, "character_settings.gohtml", CharacterSettings{
-		Supervisor:   supervisor,
-		Config:       cfg,
-		DayNames:     dayNames,
-		EnabledRuns:  enabledRuns,
-		DisabledRuns: disabledRuns,
-		AvailableTZs: availableTZs,
-		RecipeList:   config.AvailableRecipes,
+		ErrorMessage:   errorMessage,
+		Supervisor:     supervisor,
+		Config:         cfg,
+		DayNames:       dayNames,
+		EnabledRuns:    enabledRuns,
+		DisabledRuns:   disabledRuns,
+		AvailableTZs:   availableTZs,
+		RecipeList:     config.AvailableRecipes,
+		LayoutProfiles: ui.ProfileNames(),
+		Issues:         issues,
 	})
 }
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+func TestEditableConfigDoesNotChangeTheLoadedConfig(t *testing.T) {
+	loaded := &config.CharacterCfg{}
+	loaded.Health.ChickenAt = 30
+	loaded.Inventory.InventoryLock = [][]int{{1, 1}, {0, 0}}
+	loaded.Scheduler.Days = []config.Day{{DayOfWeek: 0, TimeRanges: []config.TimeRange{{}}}}
+
+	edited := editableConfig(loaded)
+	edited.Health.ChickenAt = 90
+	edited.Inventory.InventoryLock[0][0] = 0
+	for day := range edited.Scheduler.Days {
+		edited.Scheduler.Days[day].TimeRanges = nil
+	}
+
+	if loaded.Health.ChickenAt != 30 || loaded.Inventory.InventoryLock[0][0] != 1 || len(loaded.Scheduler.Days[0].TimeRanges) != 1 {
+		t.Errorf("loaded config was changed: %+v", loaded)
+	}
+	if len(edited.Scheduler.Days) != 7 {
+		t.Errorf("expected the seven scheduler days, got %d", len(edited.Scheduler.Days))
+	}
+}
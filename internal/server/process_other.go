@@ -0,0 +1,13 @@
+//go:build !windows
+
+package server
+
+// The game only runs on Windows, there are no game processes to attach to on other systems
+
+func findProcessWindow(_ uint32) uint32 {
+	return 0
+}
+
+func getRunningProcesses() ([]Process, error) {
+	return nil, nil
+}
@@ -0,0 +1,107 @@
+//go:build windows
+
+package server
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/hectorgimenez/koolo/internal/utils/winproc"
+	"github.com/lxn/win"
+	"golang.org/x/sys/windows"
+)
+
+// findProcessWindow returns the main window handle of the process, 0 if it has no window
+func findProcessWindow(pid uint32) uint32 {
+	var hwnd win.HWND
+	enumWindowsCallback := func(h win.HWND, param uintptr) uintptr {
+		var processID uint32
+		win.GetWindowThreadProcessId(h, &processID)
+		if processID == pid {
+			hwnd = h
+			return 0 // Stop enumeration
+		}
+		return 1 // Continue enumeration
+	}
+
+	windows.EnumWindows(syscall.NewCallback(enumWindowsCallback), nil)
+
+	return uint32(hwnd)
+}
+
+func getRunningProcesses() ([]Process, error) {
+	var processes []Process
+
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	err = windows.Process32First(snapshot, &entry)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		windowTitle, _ := getWindowTitle(entry.ProcessID)
+
+		if strings.ToLower(syscall.UTF16ToString(entry.ExeFile[:])) == "d2r.exe" {
+			processes = append(processes, Process{
+				WindowTitle: windowTitle,
+				ProcessName: syscall.UTF16ToString(entry.ExeFile[:]),
+				PID:         entry.ProcessID,
+			})
+		}
+
+		err = windows.Process32Next(snapshot, &entry)
+		if err != nil {
+			if err == windows.ERROR_NO_MORE_FILES {
+				break
+			}
+			return nil, err
+		}
+	}
+
+	return processes, nil
+}
+
+func getWindowTitle(pid uint32) (string, error) {
+	var windowTitle string
+	var hwnd windows.HWND
+
+	cb := syscall.NewCallback(func(h win.HWND, param uintptr) uintptr {
+		var currentPID uint32
+		_ = win.GetWindowThreadProcessId(h, &currentPID)
+
+		if currentPID == pid {
+			hwnd = windows.HWND(h)
+			return 0 // stop enumeration
+		}
+		return 1 // continue enumeration
+	})
+
+	// Enumerate all windows
+	windows.EnumWindows(cb, nil)
+
+	if hwnd == 0 {
+		return "", fmt.Errorf("no window found for process ID %d", pid)
+	}
+
+	// Get window title
+	var title [256]uint16
+	_, _, _ = winproc.GetWindowText.Call(
+		uintptr(hwnd),
+		uintptr(unsafe.Pointer(&title[0])),
+		uintptr(len(title)),
+	)
+
+	windowTitle = syscall.UTF16ToString(title[:])
+	return windowTitle, nil
+
+}
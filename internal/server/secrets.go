@@ -0,0 +1,139 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/secrets"
+)
+
+// Secret values are write only, the endpoints never send them back, only the names and the secret:// references
+type secretsStatus struct {
+	Unlocked bool     `json:"unlocked"`
+	Names    []string `json:"names"`
+}
+
+type secretRequest struct {
+	Name          string `json:"name"`
+	Value         string `json:"value"`
+	Passphrase    string `json:"passphrase"`
+	NewPassphrase string `json:"newPassphrase"`
+}
+
+func (s *HttpServer) secretsStatus(w http.ResponseWriter, r *http.Request) {
+	status := secretsStatus{Names: []string{}}
+	if store, err := secrets.Current(); err == nil {
+		status.Unlocked = true
+		status.Names = store.Names()
+	}
+
+	writeJSON(w, status)
+}
+
+// unlockSecrets opens the store with the given passphrase, a new store is created if the file doesn't exist yet
+func (s *HttpServer) unlockSecrets(w http.ResponseWriter, r *http.Request) {
+	req, ok := readSecretRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := secrets.Unlock(config.Koolo.SecretsFile(), []byte(req.Passphrase)); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	s.logger.Info("Secrets store unlocked from the web UI")
+	s.secretsStatus(w, r)
+}
+
+// setSecret adds a secret or replaces its value, the response holds the reference to use in the config files
+func (s *HttpServer) setSecret(w http.ResponseWriter, r *http.Request) {
+	req, ok := readSecretRequest(w, r)
+	if !ok {
+		return
+	}
+	store, err := secrets.Current()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if err = store.Set(req.Name, req.Value); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.logger.Info("Secret updated", slog.String("name", req.Name))
+	writeJSON(w, map[string]string{"name": req.Name, "ref": secrets.Ref(req.Name)})
+}
+
+func (s *HttpServer) deleteSecret(w http.ResponseWriter, r *http.Request) {
+	req, ok := readSecretRequest(w, r)
+	if !ok {
+		return
+	}
+	store, err := secrets.Current()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if err = store.Delete(req.Name); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, secrets.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	s.logger.Info("Secret deleted", slog.String("name", req.Name))
+	s.secretsStatus(w, r)
+}
+
+// rotateSecrets re-encrypts the store with a new passphrase, the current one is checked against the unlocked store
+func (s *HttpServer) rotateSecrets(w http.ResponseWriter, r *http.Request) {
+	req, ok := readSecretRequest(w, r)
+	if !ok {
+		return
+	}
+	store, err := secrets.Current()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if err = store.Rotate([]byte(req.Passphrase), []byte(req.NewPassphrase)); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, secrets.ErrBadPassphrase) {
+			status = http.StatusUnauthorized
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	s.logger.Info("Secrets passphrase rotated")
+	s.secretsStatus(w, r)
+}
+
+func readSecretRequest(w http.ResponseWriter, r *http.Request) (secretRequest, bool) {
+	req := secretRequest{}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return req, false
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return req, false
+	}
+
+	return req, true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -1,115 +1,86 @@
 package ui
 
-const (
-	itemBoxSize        = 33
-	itemBoxSizeClassic = 35
-
-	inventoryTopLeftX        = 846
-	inventoryTopLeftXClassic = 663
-
-	inventoryTopLeftY        = 369
-	inventoryTopLeftYClassic = 379
-
-	topCornerVendorWindowX        = 109
-	topCornerVendorWindowXClassic = 275
-
-	topCornerVendorWindowY        = 147
-	topCornerVendorWindowYClassic = 149
-
-	topCornerCubeWindowX        = 222
-	topCornerCubeWindowXClassic = 398
-
-	topCornerCubeWindowY        = 247
-	topCornerCubeWindowYClassic = 239
-
-	MercAvatarPositionX        = 36
-	MercAvatarPositionXClassic = 208
-
-	MercAvatarPositionY        = 39
-	MercAvatarPositionYClassic = 53
-
-	CubeTransmuteBtnX        = 273
-	CubeTransmuteBtnXClassic = 451
-
-	CubeTransmuteBtnY        = 411
-	CubeTransmuteBtnYClassic = 405
-
-	CubeTakeItemX        = 306
-	CubeTakeItemXClassic = 484
-
-	CubeTakeItemY        = 365
-	CubeTakeItemYClassic = 358
-
-	WpTabStartX        = 131
-	WpTabStartXClassic = 266
-
-	WpTabStartY        = 148
-	WpTabStartYClassic = 95
-
-	WpTabSizeX        = 57
-	WpTabSizeXClassic = 75
-
-	WpListPositionX        = 200
-	WpListPositionXClassic = 357
-
-	WpListStartY        = 158
-	WpListStartYClassic = 145
-
-	WpAreaBtnHeight        = 41
-	WpAreaBtnHeightClassic = 43
-
-	RepairButtonX        = 390
-	RepairButtonXClassic = 602
-
-	RepairButtonY        = 515
-	RepairButtonYClassic = 557
-
-	AnvilCenterX = 272
-	AnvilCenterY = 333
-	AnvilBtnX    = 272
-	AnvilBtnY    = 450
-
-	MainSkillButtonX = 596
-	MainSkillButtonY = 693
-
-	SecondarySkillButtonX = 686
-	SecondarySkillButtonY = 693
-
-	GambleRefreshButtonX        = 390
-	GambleRefreshButtonXClassic = 540
-
-	GambleRefreshButtonY        = 515
-	GambleRefreshButtonYClassic = 553
-
-	SecondarySkillListFirstSkillX = 687
-	MainSkillListFirstSkillX      = 592
-	SkillListFirstSkillY          = 590
-	SkillListSkillOffset          = 45
-
-	FirstMercFromContractorListX = 175
-	FirstMercFromContractorListY = 142
-
-	StashGoldBtnX        = 966
-	StashGoldBtnXClassic = 754
-
-	StashGoldBtnY        = 526
-	StashGoldBtnYClassic = 552
-
-	StashGoldBtnConfirmX        = 547
-	StashGoldBtnConfirmXClassic = 579
-
-	StashGoldBtnConfirmY        = 388
-	StashGoldBtnConfirmYClassic = 423
-
-	SwitchStashTabBtnX        = 107
-	SwitchStashTabBtnXClassic = 258
-
-	SwitchStashTabBtnY        = 128
-	SwitchStashTabBtnYClassic = 84
-
-	SwitchStashTabBtnTabSize        = 82
-	SwitchStashTabBtnTabSizeClassic = 96
-
-	CloseMiniPanelClassicX = 639
-	CloseMiniPanelClassicY = 686
-)
+import "github.com/hectorgimenez/d2go/pkg/data"
+
+// DefaultProfile holds the anchor points for a 1280x720 game window, the size the bot has been built around. Every
+// other profile starts from these values, so profile files only need to list the anchors that don't match.
+var DefaultProfile = Profile{
+	Name:   DefaultProfileName,
+	Width:  1280,
+	Height: 720,
+	D2R: Layout{
+		ItemBoxSize:                  33,
+		InventoryTopLeft:             data.Position{X: 846, Y: 369},
+		VendorWindowTopLeft:          data.Position{X: 109, Y: 147},
+		CubeWindowTopLeft:            data.Position{X: 222, Y: 247},
+		MercAvatar:                   data.Position{X: 36, Y: 39},
+		CubeTransmuteButton:          data.Position{X: 273, Y: 411},
+		CubeTakeItem:                 data.Position{X: 306, Y: 365},
+		WpTabStart:                   data.Position{X: 131, Y: 148},
+		WpTabWidth:                   57,
+		WpListStart:                  data.Position{X: 200, Y: 158},
+		WpAreaButtonHeight:           41,
+		RepairButton:                 data.Position{X: 390, Y: 515},
+		AnvilCenter:                  data.Position{X: 272, Y: 333},
+		AnvilButton:                  data.Position{X: 272, Y: 450},
+		MainSkillButton:              data.Position{X: 596, Y: 693},
+		SecondarySkillButton:         data.Position{X: 686, Y: 693},
+		GambleRefreshButton:          data.Position{X: 390, Y: 515},
+		MainSkillListFirstSkill:      data.Position{X: 592, Y: 590},
+		SecondarySkillListFirstSkill: data.Position{X: 687, Y: 590},
+		SkillListSkillOffset:         45,
+		FirstMercFromContractorList:  data.Position{X: 175, Y: 142},
+		StashGoldButton:              data.Position{X: 966, Y: 526},
+		StashGoldConfirmButton:       data.Position{X: 547, Y: 388},
+		SwitchStashTab:               data.Position{X: 107, Y: 128},
+		SwitchStashTabWidth:          82,
+		StatButtons: StatButtons{
+			Strength:  data.Position{X: 240, Y: 210},
+			Dexterity: data.Position{X: 240, Y: 290},
+			Vitality:  data.Position{X: 240, Y: 380},
+			Energy:    data.Position{X: 240, Y: 430},
+		},
+		SkillTreeTabs:    [3]data.Position{{X: 1100, Y: 140}, {X: 1010, Y: 140}, {X: 910, Y: 140}},
+		SkillTreeRows:    [6]int{190, 250, 310, 365, 430, 490},
+		SkillTreeColumns: [3]int{920, 1010, 1095},
+		OnlineTab:        data.Position{X: 1090, Y: 32},
+		LobbyButton:      data.Position{X: 744, Y: 650},
+	},
+	Legacy: Layout{
+		ItemBoxSize:                  35,
+		InventoryTopLeft:             data.Position{X: 663, Y: 379},
+		VendorWindowTopLeft:          data.Position{X: 275, Y: 149},
+		CubeWindowTopLeft:            data.Position{X: 398, Y: 239},
+		MercAvatar:                   data.Position{X: 208, Y: 53},
+		CubeTransmuteButton:          data.Position{X: 451, Y: 405},
+		CubeTakeItem:                 data.Position{X: 484, Y: 358},
+		WpTabStart:                   data.Position{X: 266, Y: 95},
+		WpTabWidth:                   75,
+		WpListStart:                  data.Position{X: 357, Y: 145},
+		WpAreaButtonHeight:           43,
+		RepairButton:                 data.Position{X: 602, Y: 557},
+		AnvilCenter:                  data.Position{X: 272, Y: 333},
+		AnvilButton:                  data.Position{X: 272, Y: 450},
+		MainSkillButton:              data.Position{X: 596, Y: 693},
+		SecondarySkillButton:         data.Position{X: 686, Y: 693},
+		GambleRefreshButton:          data.Position{X: 540, Y: 553},
+		MainSkillListFirstSkill:      data.Position{X: 592, Y: 590},
+		SecondarySkillListFirstSkill: data.Position{X: 687, Y: 590},
+		SkillListSkillOffset:         45,
+		FirstMercFromContractorList:  data.Position{X: 175, Y: 142},
+		StashGoldButton:              data.Position{X: 754, Y: 552},
+		StashGoldConfirmButton:       data.Position{X: 579, Y: 423},
+		SwitchStashTab:               data.Position{X: 258, Y: 84},
+		SwitchStashTabWidth:          96,
+		CloseMiniPanel:               data.Position{X: 639, Y: 686},
+		StatButtons: StatButtons{
+			Strength:  data.Position{X: 430, Y: 180},
+			Dexterity: data.Position{X: 430, Y: 250},
+			Vitality:  data.Position{X: 430, Y: 360},
+			Energy:    data.Position{X: 430, Y: 435},
+		},
+		SkillTreeTabs:    [3]data.Position{{X: 970, Y: 510}, {X: 970, Y: 390}, {X: 970, Y: 260}},
+		SkillTreeRows:    [6]int{110, 195, 275, 355, 440, 520},
+		SkillTreeColumns: [3]int{690, 770, 855},
+	},
+}
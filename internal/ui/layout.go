@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/context"
+	"gopkg.in/yaml.v3"
+)
+
+const DefaultProfileName = "default"
+
+// ProfilesDir is the directory holding the layout profile files, one {name}.yaml file per profile
+var ProfilesDir = filepath.Join("config", config.LayoutsDir)
+
+var (
+	profilesMu sync.RWMutex
+	profiles   = map[string]Profile{}
+)
+
+// Profile holds the screen position of every UI element the bot clicks on, for both graphics modes. Positions are
+// taken for a window of Width x Height pixels, and scaled to the real game window size when used.
+type Profile struct {
+	Name   string `yaml:"-"`
+	Width  int    `yaml:"width"`
+	Height int    `yaml:"height"`
+	D2R    Layout `yaml:"d2r"`
+	Legacy Layout `yaml:"legacy"`
+}
+
+type Layout struct {
+	ItemBoxSize                  int              `yaml:"itemBoxSize"`
+	InventoryTopLeft             data.Position    `yaml:"inventoryTopLeft"`
+	VendorWindowTopLeft          data.Position    `yaml:"vendorWindowTopLeft"`
+	CubeWindowTopLeft            data.Position    `yaml:"cubeWindowTopLeft"`
+	MercAvatar                   data.Position    `yaml:"mercAvatar"`
+	CubeTransmuteButton          data.Position    `yaml:"cubeTransmuteButton"`
+	CubeTakeItem                 data.Position    `yaml:"cubeTakeItem"`
+	WpTabStart                   data.Position    `yaml:"wpTabStart"`
+	WpTabWidth                   int              `yaml:"wpTabWidth"`
+	WpListStart                  data.Position    `yaml:"wpListStart"`
+	WpAreaButtonHeight           int              `yaml:"wpAreaButtonHeight"`
+	RepairButton                 data.Position    `yaml:"repairButton"`
+	AnvilCenter                  data.Position    `yaml:"anvilCenter"`
+	AnvilButton                  data.Position    `yaml:"anvilButton"`
+	MainSkillButton              data.Position    `yaml:"mainSkillButton"`
+	SecondarySkillButton         data.Position    `yaml:"secondarySkillButton"`
+	GambleRefreshButton          data.Position    `yaml:"gambleRefreshButton"`
+	MainSkillListFirstSkill      data.Position    `yaml:"mainSkillListFirstSkill"`
+	SecondarySkillListFirstSkill data.Position    `yaml:"secondarySkillListFirstSkill"`
+	SkillListSkillOffset         int              `yaml:"skillListSkillOffset"`
+	FirstMercFromContractorList  data.Position    `yaml:"firstMercFromContractorList"`
+	StashGoldButton              data.Position    `yaml:"stashGoldButton"`
+	StashGoldConfirmButton       data.Position    `yaml:"stashGoldConfirmButton"`
+	SwitchStashTab               data.Position    `yaml:"switchStashTab"`
+	SwitchStashTabWidth          int              `yaml:"switchStashTabWidth"`
+	CloseMiniPanel               data.Position    `yaml:"closeMiniPanel"`
+	StatButtons                  StatButtons      `yaml:"statButtons"`
+	SkillTreeTabs                [3]data.Position `yaml:"skillTreeTabs"`
+	SkillTreeRows                [6]int           `yaml:"skillTreeRows"`
+	SkillTreeColumns             [3]int           `yaml:"skillTreeColumns"`
+	// Character selection screen, only read from the D2R layout, menus aren't drawn with legacy graphics
+	OnlineTab   data.Position `yaml:"onlineTab"`
+	LobbyButton data.Position `yaml:"lobbyButton"`
+}
+
+type StatButtons struct {
+	Strength  data.Position `yaml:"strength"`
+	Dexterity data.Position `yaml:"dexterity"`
+	Vitality  data.Position `yaml:"vitality"`
+	Energy    data.Position `yaml:"energy"`
+}
+
+func (s StatButtons) Find(id stat.ID) (data.Position, bool) {
+	switch id {
+	case stat.Strength:
+		return s.Strength, true
+	case stat.Dexterity:
+		return s.Dexterity, true
+	case stat.Vitality:
+		return s.Vitality, true
+	case stat.Energy:
+		return s.Energy, true
+	}
+
+	return data.Position{}, false
+}
+
+// Layout returns the layout for the given graphics mode, scaled to a game window of width x height pixels. Unknown
+// window sizes (zero) keep the profile positions.
+func (p Profile) Layout(legacyGraphics bool, width, height int) Layout {
+	l := p.D2R
+	if legacyGraphics {
+		l = p.Legacy
+	}
+
+	if width <= 0 || height <= 0 || p.Width <= 0 || p.Height <= 0 {
+		return l
+	}
+
+	return l.Scale(float64(width)/float64(p.Width), float64(height)/float64(p.Height))
+}
+
+// Scaled returns the profile with all the positions moved to a window of width x height pixels
+func (p Profile) Scaled(width, height int) Profile {
+	p.D2R = p.Layout(false, width, height)
+	p.Legacy = p.Layout(true, width, height)
+	p.Width = width
+	p.Height = height
+
+	return p
+}
+
+// Scale multiplies horizontal positions and sizes by sx and vertical ones by sy. Item boxes and skill icons are
+// square, their sizes follow the height of the window like the rest of the game UI does.
+func (l Layout) Scale(sx, sy float64) Layout {
+	x := func(v int) int { return int(math.Round(float64(v) * sx)) }
+	y := func(v int) int { return int(math.Round(float64(v) * sy)) }
+	pos := func(p data.Position) data.Position { return data.Position{X: x(p.X), Y: y(p.Y)} }
+
+	s := Layout{
+		ItemBoxSize:                  y(l.ItemBoxSize),
+		InventoryTopLeft:             pos(l.InventoryTopLeft),
+		VendorWindowTopLeft:          pos(l.VendorWindowTopLeft),
+		CubeWindowTopLeft:            pos(l.CubeWindowTopLeft),
+		MercAvatar:                   pos(l.MercAvatar),
+		CubeTransmuteButton:          pos(l.CubeTransmuteButton),
+		CubeTakeItem:                 pos(l.CubeTakeItem),
+		WpTabStart:                   pos(l.WpTabStart),
+		WpTabWidth:                   x(l.WpTabWidth),
+		WpListStart:                  pos(l.WpListStart),
+		WpAreaButtonHeight:           y(l.WpAreaButtonHeight),
+		RepairButton:                 pos(l.RepairButton),
+		AnvilCenter:                  pos(l.AnvilCenter),
+		AnvilButton:                  pos(l.AnvilButton),
+		MainSkillButton:              pos(l.MainSkillButton),
+		SecondarySkillButton:         pos(l.SecondarySkillButton),
+		GambleRefreshButton:          pos(l.GambleRefreshButton),
+		MainSkillListFirstSkill:      pos(l.MainSkillListFirstSkill),
+		SecondarySkillListFirstSkill: pos(l.SecondarySkillListFirstSkill),
+		SkillListSkillOffset:         y(l.SkillListSkillOffset),
+		FirstMercFromContractorList:  pos(l.FirstMercFromContractorList),
+		StashGoldButton:              pos(l.StashGoldButton),
+		StashGoldConfirmButton:       pos(l.StashGoldConfirmButton),
+		SwitchStashTab:               pos(l.SwitchStashTab),
+		SwitchStashTabWidth:          x(l.SwitchStashTabWidth),
+		CloseMiniPanel:               pos(l.CloseMiniPanel),
+		StatButtons: StatButtons{
+			Strength:  pos(l.StatButtons.Strength),
+			Dexterity: pos(l.StatButtons.Dexterity),
+			Vitality:  pos(l.StatButtons.Vitality),
+			Energy:    pos(l.StatButtons.Energy),
+		},
+		OnlineTab:   pos(l.OnlineTab),
+		LobbyButton: pos(l.LobbyButton),
+	}
+	for i, p := range l.SkillTreeTabs {
+		s.SkillTreeTabs[i] = pos(p)
+	}
+	for i, r := range l.SkillTreeRows {
+		s.SkillTreeRows[i] = y(r)
+	}
+	for i, c := range l.SkillTreeColumns {
+		s.SkillTreeColumns[i] = x(c)
+	}
+
+	return s
+}
+
+// LoadProfile reads the profile from {ProfilesDir}/{name}.yaml and replaces the cached one used by CurrentLayout, the
+// cache is only refreshed here so file changes are picked up the next time a supervisor starts. Anchors missing in the
+// file keep the DefaultProfile positions, scaled to the file width and height.
+func LoadProfile(name string) (Profile, error) {
+	if name == "" || name == DefaultProfileName {
+		return DefaultProfile, nil
+	}
+
+	path := filepath.Join(ProfilesDir, name+".yaml")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, fmt.Errorf("error reading layout profile %s: %w", name, err)
+	}
+
+	size := struct {
+		Width  int `yaml:"width"`
+		Height int `yaml:"height"`
+	}{}
+	if err = yaml.Unmarshal(b, &size); err != nil {
+		return Profile{}, fmt.Errorf("error reading layout profile %s: %w", path, err)
+	}
+	if size.Width <= 0 || size.Height <= 0 {
+		return Profile{}, errors.New("layout profile " + path + " must set the width and height the positions were taken at")
+	}
+
+	p := DefaultProfile.Scaled(size.Width, size.Height)
+	if err = yaml.Unmarshal(b, &p); err != nil {
+		return Profile{}, fmt.Errorf("error reading layout profile %s: %w", path, err)
+	}
+	p.Name = name
+
+	profilesMu.Lock()
+	profiles[name] = p
+	profilesMu.Unlock()
+
+	return p, nil
+}
+
+// CurrentLayout returns the layout of the character profile for the current graphics mode and game window size
+func CurrentLayout() Layout {
+	ctx := context.Get()
+
+	return LayoutFor(ctx.Context, ctx.GameReader.LegacyGraphics())
+}
+
+// LayoutFor returns the layout of the character profile for the given graphics mode, for routines not attached to
+// the bot context or while switching between modes
+func LayoutFor(ctx *context.Context, legacyGraphics bool) Layout {
+	p := characterProfile(ctx)
+	width, height := ctx.GameReader.GameAreaSize()
+
+	return p.Layout(legacyGraphics, width, height)
+}
+
+func characterProfile(ctx *context.Context) Profile {
+	name := ctx.CharacterCfg.LayoutProfile
+	if name == "" || name == DefaultProfileName {
+		return DefaultProfile
+	}
+
+	profilesMu.RLock()
+	p, found := profiles[name]
+	profilesMu.RUnlock()
+	if found {
+		return p
+	}
+
+	p, err := LoadProfile(name)
+	if err != nil {
+		ctx.Logger.Warn("Failed to load layout profile, using the default one", slog.String("profile", name), slog.Any("error", err))
+		// Keep using the default one, the warning is logged only once
+		profilesMu.Lock()
+		profiles[name] = DefaultProfile
+		profilesMu.Unlock()
+
+		return DefaultProfile
+	}
+
+	return p
+}
+
+// ProfileNames returns the default profile name followed by the name of every profile file in ProfilesDir
+func ProfileNames() []string {
+	names := []string{DefaultProfileName}
+
+	entries, err := os.ReadDir(ProfilesDir)
+	if err != nil {
+		return names
+	}
+	for _, e := range entries {
+		name, isProfile := strings.CutSuffix(e.Name(), ".yaml")
+		if e.IsDir() || !isProfile || name == DefaultProfileName {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"gopkg.in/yaml.v3"
+)
+
+// The profile shipped as an example must stay in sync with the default one
+func TestDefaultProfileFile(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("..", "..", "config", "layouts", "default.yaml.dist"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := Profile{Name: DefaultProfileName}
+	if err = yaml.Unmarshal(b, &p); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(p, DefaultProfile) {
+		t.Errorf("default.yaml.dist doesn't match DefaultProfile, got %+v", p)
+	}
+}
+
+func TestProfileLayoutScalesToWindow(t *testing.T) {
+	tests := []struct {
+		name           string
+		width, height  int
+		legacy         bool
+		wantRepair     data.Position
+		wantItemBox    int
+		wantSkillRow0  int
+		wantStashTabsW int
+	}{
+		{name: "reference size", width: 1280, height: 720, wantRepair: data.Position{X: 390, Y: 515}, wantItemBox: 33, wantSkillRow0: 190, wantStashTabsW: 82},
+		{name: "unknown size", wantRepair: data.Position{X: 390, Y: 515}, wantItemBox: 33, wantSkillRow0: 190, wantStashTabsW: 82},
+		{name: "1080p", width: 1920, height: 1080, wantRepair: data.Position{X: 585, Y: 773}, wantItemBox: 50, wantSkillRow0: 285, wantStashTabsW: 123},
+		{name: "legacy 1440p", width: 2560, height: 1440, legacy: true, wantRepair: data.Position{X: 1204, Y: 1114}, wantItemBox: 70, wantSkillRow0: 220, wantStashTabsW: 192},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := DefaultProfile.Layout(tt.legacy, tt.width, tt.height)
+			if l.RepairButton != tt.wantRepair {
+				t.Errorf("repair button: got %v, want %v", l.RepairButton, tt.wantRepair)
+			}
+			if l.ItemBoxSize != tt.wantItemBox {
+				t.Errorf("item box size: got %d, want %d", l.ItemBoxSize, tt.wantItemBox)
+			}
+			if l.SkillTreeRows[0] != tt.wantSkillRow0 {
+				t.Errorf("first skill row: got %d, want %d", l.SkillTreeRows[0], tt.wantSkillRow0)
+			}
+			if l.SwitchStashTabWidth != tt.wantStashTabsW {
+				t.Errorf("stash tab width: got %d, want %d", l.SwitchStashTabWidth, tt.wantStashTabsW)
+			}
+		})
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	defer func(old string) { ProfilesDir = old }(ProfilesDir)
+	ProfilesDir = dir
+
+	content := "width: 1920\nheight: 1080\nd2r:\n  onlineTab: {x: 1700, y: 40}\n"
+	if err := os.WriteFile(filepath.Join(dir, "fullhd.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadProfile("fullhd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.D2R.OnlineTab != (data.Position{X: 1700, Y: 40}) {
+		t.Errorf("expected the anchor from the file, got %v", p.D2R.OnlineTab)
+	}
+	// Missing anchors come from the default profile, moved to the file size
+	if p.D2R.RepairButton != (data.Position{X: 585, Y: 773}) {
+		t.Errorf("expected the default repair button scaled to 1080p, got %v", p.D2R.RepairButton)
+	}
+	if l := p.Layout(false, 1920, 1080); l.OnlineTab != p.D2R.OnlineTab {
+		t.Errorf("expected no scaling at the profile size, got %v", l.OnlineTab)
+	}
+
+	if names := ProfileNames(); !reflect.DeepEqual(names, []string{DefaultProfileName, "fullhd"}) {
+		t.Errorf("unexpected profile names %v", names)
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, "nosize.yaml"), []byte("d2r:\n  itemBoxSize: 40\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadProfile("nosize"); err == nil {
+		t.Error("expected an error for a profile without width and height")
+	}
+	if _, err = LoadProfile("missing"); err == nil {
+		t.Error("expected an error for a missing profile")
+	}
+}
@@ -3,54 +3,24 @@ package ui
 import (
 	"github.com/hectorgimenez/d2go/pkg/data"
 	"github.com/hectorgimenez/d2go/pkg/data/item"
-	"github.com/hectorgimenez/koolo/internal/context"
 )
 
 func GetScreenCoordsForItem(itm data.Item) data.Position {
-	ctx := context.Get()
-	if ctx.GameReader.LegacyGraphics() {
-		return getScreenCoordsForItemClassic(itm)
-	}
-
-	return getScreenCoordsForItem(itm)
+	return CurrentLayout().ItemScreenCoords(itm)
 }
 
-func getScreenCoordsForItem(itm data.Item) data.Position {
+// ItemScreenCoords returns the screen position of the center of the item box
+func (l Layout) ItemScreenCoords(itm data.Item) data.Position {
+	topLeft := l.InventoryTopLeft
 	switch itm.Location.LocationType {
 	case item.LocationVendor, item.LocationStash, item.LocationSharedStash:
-		x := topCornerVendorWindowX + itm.Position.X*itemBoxSize + (itemBoxSize / 2)
-		y := topCornerVendorWindowY + itm.Position.Y*itemBoxSize + (itemBoxSize / 2)
-
-		return data.Position{X: x, Y: y}
+		topLeft = l.VendorWindowTopLeft
 	case item.LocationCube:
-		x := topCornerCubeWindowX + itm.Position.X*itemBoxSize + (itemBoxSize / 2)
-		y := topCornerCubeWindowY + itm.Position.Y*itemBoxSize + (itemBoxSize / 2)
-
-		return data.Position{X: x, Y: y}
-	}
-
-	x := inventoryTopLeftX + itm.Position.X*itemBoxSize + (itemBoxSize / 2)
-	y := inventoryTopLeftY + itm.Position.Y*itemBoxSize + (itemBoxSize / 2)
-
-	return data.Position{X: x, Y: y}
-}
-
-func getScreenCoordsForItemClassic(itm data.Item) data.Position {
-	switch itm.Location.LocationType {
-	case item.LocationVendor, item.LocationStash, item.LocationSharedStash:
-		x := topCornerVendorWindowXClassic + itm.Position.X*itemBoxSizeClassic + (itemBoxSizeClassic / 2)
-		y := topCornerVendorWindowYClassic + itm.Position.Y*itemBoxSizeClassic + (itemBoxSizeClassic / 2)
-
-		return data.Position{X: x, Y: y}
-	case item.LocationCube:
-		x := topCornerCubeWindowXClassic + itm.Position.X*itemBoxSizeClassic + (itemBoxSizeClassic / 2)
-		y := topCornerCubeWindowYClassic + itm.Position.Y*itemBoxSizeClassic + (itemBoxSizeClassic / 2)
-
-		return data.Position{X: x, Y: y}
+		topLeft = l.CubeWindowTopLeft
 	}
 
-	x := inventoryTopLeftXClassic + itm.Position.X*itemBoxSizeClassic + (itemBoxSizeClassic / 2)
-	y := inventoryTopLeftYClassic + itm.Position.Y*itemBoxSizeClassic + (itemBoxSizeClassic / 2)
+	x := topLeft.X + itm.Position.X*l.ItemBoxSize + (l.ItemBoxSize / 2)
+	y := topLeft.Y + itm.Position.Y*l.ItemBoxSize + (l.ItemBoxSize / 2)
 
 	return data.Position{X: x, Y: y}
 }
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package utils
+
+import (
+	"fmt"
+	"os"
+)
+
+// HasAdminPermission checks for root, there is nothing like the Windows physical drive check on other systems
+func HasAdminPermission() bool {
+	return os.Geteuid() == 0
+}
+
+// ShowDialog writes the message to stderr, there are no message boxes outside Windows
+func ShowDialog(title, message string) {
+	fmt.Fprintf(os.Stderr, "%s: %s\n", title, message)
+}
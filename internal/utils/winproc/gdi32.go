@@ -1,3 +1,5 @@
+//go:build windows
+
 package winproc
 
 import "golang.org/x/sys/windows"
@@ -1,3 +1,5 @@
+//go:build windows
+
 package winproc
 
 import "golang.org/x/sys/windows"
@@ -5,9 +7,11 @@ import "golang.org/x/sys/windows"
 const (
 	EXECUTION_STATE_ES_DISPLAY_REQUIRED = 0x00000002
 	EXECUTION_STATE_ES_CONTINUOUS       = 0x80000000
+	ATTACH_PARENT_PROCESS               = ^uintptr(0)
 )
 
 var (
 	KERNEL32                = windows.NewLazySystemDLL("kernel32.dll")
 	SetThreadExecutionState = KERNEL32.NewProc("SetThreadExecutionState")
+	AttachConsole           = KERNEL32.NewProc("AttachConsole")
 )
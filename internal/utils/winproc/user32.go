@@ -1,3 +1,5 @@
+//go:build windows
+
 package winproc
 
 import "golang.org/x/sys/windows"
@@ -11,4 +13,5 @@ var (
 	GetKeyState        = USER32.NewProc("GetKeyState")
 	GetWindowText      = USER32.NewProc("GetWindowTextW")
 	MapVirtualKey      = USER32.NewProc("MapVirtualKeyW")
+	IsHungAppWindow    = USER32.NewProc("IsHungAppWindow")
 )